@@ -0,0 +1,9 @@
+#!/bin/bash
+
+# Needs protoc, protoc-gen-go and protoc-gen-go-grpc on PATH:
+#   go install google.golang.org/protobuf/cmd/protoc-gen-go@v1.36.10
+#   go install google.golang.org/grpc/cmd/protoc-gen-go-grpc@v1.5.1
+protoc -I webapp/backend/proto \
+  --go_out=webapp/backend --go_opt=module=dexbe \
+  --go-grpc_out=webapp/backend --go-grpc_opt=module=dexbe \
+  webapp/backend/proto/dex.proto
@@ -0,0 +1,76 @@
+// auditverify checks the hash chain of an engine audit log:
+//
+//	go run ./cmd/auditverify [-signer address] [-head seq:hash] [path]
+//
+// path defaults to ENGINE_AUDIT_LOG, then engine_audit.log. -signer requires every anchor to be
+// signed by the operator address. -head takes a head kept outside the log, such as one printed by
+// an earlier run, and requires the log to still hold it. It exits non-zero at the first record
+// that does not verify.
+package main
+
+import (
+	"dexbe/internal/infra/audit"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+func main() {
+	signerFlag := flag.String("signer", "", "operator address every anchor must be signed by")
+	headFlag := flag.String("head", "", "seq:hash of a record the log must contain")
+	flag.Parse()
+
+	var opts audit.VerifyOptions
+	if *signerFlag != "" {
+		if !common.IsHexAddress(*signerFlag) {
+			fmt.Fprintf(os.Stderr, "-signer %q is not an address\n", *signerFlag)
+			os.Exit(2)
+		}
+		opts.Signer = common.HexToAddress(*signerFlag)
+	}
+	if *headFlag != "" {
+		head, err := audit.ParseHead(*headFlag)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "-head: %v\n", err)
+			os.Exit(2)
+		}
+		opts.Head = head
+	}
+
+	path := os.Getenv("ENGINE_AUDIT_LOG")
+	if flag.NArg() > 0 {
+		path = flag.Arg(0)
+	}
+	if path == "" {
+		path = "engine_audit.log"
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		os.Exit(2)
+	}
+	defer file.Close()
+
+	verified, err := audit.Verify(file, opts)
+	if err != nil {
+		if verified.Last != nil {
+			fmt.Fprintf(os.Stderr, "%s: chain intact through seq %d, then: %v\n", path, verified.Last.Seq, err)
+		} else {
+			fmt.Fprintf(os.Stderr, "%s: %v\n", path, err)
+		}
+		os.Exit(1)
+	}
+	if verified.Last == nil {
+		fmt.Printf("%s: empty\n", path)
+		return
+	}
+	fmt.Printf("%s: %d records verified, head %d:%s\n", path, verified.Last.Seq, verified.Last.Seq, verified.Last.Hash)
+	if verified.LastAnchor == nil {
+		fmt.Printf("%s: no anchors, the chain is not signed\n", path)
+		return
+	}
+	fmt.Printf("%s: last anchor at seq %d, %d records after it\n", path, verified.LastAnchor.Seq, verified.Last.Seq-verified.LastAnchor.Seq)
+}
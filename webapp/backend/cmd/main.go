@@ -2,42 +2,100 @@ package main
 
 import (
 	"context"
+	"dexbe/config"
+	"dexbe/internal/domains/admin"
+	"dexbe/internal/domains/auth"
+	"dexbe/internal/domains/candle"
 	"dexbe/internal/domains/nonce"
 	"dexbe/internal/domains/orderbook"
+	"dexbe/internal/domains/permit"
 	"dexbe/internal/domains/registry"
+	"dexbe/internal/domains/ticker"
 	"dexbe/internal/infra/api"
 	"dexbe/internal/infra/api/controllers"
 	"dexbe/internal/infra/api/routers"
+	"dexbe/internal/infra/audit"
 	"dexbe/internal/infra/eth"
 	"dexbe/internal/infra/eth/exchange"
 	registryC "dexbe/internal/infra/eth/registry"
+	"dexbe/internal/infra/fix"
+	"dexbe/internal/infra/metrics"
+	"dexbe/internal/infra/rpc"
+	"dexbe/internal/infra/tracing"
 	//"dexbe/internal/infra/eth/token"
 	"log"
 	"os"
+	"os/signal"
 	"strconv"
-	"time"
+	"syscall"
 
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/joho/godotenv"
 
 	"github.com/labstack/echo/v4"
 	"github.com/labstack/echo/v4/middleware"
+	"google.golang.org/grpc/credentials"
 )
 
 func main() {
 	godotenv.Load()
+	cfg, err := config.Load(os.Args[1:])
+	if err != nil {
+		log.Fatal(err)
+	}
+	// Settings the config package leaves to the packages that read them, parsed before anything starts
+	limitConfig, err := api.ParseRateLimits(cfg.Server.RateLimits, api.DefaultRateLimits())
+	if err != nil {
+		log.Fatalf("invalid RATE_LIMITS: %v", err)
+	}
+	var fixSessions map[string]fix.SessionConfig
+	if cfg.Server.FIXSessions != "" {
+		fixSessions, err = fix.ParseSessions(cfg.Server.FIXSessions)
+		if err != nil {
+			log.Fatalf("invalid FIX sessions: %v", err)
+		}
+	}
+	api.ConfigureWebsocket(cfg.Websocket.SendQueue, cfg.Websocket.WriteWait.Duration, cfg.Websocket.PongWait.Duration, cfg.Websocket.MaxMessageSize)
+
 	e := echo.New()
-	e.Use(middleware.Logger())
+	e.IPExtractor, err = api.IPExtractor(cfg.Server.TrustedProxies)
+	if err != nil {
+		log.Fatalf("invalid TRUSTED_PROXIES: %v", err)
+	}
+	e.Use(api.AccessLogger())
 	e.Use(middleware.Recover())
 	e.Use(middleware.CORS())
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 
-	chainId := "31337"
-	deployerPrivateKey := "0xac0974bec39a17e36ba4a6b4d238ff944bacb478cbed5efcae784d7bf4f2ff80"
-	host := "http://localhost:8545"
-	exchangeAddr := os.Getenv("EXCHANGE")
-	registryAddr := os.Getenv("TOKENREGISTRY")
-	ethClient := eth.GetEthClient(chainId, host, deployerPrivateKey)
+	shutdownTracing, err := tracing.Setup(ctx, cfg.Server.TraceExporter, cfg.Server.TraceFile)
+	if err != nil {
+		log.Fatalf("failed to set up tracing: %v", err)
+	}
+	defer shutdownTracing(context.Background())
+
+	engineAudit, err := audit.Open(cfg.Server.EngineAuditLog)
+	if err != nil {
+		log.Fatalf("failed to open engine audit log: %v", err)
+	}
+	defer engineAudit.Close()
+
+	exchangeAddr := cfg.Contracts.Exchange
+	registryAddr := cfg.Contracts.TokenRegistry
+	signer, err := openSigner(cfg.Signer)
+	if err != nil {
+		log.Fatalf("failed to open signer: %v", err)
+	}
+	if cfg.Server.AuditAnchor.Duration > 0 {
+		engineAudit.StartAnchoring(ctx, cfg.Server.AuditAnchor.Duration, signer)
+		defer func() {
+			// Anchor the records of the final cycle and shutdown too
+			if err := engineAudit.Anchor(signer); err != nil {
+				log.Printf("**Audit Error**: %v", err)
+			}
+		}()
+	}
+	ethClient := eth.GetEthClient(strconv.FormatInt(cfg.Chain.ID, 10), cfg.Chain.RPCURL, signer)
 	registryContract := registryC.NewRegistryContract(ethClient, registryAddr)
 	exchangeContract := exchange.NewExchangeContract(ethClient, exchangeAddr)
 
@@ -47,23 +105,116 @@ func main() {
 	registryStore.Build(&allTokens)
 	allSymbols := registryStore.GetAllSymbols()
 	orderbs := orderbook.NewOrderBookStore(exchangeContract, allSymbols)
-	pollingInterval := 50 * time.Millisecond
-	orderbs.StartOracle(ctx, pollingInterval)
+	orderbs.SetRingMatchingEnabled(cfg.Matching.RingMatching)
+	orderbs.SetMaxRingDepth(cfg.Matching.MaxRingDepth)
+	if cfg.Server.StateFile != "" {
+		if err := orderbs.LoadState(cfg.Server.StateFile); err != nil {
+			log.Fatalf("failed to load engine state: %v", err)
+		}
+	}
+	orderbs.StartOracle(ctx, cfg.Matching.Interval.Duration)
 
 	api.StartBroadcast()
 
+	candles := candle.NewAggregator()
+	candles.Rebuild(orderbs.Trades)
+	orderbs.Trades.OnTrade(candles.AddTrade)
+	tickers := ticker.NewTickerService(orderbs)
+	tickers.Rebuild(orderbs.Trades)
+	orderbs.Trades.OnTrade(tickers.AddTrade)
+
+	metrics.Registry.MustRegister(orderbs.Collector())
+	metrics.RegisterQueue("global", api.GlobalQueueLength)
+	metrics.RegisterQueue("trades", orderbs.Trades.QueueLength)
+	metrics.RegisterQueue("candles", candles.QueueLength)
+
 	noncer := nonce.NewNonceRegistry()
-	convChainId, _ := strconv.Atoi(chainId)
-	globalCtrl := controller.NewGlobalController()
-	orderCtrl := controller.NewOrderController(orderbs, convChainId, exchangeAddr, registryStore)
-	nonceCtrl := controller.NewNonceController(noncer)
+	convChainId := int(cfg.Chain.ID)
+	sessions := auth.NewSessionStore(convChainId)
+	rateLimits := api.NewRateLimits(limitConfig, sessions)
+
+	globalCtrl := controller.NewGlobalController(orderbs, sessions, rateLimits)
+	authCtrl := controller.NewAuthController(sessions)
+
+	admins := auth.NewAdminSet()
+	if owner, err := registryContract.Owner(); err == nil {
+		admins.Add(owner, "registry owner")
+	} else {
+		log.Printf("Could not read registry owner: %v", err)
+	}
+	if owner, err := exchangeContract.Owner(); err == nil {
+		admins.Add(owner, "exchange owner")
+	} else {
+		log.Printf("Could not read exchange owner: %v", err)
+	}
+	for _, addr := range cfg.Server.AdminAddresses {
+		admins.Add(common.HexToAddress(addr), "configured admin")
+	}
+	auditTrail, err := admin.NewAuditTrail(cfg.Server.AdminAuditLog)
+	if err != nil {
+		log.Fatalf("failed to open admin audit trail: %v", err)
+	}
+	defer auditTrail.Close()
+	adminCtrl := controller.NewAdminController(orderbs, admins, auditTrail)
+	permitService := permit.NewPermitService(registryStore, convChainId, exchangeAddr)
+	orderCtrl := controller.NewOrderController(orderbs, convChainId, exchangeAddr, registryStore, permitService, rateLimits)
+	nonceCtrl := controller.NewNonceController(noncer, rateLimits)
 	tokenCtrl := controller.NewTokenController(registryContract, orderbs, registryStore)
-	orderBookCtrl := controller.NewOrderBookController(orderbs)
+	orderBookCtrl := controller.NewOrderBookController(orderbs, sessions, rateLimits)
+	permitCtrl := controller.NewPermitController(permitService, rateLimits)
+	tradeCtrl := controller.NewTradeController(orderbs, rateLimits)
+	candleCtrl := controller.NewCandleController(orderbs, candles, rateLimits)
+	tickerCtrl := controller.NewTickerController(tickers, rateLimits)
+	priceFeed := orderbook.NewPriceFeed(orderbs)
+	marketCtrl := controller.NewMarketController(orderbs, priceFeed, rateLimits)
+	streamCtrl := controller.NewStreamController(orderbs, candles, tickers, priceFeed, sessions, rateLimits)
+	healthCtrl := controller.NewHealthController(orderbs, ethClient, exchangeAddr, registryAddr, controller.HealthThresholds{
+		MaxBlockAge:        cfg.Health.MaxBlockAge.Duration,
+		MinOperatorBalance: cfg.Health.MinOperatorBalanceWei(),
+		MatcherStaleAfter:  cfg.Health.MatcherStaleAfter.Duration,
+		StuckAfter:         cfg.Health.StuckAfter.Duration,
+	})
+
+	router.RegisterAllRoutes(e, rateLimits, api.RequireSession(sessions), api.RequireAdmin(admins, auditTrail), globalCtrl, authCtrl, orderCtrl, orderBookCtrl, nonceCtrl, tokenCtrl, permitCtrl, adminCtrl, tradeCtrl, candleCtrl, tickerCtrl, marketCtrl, streamCtrl, healthCtrl)
 
-	router.RegisterAllRoutes(e, globalCtrl, orderCtrl, orderBookCtrl, nonceCtrl, tokenCtrl)
+	var grpcCreds credentials.TransportCredentials
+	if cfg.Server.GRPCTLSCert != "" {
+		grpcCreds, err = credentials.NewServerTLSFromFile(cfg.Server.GRPCTLSCert, cfg.Server.GRPCTLSKey)
+		if err != nil {
+			log.Fatalf("failed to load gRPC TLS certificate: %v", err)
+		}
+	} else {
+		log.Println("**Warning**: gRPC is plaintext, session tokens need a TLS proxy or a private network in front of it")
+	}
+	grpcServer := rpc.NewServer(orderCtrl, orderbs, registryStore, sessions, rateLimits, grpcCreds)
+	go func() {
+		if err := grpcServer.ListenAndServe(cfg.Server.GRPCAddr); err != nil {
+			log.Printf("gRPC server stopped: %v", err)
+		}
+	}()
+
+	var acceptor *fix.Acceptor
+	if fixSessions != nil {
+		acceptor = fix.NewAcceptor(cfg.Server.FIXCompID, fixSessions, orderbs, orderCtrl)
+		go func() {
+			if err := acceptor.ListenAndServe(cfg.Server.FIXAddr); err != nil {
+				log.Printf("FIX acceptor stopped: %v", err)
+			}
+		}()
+	}
 
-	log.Println("Starting server on :11223")
-	if err := e.Start(":11223"); err != nil {
-		log.Fatalf("failed to start server: %v", err)
+	signals, stopSignals := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stopSignals()
+	serverErr := make(chan error, 1)
+	go func() {
+		log.Printf("Starting server on %s (chain %d)", cfg.Server.Addr, cfg.Chain.ID)
+		serverErr <- e.Start(cfg.Server.Addr)
+	}()
+	select {
+	case <-signals.Done():
+		log.Println("**Shutdown**: signal received")
+	case err := <-serverErr:
+		log.Printf("**Shutdown**: server stopped: %v", err)
 	}
+	shutdown(cfg.Server, orderbs, cancel, e, grpcServer, acceptor)
 }
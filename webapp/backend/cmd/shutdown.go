@@ -0,0 +1,51 @@
+package main
+
+import (
+	"context"
+	"dexbe/config"
+	"dexbe/internal/domains/orderbook"
+	"dexbe/internal/infra/api"
+	"dexbe/internal/infra/fix"
+	"dexbe/internal/infra/rpc"
+	"log"
+
+	"github.com/labstack/echo/v4"
+)
+
+// shutdown stops taking orders, stops the oracle and waits for the settlements already submitted,
+// closes every listener and connection, then saves the engine state. Waiting for receipts and
+// closing connections each get the configured timeout. The audit logs and tracing are flushed by
+// main's deferred calls once this returns.
+func shutdown(cfg config.ServerConfig, orderbs *orderbook.OrderBookStore, stopOracle context.CancelFunc, e *echo.Echo, grpcServer *rpc.Server, acceptor *fix.Acceptor) {
+	orderbs.StopAccepting()
+	stopOracle()
+
+	drainCtx, cancelDrain := context.WithTimeout(context.Background(), cfg.ShutdownTimeout.Duration)
+	defer cancelDrain()
+	if err := orderbs.Drain(drainCtx); err != nil {
+		log.Printf("**Shutdown**: %v", err)
+	}
+	closeCtx, cancelClose := context.WithTimeout(context.Background(), cfg.ShutdownTimeout.Duration)
+	defer cancelClose()
+	if acceptor != nil {
+		if err := acceptor.Shutdown(closeCtx); err != nil {
+			log.Printf("**Shutdown**: FIX sessions did not log out: %v", err)
+		}
+	}
+	if err := grpcServer.Shutdown(closeCtx); err != nil {
+		log.Printf("**Shutdown**: gRPC calls cut off: %v", err)
+	}
+	if err := e.Shutdown(closeCtx); err != nil {
+		log.Printf("**Shutdown**: HTTP requests cut off: %v", err)
+	}
+	if err := api.CloseAllClients(closeCtx); err != nil {
+		log.Printf("**Shutdown**: websockets did not close: %v", err)
+	}
+	// Cancels are honoured until the listeners are down, so the state is only final now
+	if cfg.StateFile != "" {
+		if err := orderbs.SaveState(cfg.StateFile); err != nil {
+			log.Printf("**Shutdown**: failed to save engine state: %v", err)
+		}
+	}
+	log.Println("**Shutdown**: complete")
+}
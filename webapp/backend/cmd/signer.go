@@ -0,0 +1,27 @@
+package main
+
+import (
+	"dexbe/config"
+	"dexbe/internal/infra/eth"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// openSigner builds the configured signer. Keystores are decrypted and external signers contacted here.
+func openSigner(signer config.SignerConfig) (eth.Signer, error) {
+	switch signer.Type {
+	case config.SignerKey:
+		return eth.NewKeySigner(signer.PrivateKey)
+	case config.SignerKeystore:
+		return eth.NewKeystoreSigner(signer.Keystore, signer.PassphraseFile)
+	case config.SignerExternal:
+		address := common.Address{}
+		if signer.Address != "" {
+			address = common.HexToAddress(signer.Address)
+		}
+		return eth.NewExternalSigner(signer.Endpoint, address)
+	default:
+		return nil, fmt.Errorf("unknown signer type %q", signer.Type)
+	}
+}
@@ -1 +1,481 @@
 package config
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"math/big"
+	"net"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// hardhatDeployerKey is the first account of every Hardhat and Anvil node. It is the default signer
+// for local development and refused on any other chain.
+const (
+	hardhatDeployerKey = "0xac0974bec39a17e36ba4a6b4d238ff944bacb478cbed5efcae784d7bf4f2ff80"
+	localChainID       = 31337
+)
+
+// Duration reads "50ms"-style strings in config files
+type Duration struct {
+	time.Duration
+}
+
+func (d Duration) MarshalJSON() ([]byte, error) {
+	return json.Marshal(d.String())
+}
+
+func (d *Duration) UnmarshalJSON(data []byte) error {
+	var text string
+	if err := json.Unmarshal(data, &text); err != nil {
+		return fmt.Errorf("duration must be a string such as \"50ms\": %w", err)
+	}
+	parsed, err := time.ParseDuration(text)
+	if err != nil {
+		return err
+	}
+	d.Duration = parsed
+	return nil
+}
+
+type ChainConfig struct {
+	ID     int64  `json:"id"`
+	RPCURL string `json:"rpcUrl"`
+}
+
+type ContractsConfig struct {
+	Exchange      string `json:"exchange"`
+	TokenRegistry string `json:"tokenRegistry"`
+}
+
+// Signer kinds
+const (
+	SignerKey      = "key"      // raw PrivateKey, for development
+	SignerKeystore = "keystore" // encrypted Keystore file unlocked with PassphraseFile
+	SignerExternal = "external" // clef-compatible signer at Endpoint
+)
+
+// SignerConfig picks how the engine signs settlement transactions
+type SignerConfig struct {
+	Type           string `json:"type"`
+	PrivateKey     string `json:"privateKey,omitempty"`
+	Keystore       string `json:"keystore,omitempty"`
+	PassphraseFile string `json:"passphraseFile,omitempty"`
+	Endpoint       string `json:"endpoint,omitempty"` // IPC path or http(s) URL
+	Address        string `json:"address,omitempty"`  // account to use at Endpoint, its first when empty
+}
+
+type ServerConfig struct {
+	Addr            string   `json:"addr"`
+	GRPCAddr        string   `json:"grpcAddr"`
+	GRPCTLSCert     string   `json:"grpcTlsCert"` // with GRPCTLSKey serves gRPC over TLS; without, keep it behind a TLS proxy or on a private network
+	GRPCTLSKey      string   `json:"grpcTlsKey"`
+	FIXAddr         string   `json:"fixAddr"` // plaintext FIX, keep it behind a TLS proxy or on a private network
+	FIXCompID       string   `json:"fixCompId"`
+	FIXSessions     string   `json:"fixSessions"`    // SenderCompID=address:password,...; FIX is off when empty
+	RateLimits      string   `json:"rateLimits"`     // overrides of api.DefaultRateLimits, as api.ParseRateLimits reads them
+	TrustedProxies  []string `json:"trustedProxies"` // CIDR ranges whose X-Forwarded-For is believed; none trusts only the peer
+	AdminAddresses  []string `json:"adminAddresses"`
+	AdminAuditLog   string   `json:"adminAuditLog"`
+	EngineAuditLog  string   `json:"engineAuditLog"`
+	AuditAnchor     Duration `json:"auditAnchor"`   // how often the operator signs the audit log head, 0 never
+	TraceExporter   string   `json:"traceExporter"` // "", "file" or "otlp"
+	TraceFile       string   `json:"traceFile"`
+	StateFile       string   `json:"stateFile"` // books and history across restarts, not kept when empty
+	ShutdownTimeout Duration `json:"shutdownTimeout"`
+}
+
+type MatchingConfig struct {
+	Interval     Duration `json:"interval"`
+	RingMatching bool     `json:"ringMatching"`
+	MaxRingDepth int      `json:"maxRingDepth"`
+}
+
+// HealthConfig sets when /readyz and /healthz report a problem
+type HealthConfig struct {
+	MaxBlockAge        Duration `json:"maxBlockAge"`        // 0 reports the head block's age without judging it
+	MinOperatorBalance string   `json:"minOperatorBalance"` // wei
+	MatcherStaleAfter  Duration `json:"matcherStaleAfter"`
+	StuckAfter         Duration `json:"stuckAfter"`
+}
+
+type WebsocketConfig struct {
+	SendQueue      int      `json:"sendQueue"`
+	WriteWait      Duration `json:"writeWait"`
+	PongWait       Duration `json:"pongWait"`
+	MaxMessageSize int64    `json:"maxMessageSize"`
+}
+
+type Config struct {
+	Chain     ChainConfig     `json:"chain"`
+	Contracts ContractsConfig `json:"contracts"`
+	Signer    SignerConfig    `json:"signer"`
+	Server    ServerConfig    `json:"server"`
+	Matching  MatchingConfig  `json:"matching"`
+	Websocket WebsocketConfig `json:"websocket"`
+	Health    HealthConfig    `json:"health"`
+}
+
+// Default is a local Hardhat node on 31337 with the settings the backend has always run with
+func Default() *Config {
+	return &Config{
+		Chain:  ChainConfig{ID: localChainID, RPCURL: "http://localhost:8545"},
+		Signer: SignerConfig{Type: SignerKey, PrivateKey: hardhatDeployerKey},
+		Server: ServerConfig{
+			Addr:            ":11223",
+			GRPCAddr:        ":11224",
+			FIXAddr:         ":9878",
+			FIXCompID:       "SMASHDEX",
+			AdminAuditLog:   "admin_audit.log",
+			EngineAuditLog:  "engine_audit.log",
+			AuditAnchor:     Duration{time.Minute},
+			TraceFile:       "traces.jsonl",
+			StateFile:       "engine_state.json",
+			ShutdownTimeout: Duration{30 * time.Second},
+		},
+		Matching: MatchingConfig{Interval: Duration{50 * time.Millisecond}, RingMatching: true, MaxRingDepth: 5},
+		Websocket: WebsocketConfig{
+			SendQueue:      256,
+			WriteWait:      Duration{10 * time.Second},
+			PongWait:       Duration{60 * time.Second},
+			MaxMessageSize: 64 * 1024,
+		},
+		Health: HealthConfig{
+			MinOperatorBalance: "10000000000000000", // 0.01 ETH
+			MatcherStaleAfter:  Duration{5 * time.Second},
+			StuckAfter:         Duration{2 * time.Minute},
+		},
+	}
+}
+
+// setting is one value that can come from the environment or a flag, which override the file
+type setting struct {
+	env   string
+	flag  string
+	usage string
+	set   func(cfg *Config, value string) error
+}
+
+func stringSetting(env, flagName, usage string, field func(*Config) *string) setting {
+	return setting{env, flagName, usage, func(cfg *Config, value string) error {
+		*field(cfg) = value
+		return nil
+	}}
+}
+
+func intSetting(env, flagName, usage string, field func(*Config) *int) setting {
+	return setting{env, flagName, usage, func(cfg *Config, value string) error {
+		parsed, err := strconv.Atoi(value)
+		if err != nil {
+			return fmt.Errorf("not an integer: %q", value)
+		}
+		*field(cfg) = parsed
+		return nil
+	}}
+}
+
+func durationSetting(env, flagName, usage string, field func(*Config) *Duration) setting {
+	return setting{env, flagName, usage, func(cfg *Config, value string) error {
+		parsed, err := time.ParseDuration(value)
+		if err != nil {
+			return err
+		}
+		field(cfg).Duration = parsed
+		return nil
+	}}
+}
+
+var settings = []setting{
+	{"CHAIN_ID", "chain-id", "chain id the exchange is deployed on", func(cfg *Config, value string) error {
+		parsed, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return fmt.Errorf("not an integer: %q", value)
+		}
+		cfg.Chain.ID = parsed
+		return nil
+	}},
+	stringSetting("RPC_URL", "rpc-url", "JSON-RPC endpoint of the chain", func(cfg *Config) *string { return &cfg.Chain.RPCURL }),
+	stringSetting("EXCHANGE", "exchange", "exchange contract address", func(cfg *Config) *string { return &cfg.Contracts.Exchange }),
+	stringSetting("TOKENREGISTRY", "token-registry", "token registry contract address", func(cfg *Config) *string { return &cfg.Contracts.TokenRegistry }),
+	stringSetting("SIGNER", "signer", "how settlement transactions are signed: key, keystore or external", func(cfg *Config) *string { return &cfg.Signer.Type }),
+	stringSetting("SIGNER_PRIVATE_KEY", "signer-private-key", "hex private key of the key signer, for development", func(cfg *Config) *string { return &cfg.Signer.PrivateKey }),
+	stringSetting("SIGNER_KEYSTORE", "signer-keystore", "encrypted keystore file of the keystore signer", func(cfg *Config) *string { return &cfg.Signer.Keystore }),
+	stringSetting("SIGNER_PASSPHRASE_FILE", "signer-passphrase-file", "file holding the keystore passphrase", func(cfg *Config) *string { return &cfg.Signer.PassphraseFile }),
+	stringSetting("SIGNER_ENDPOINT", "signer-endpoint", "IPC path or URL of the external signer", func(cfg *Config) *string { return &cfg.Signer.Endpoint }),
+	stringSetting("SIGNER_ADDRESS", "signer-address", "account of the external signer to use", func(cfg *Config) *string { return &cfg.Signer.Address }),
+	stringSetting("HTTP_ADDR", "addr", "REST and websocket listen address", func(cfg *Config) *string { return &cfg.Server.Addr }),
+	stringSetting("GRPC_ADDR", "grpc-addr", "gRPC listen address", func(cfg *Config) *string { return &cfg.Server.GRPCAddr }),
+	stringSetting("GRPC_TLS_CERT", "grpc-tls-cert", "PEM certificate for gRPC over TLS; without one gRPC is plaintext and belongs behind TLS or on a private network", func(cfg *Config) *string { return &cfg.Server.GRPCTLSCert }),
+	stringSetting("GRPC_TLS_KEY", "grpc-tls-key", "PEM private key of the gRPC certificate", func(cfg *Config) *string { return &cfg.Server.GRPCTLSKey }),
+	stringSetting("FIX_ADDR", "fix-addr", "FIX acceptor listen address", func(cfg *Config) *string { return &cfg.Server.FIXAddr }),
+	stringSetting("FIX_COMP_ID", "fix-comp-id", "our FIX SenderCompID", func(cfg *Config) *string { return &cfg.Server.FIXCompID }),
+	stringSetting("FIX_SESSIONS", "fix-sessions", "FIX counterparties as SenderCompID=address:password,...; serve FIX only behind TLS or on a private network", func(cfg *Config) *string { return &cfg.Server.FIXSessions }),
+	stringSetting("RATE_LIMITS", "rate-limits", "rate limit overrides such as order.ip=10/20", func(cfg *Config) *string { return &cfg.Server.RateLimits }),
+	{"TRUSTED_PROXIES", "trusted-proxies", "comma separated CIDR ranges of reverse proxies whose X-Forwarded-For is trusted", func(cfg *Config, value string) error {
+		cfg.Server.TrustedProxies = nil
+		for _, proxy := range strings.Split(value, ",") {
+			if proxy = strings.TrimSpace(proxy); proxy != "" {
+				cfg.Server.TrustedProxies = append(cfg.Server.TrustedProxies, proxy)
+			}
+		}
+		return nil
+	}},
+	{"ADMIN_ADDRESSES", "admin-addresses", "comma separated admin addresses besides the contract owners", func(cfg *Config, value string) error {
+		cfg.Server.AdminAddresses = nil
+		for _, addr := range strings.Split(value, ",") {
+			if addr = strings.TrimSpace(addr); addr != "" {
+				cfg.Server.AdminAddresses = append(cfg.Server.AdminAddresses, addr)
+			}
+		}
+		return nil
+	}},
+	stringSetting("ADMIN_AUDIT_LOG", "admin-audit-log", "file admin actions are appended to", func(cfg *Config) *string { return &cfg.Server.AdminAuditLog }),
+	stringSetting("ENGINE_AUDIT_LOG", "engine-audit-log", "hash-chained engine audit log", func(cfg *Config) *string { return &cfg.Server.EngineAuditLog }),
+	durationSetting("ENGINE_AUDIT_ANCHOR", "engine-audit-anchor", "how often the operator signs the head of the engine audit log, 0 never", func(cfg *Config) *Duration { return &cfg.Server.AuditAnchor }),
+	stringSetting("TRACE_EXPORTER", "trace-exporter", "order tracing exporter: file or otlp", func(cfg *Config) *string { return &cfg.Server.TraceExporter }),
+	stringSetting("TRACE_FILE", "trace-file", "file the file trace exporter writes to", func(cfg *Config) *string { return &cfg.Server.TraceFile }),
+	stringSetting("STATE_FILE", "state-file", "file the books and history are saved to at shutdown and loaded from at start", func(cfg *Config) *string { return &cfg.Server.StateFile }),
+	durationSetting("SHUTDOWN_TIMEOUT", "shutdown-timeout", "how long shutdown waits for settlement receipts, and then for connections to close", func(cfg *Config) *Duration { return &cfg.Server.ShutdownTimeout }),
+	durationSetting("MATCH_INTERVAL", "match-interval", "how often the books are matched", func(cfg *Config) *Duration { return &cfg.Matching.Interval }),
+	{"RING_MATCHING", "ring-matching", "match rings across books", func(cfg *Config, value string) error {
+		parsed, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("not a boolean: %q", value)
+		}
+		cfg.Matching.RingMatching = parsed
+		return nil
+	}},
+	intSetting("MAX_RING_DEPTH", "max-ring-depth", "most orders in a ring", func(cfg *Config) *int { return &cfg.Matching.MaxRingDepth }),
+	intSetting("WS_SEND_QUEUE", "ws-send-queue", "messages a websocket may fall behind by before it is disconnected", func(cfg *Config) *int { return &cfg.Websocket.SendQueue }),
+	durationSetting("WS_WRITE_WAIT", "ws-write-wait", "deadline of a single websocket write", func(cfg *Config) *Duration { return &cfg.Websocket.WriteWait }),
+	durationSetting("WS_PONG_WAIT", "ws-pong-wait", "how long a silent websocket is kept", func(cfg *Config) *Duration { return &cfg.Websocket.PongWait }),
+	durationSetting("HEALTH_MAX_BLOCK_AGE", "health-max-block-age", "head block age at which the node counts as lagging, 0 to only report it", func(cfg *Config) *Duration { return &cfg.Health.MaxBlockAge }),
+	stringSetting("HEALTH_MIN_OPERATOR_BALANCE", "health-min-operator-balance", "wei the operator needs to be ready", func(cfg *Config) *string { return &cfg.Health.MinOperatorBalance }),
+	durationSetting("HEALTH_MATCHER_STALE_AFTER", "health-matcher-stale-after", "matching cycle age at which the matcher counts as stuck", func(cfg *Config) *Duration { return &cfg.Health.MatcherStaleAfter }),
+	durationSetting("HEALTH_STUCK_AFTER", "health-stuck-after", "how long a settlement may wait for its receipt before it counts as stuck", func(cfg *Config) *Duration { return &cfg.Health.StuckAfter }),
+	{"WS_MAX_MESSAGE_SIZE", "ws-max-message-size", "largest inbound websocket message in bytes", func(cfg *Config, value string) error {
+		parsed, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return fmt.Errorf("not an integer: %q", value)
+		}
+		cfg.Websocket.MaxMessageSize = parsed
+		return nil
+	}},
+}
+
+// Load builds the configuration from the defaults, then the JSON file named by -config or
+// CONFIG_FILE, then the environment, then the remaining flags, and validates the result
+func Load(args []string) (*Config, error) {
+	flags := flag.NewFlagSet("dexbe", flag.ContinueOnError)
+	configPath := flags.String("config", os.Getenv("CONFIG_FILE"), "JSON config file (CONFIG_FILE)")
+	flagValues := map[string]string{}
+	for _, s := range settings {
+		name := s.flag
+		flags.Func(name, fmt.Sprintf("%s (%s)", s.usage, s.env), func(value string) error {
+			flagValues[name] = value
+			return nil
+		})
+	}
+	if err := flags.Parse(args); err != nil {
+		return nil, err
+	}
+
+	cfg := Default()
+	if *configPath != "" {
+		file, err := os.Open(*configPath)
+		if err != nil {
+			return nil, fmt.Errorf("config file: %w", err)
+		}
+		defer file.Close()
+		decoder := json.NewDecoder(file)
+		decoder.DisallowUnknownFields()
+		if err := decoder.Decode(cfg); err != nil {
+			return nil, fmt.Errorf("config file %s: %w", *configPath, err)
+		}
+	}
+
+	var errs []error
+	for _, s := range settings {
+		if value, set := os.LookupEnv(s.env); set {
+			if err := s.set(cfg, value); err != nil {
+				errs = append(errs, fmt.Errorf("%s: %w", s.env, err))
+			}
+		}
+	}
+	for _, s := range settings {
+		if value, set := flagValues[s.flag]; set {
+			if err := s.set(cfg, value); err != nil {
+				errs = append(errs, fmt.Errorf("-%s: %w", s.flag, err))
+			}
+		}
+	}
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+// Validate reports every invalid setting at once
+func (cfg *Config) Validate() error {
+	var errs []error
+	fail := func(field, format string, args ...any) {
+		errs = append(errs, fmt.Errorf("%s: %s", field, fmt.Sprintf(format, args...)))
+	}
+
+	if cfg.Chain.ID <= 0 {
+		fail("chain.id", "must be positive, got %d", cfg.Chain.ID)
+	}
+	if u, err := url.Parse(cfg.Chain.RPCURL); err != nil || u.Host == "" {
+		fail("chain.rpcUrl", "%q is not a URL", cfg.Chain.RPCURL)
+	} else if u.Scheme != "http" && u.Scheme != "https" && u.Scheme != "ws" && u.Scheme != "wss" {
+		fail("chain.rpcUrl", "scheme must be http, https, ws or wss, got %q", u.Scheme)
+	}
+
+	for _, contract := range []struct{ field, addr string }{
+		{"contracts.exchange (EXCHANGE)", cfg.Contracts.Exchange},
+		{"contracts.tokenRegistry (TOKENREGISTRY)", cfg.Contracts.TokenRegistry},
+	} {
+		if !common.IsHexAddress(contract.addr) {
+			fail(contract.field, "%q is not an address", contract.addr)
+		} else if common.HexToAddress(contract.addr) == (common.Address{}) {
+			fail(contract.field, "must not be the zero address")
+		}
+	}
+
+	cfg.validateSigner(fail)
+
+	for _, listener := range []struct{ field, addr string }{
+		{"server.addr", cfg.Server.Addr},
+		{"server.grpcAddr", cfg.Server.GRPCAddr},
+		{"server.fixAddr", cfg.Server.FIXAddr},
+	} {
+		if _, _, err := net.SplitHostPort(listener.addr); err != nil {
+			fail(listener.field, "%q is not host:port", listener.addr)
+		}
+	}
+	if (cfg.Server.GRPCTLSCert == "") != (cfg.Server.GRPCTLSKey == "") {
+		fail("server.grpcTlsCert", "certificate and key go together")
+	}
+	if cfg.Server.FIXSessions != "" && cfg.Server.FIXCompID == "" {
+		fail("server.fixCompId", "required when FIX sessions are configured")
+	}
+	for _, proxy := range cfg.Server.TrustedProxies {
+		if _, _, err := net.ParseCIDR(proxy); err != nil {
+			fail("server.trustedProxies", "%q is not a CIDR range", proxy)
+		}
+	}
+	for _, addr := range cfg.Server.AdminAddresses {
+		if !common.IsHexAddress(addr) {
+			fail("server.adminAddresses", "%q is not an address", addr)
+		}
+	}
+	if cfg.Server.EngineAuditLog == "" {
+		fail("server.engineAuditLog", "required")
+	}
+	if cfg.Server.AuditAnchor.Duration < 0 {
+		fail("server.auditAnchor", "must not be negative, got %v", cfg.Server.AuditAnchor.Duration)
+	}
+	switch cfg.Server.TraceExporter {
+	case "", "otlp":
+	case "file":
+		if cfg.Server.TraceFile == "" {
+			fail("server.traceFile", "required by the file trace exporter")
+		}
+	default:
+		fail("server.traceExporter", "must be file or otlp, got %q", cfg.Server.TraceExporter)
+	}
+
+	if cfg.Server.ShutdownTimeout.Duration <= 0 {
+		fail("server.shutdownTimeout", "must be positive, got %v", cfg.Server.ShutdownTimeout.Duration)
+	}
+
+	if cfg.Matching.Interval.Duration <= 0 {
+		fail("matching.interval", "must be positive, got %v", cfg.Matching.Interval.Duration)
+	}
+	if cfg.Matching.MaxRingDepth < 2 {
+		fail("matching.maxRingDepth", "must be at least 2, got %d", cfg.Matching.MaxRingDepth)
+	}
+
+	if cfg.Websocket.SendQueue <= 0 {
+		fail("websocket.sendQueue", "must be positive, got %d", cfg.Websocket.SendQueue)
+	}
+	if cfg.Websocket.WriteWait.Duration <= 0 {
+		fail("websocket.writeWait", "must be positive, got %v", cfg.Websocket.WriteWait.Duration)
+	}
+	if cfg.Websocket.PongWait.Duration < time.Second {
+		fail("websocket.pongWait", "must be at least 1s, got %v", cfg.Websocket.PongWait.Duration)
+	}
+	if cfg.Websocket.MaxMessageSize <= 0 {
+		fail("websocket.maxMessageSize", "must be positive, got %d", cfg.Websocket.MaxMessageSize)
+	}
+
+	if cfg.Health.MaxBlockAge.Duration < 0 {
+		fail("health.maxBlockAge", "must not be negative, got %v", cfg.Health.MaxBlockAge.Duration)
+	}
+	if balance, ok := new(big.Int).SetString(cfg.Health.MinOperatorBalance, 10); !ok || balance.Sign() < 0 {
+		fail("health.minOperatorBalance", "%q is not an amount of wei", cfg.Health.MinOperatorBalance)
+	}
+	if cfg.Health.MatcherStaleAfter.Duration <= cfg.Matching.Interval.Duration {
+		fail("health.matcherStaleAfter", "must be longer than matching.interval (%v), got %v", cfg.Matching.Interval.Duration, cfg.Health.MatcherStaleAfter.Duration)
+	}
+	if cfg.Health.StuckAfter.Duration <= 0 {
+		fail("health.stuckAfter", "must be positive, got %v", cfg.Health.StuckAfter.Duration)
+	}
+
+	if len(errs) > 0 {
+		return fmt.Errorf("invalid configuration:\n%w", errors.Join(errs...))
+	}
+	return nil
+}
+
+// validateSigner checks what can be checked without decrypting the keystore or contacting the
+// external signer; opening the signer reports the rest
+func (cfg *Config) validateSigner(fail func(field, format string, args ...any)) {
+	signer := cfg.Signer
+	if signer.Type != SignerKey && signer.PrivateKey != "" && signer.PrivateKey != hardhatDeployerKey {
+		fail("signer.privateKey", "only used by the key signer, remove it for the %s signer", signer.Type)
+	}
+	switch signer.Type {
+	case SignerKey:
+		if _, err := crypto.HexToECDSA(strings.TrimPrefix(signer.PrivateKey, "0x")); err != nil {
+			fail("signer.privateKey", "not a hex private key: %v", err)
+		} else if strings.EqualFold(signer.PrivateKey, hardhatDeployerKey) && cfg.Chain.ID != localChainID {
+			fail("signer.privateKey", "the public Hardhat deployer key is only allowed on chain %d, use a keystore or external signer", localChainID)
+		}
+	case SignerKeystore:
+		if _, err := os.Stat(signer.Keystore); err != nil {
+			fail("signer.keystore", "%v", err)
+		}
+		if signer.PassphraseFile == "" {
+			fail("signer.passphraseFile", "required by the keystore signer")
+		} else if _, err := os.Stat(signer.PassphraseFile); err != nil {
+			fail("signer.passphraseFile", "%v", err)
+		}
+	case SignerExternal:
+		if signer.Endpoint == "" {
+			fail("signer.endpoint", "required by the external signer")
+		}
+		if signer.Address != "" && !common.IsHexAddress(signer.Address) {
+			fail("signer.address", "%q is not an address", signer.Address)
+		}
+	default:
+		fail("signer.type", "must be key, keystore or external, got %q", signer.Type)
+	}
+}
+
+// MinOperatorBalanceWei parses MinOperatorBalance. It assumes Validate passed.
+func (health HealthConfig) MinOperatorBalanceWei() *big.Int {
+	minBalance, _ := new(big.Int).SetString(health.MinOperatorBalance, 10)
+	return minBalance
+}
@@ -0,0 +1,106 @@
+package admin
+
+import (
+	"dexbe/internal/infra/audit"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// AuditEntry records one privileged request and its outcome
+type AuditEntry struct {
+	Time    time.Time      `json:"time"`
+	Admin   common.Address `json:"admin"`
+	Action  string         `json:"action"`
+	Request string         `json:"request,omitempty"`
+	Status  int            `json:"status"`
+	Error   string         `json:"error,omitempty"`
+}
+
+// auditRecent is how many entries the trail keeps in memory; the file is the full record
+const auditRecent = 1000
+
+// AuditTrail keeps the most recent admin actions in memory and appends every one as a JSON line to
+// a file
+type AuditTrail struct {
+	entries []AuditEntry // ring of the last auditRecent entries
+	next    int          // where the next entry goes once the ring is full
+	file    *os.File
+	mu      sync.Mutex
+}
+
+func NewAuditTrail(path string) (*AuditTrail, error) {
+	trail := &AuditTrail{
+		entries: make([]AuditEntry, 0, auditRecent),
+	}
+	if path == "" {
+		return trail, nil
+	}
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open admin audit log %s: %w", path, err)
+	}
+	trail.file = file
+	return trail, nil
+}
+
+func (trail *AuditTrail) Record(entry AuditEntry) {
+	if entry.Time.IsZero() {
+		entry.Time = time.Now().UTC()
+	}
+
+	trail.mu.Lock()
+	defer trail.mu.Unlock()
+
+	if len(trail.entries) < auditRecent {
+		trail.entries = append(trail.entries, entry)
+	} else {
+		trail.entries[trail.next] = entry
+		trail.next = (trail.next + 1) % auditRecent
+	}
+	log.Printf("**Admin Action**: %s by %s (status %d)", entry.Action, entry.Admin.Hex(), entry.Status)
+	audit.Record(audit.KindAdmin, entry)
+
+	if trail.file == nil {
+		return
+	}
+	encoded, err := json.Marshal(entry)
+	if err != nil {
+		log.Printf("**Audit Error**: failed to encode entry: %v", err)
+		return
+	}
+	if _, err := trail.file.Write(append(encoded, '\n')); err != nil {
+		log.Printf("**Audit Error**: failed to write entry: %v", err)
+	}
+}
+
+// Entries returns the most recent limit entries still held in memory, newest last
+func (trail *AuditTrail) Entries(limit int) []AuditEntry {
+	trail.mu.Lock()
+	defer trail.mu.Unlock()
+	count := len(trail.entries)
+	if limit > 0 && count > limit {
+		count = limit
+	}
+	result := make([]AuditEntry, count)
+	// The oldest entry sits at next, so the newest count end just before it
+	start := trail.next + len(trail.entries) - count
+	for i := range result {
+		result[i] = trail.entries[(start+i)%len(trail.entries)]
+	}
+	return result
+}
+
+func (trail *AuditTrail) Close() error {
+	trail.mu.Lock()
+	defer trail.mu.Unlock()
+	if trail.file == nil {
+		return nil
+	}
+	return trail.file.Close()
+}
@@ -0,0 +1,47 @@
+package auth
+
+import (
+	"log"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// AdminSet holds the addresses allowed to call privileged endpoints and where each grant came from
+type AdminSet struct {
+	admins map[common.Address]string
+	mu     sync.RWMutex
+}
+
+func NewAdminSet() *AdminSet {
+	return &AdminSet{
+		admins: make(map[common.Address]string),
+	}
+}
+
+func (set *AdminSet) Add(addr common.Address, source string) {
+	set.mu.Lock()
+	defer set.mu.Unlock()
+	if _, exists := set.admins[addr]; exists {
+		return
+	}
+	set.admins[addr] = source
+	log.Printf("**Admin Registered**: %s (%s)", addr.Hex(), source)
+}
+
+func (set *AdminSet) IsAdmin(addr common.Address) bool {
+	set.mu.RLock()
+	defer set.mu.RUnlock()
+	_, exists := set.admins[addr]
+	return exists
+}
+
+func (set *AdminSet) List() map[string]string {
+	set.mu.RLock()
+	defer set.mu.RUnlock()
+	result := make(map[string]string, len(set.admins))
+	for addr, source := range set.admins {
+		result[addr.Hex()] = source
+	}
+	return result
+}
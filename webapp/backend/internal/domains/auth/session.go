@@ -0,0 +1,184 @@
+package auth
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+const (
+	DefaultChallengeTTL = 5 * time.Minute
+	DefaultSessionTTL   = 24 * time.Hour
+)
+
+var ErrInvalidSession = errors.New("invalid or expired session")
+
+type Challenge struct {
+	Address   common.Address
+	Message   string
+	ExpiresAt time.Time
+}
+
+type Session struct {
+	Token     string         `json:"token"`
+	Address   common.Address `json:"address"`
+	ExpiresAt time.Time      `json:"expiresAt"`
+}
+
+// SessionStore issues SIWE challenges and the bearer sessions created from signed ones
+type SessionStore struct {
+	ChainId      int
+	ChallengeTTL time.Duration
+	SessionTTL   time.Duration
+	challenges   map[string]*Challenge
+	sessions     map[string]*Session
+	mu           sync.Mutex
+}
+
+func NewSessionStore(chainId int) *SessionStore {
+	return &SessionStore{
+		ChainId:      chainId,
+		ChallengeTTL: DefaultChallengeTTL,
+		SessionTTL:   DefaultSessionTTL,
+		challenges:   make(map[string]*Challenge),
+		sessions:     make(map[string]*Session),
+	}
+}
+
+// IssueChallenge creates a single-use SIWE message for address to sign
+func (store *SessionStore) IssueChallenge(address common.Address, domain, uri string) (*SiweMessage, error) {
+	nonce, err := randomHex(16)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	msg := NewSiweMessage(domain, address, uri, store.ChainId, nonce, now, store.ChallengeTTL)
+
+	store.mu.Lock()
+	defer store.mu.Unlock()
+	store.pruneLocked(now)
+	store.challenges[nonce] = &Challenge{
+		Address:   address,
+		Message:   msg.String(),
+		ExpiresAt: msg.ExpirationTime,
+	}
+	return msg, nil
+}
+
+// Login checks a signed challenge and returns a new session for the recovered address
+func (store *SessionStore) Login(message string, sig []byte) (*Session, error) {
+	nonce, err := parseSiweNonce(message)
+	if err != nil {
+		return nil, err
+	}
+
+	store.mu.Lock()
+	challenge, exists := store.challenges[nonce]
+	// Challenges are single use, whether or not the signature checks out
+	delete(store.challenges, nonce)
+	store.mu.Unlock()
+
+	if !exists {
+		return nil, fmt.Errorf("unknown or already used challenge")
+	}
+	if time.Now().After(challenge.ExpiresAt) {
+		return nil, fmt.Errorf("challenge expired")
+	}
+	if message != challenge.Message {
+		return nil, fmt.Errorf("signed message does not match issued challenge")
+	}
+
+	recovered, err := recoverPersonalSign([]byte(message), sig)
+	if err != nil {
+		return nil, err
+	}
+	if recovered != challenge.Address {
+		return nil, fmt.Errorf("signature recovered address %s does not match %s", recovered.Hex(), challenge.Address.Hex())
+	}
+
+	token, err := randomHex(32)
+	if err != nil {
+		return nil, err
+	}
+	session := &Session{
+		Token:     token,
+		Address:   recovered,
+		ExpiresAt: time.Now().Add(store.SessionTTL),
+	}
+
+	store.mu.Lock()
+	store.sessions[token] = session
+	store.mu.Unlock()
+
+	log.Printf("**Session Created**: %s (expires %s)", recovered.Hex()[:10], session.ExpiresAt.Format(time.RFC3339))
+	return session, nil
+}
+
+// Get returns the live session for token
+func (store *SessionStore) Get(token string) (*Session, error) {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	session, exists := store.sessions[token]
+	if !exists {
+		return nil, ErrInvalidSession
+	}
+	if time.Now().After(session.ExpiresAt) {
+		delete(store.sessions, token)
+		return nil, ErrInvalidSession
+	}
+	return session, nil
+}
+
+func (store *SessionStore) Revoke(token string) {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+	delete(store.sessions, token)
+}
+
+func (store *SessionStore) pruneLocked(now time.Time) {
+	for nonce, challenge := range store.challenges {
+		if now.After(challenge.ExpiresAt) {
+			delete(store.challenges, nonce)
+		}
+	}
+	for token, session := range store.sessions {
+		if now.After(session.ExpiresAt) {
+			delete(store.sessions, token)
+		}
+	}
+}
+
+// recoverPersonalSign recovers the signer of an EIP-191 personal_sign message
+func recoverPersonalSign(message []byte, sig []byte) (common.Address, error) {
+	if len(sig) != 65 {
+		return common.Address{}, fmt.Errorf("invalid signature length: got %d, want 65", len(sig))
+	}
+	recoverSig := make([]byte, len(sig))
+	copy(recoverSig, sig)
+	v := int(recoverSig[64])
+	if v == 27 || v == 28 {
+		recoverSig[64] = byte(v - 27)
+	}
+	pubKey, err := crypto.SigToPub(accounts.TextHash(message), recoverSig)
+	if err != nil {
+		return common.Address{}, fmt.Errorf("failed to recover public key from signature: %w", err)
+	}
+	return crypto.PubkeyToAddress(*pubKey), nil
+}
+
+func randomHex(n int) (string, error) {
+	buf := make([]byte, n)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("failed to generate random bytes: %w", err)
+	}
+	return hex.EncodeToString(buf), nil
+}
@@ -0,0 +1,64 @@
+package auth
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+const SiweVersion = "1"
+const SiweStatement = "Sign in to SmashDEX to access your orders and account updates."
+
+// SiweMessage is an EIP-4361 Sign-In with Ethereum message issued by the backend
+type SiweMessage struct {
+	Domain         string
+	Address        common.Address
+	Statement      string
+	URI            string
+	Version        string
+	ChainId        int
+	Nonce          string
+	IssuedAt       time.Time
+	ExpirationTime time.Time
+}
+
+func NewSiweMessage(domain string, address common.Address, uri string, chainId int, nonce string, issuedAt time.Time, ttl time.Duration) *SiweMessage {
+	return &SiweMessage{
+		Domain:         domain,
+		Address:        address,
+		Statement:      SiweStatement,
+		URI:            uri,
+		Version:        SiweVersion,
+		ChainId:        chainId,
+		Nonce:          nonce,
+		IssuedAt:       issuedAt.UTC(),
+		ExpirationTime: issuedAt.Add(ttl).UTC(),
+	}
+}
+
+// String renders the message in the exact EIP-4361 layout the wallet will sign
+func (m *SiweMessage) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s wants you to sign in with your Ethereum account:\n", m.Domain)
+	fmt.Fprintf(&b, "%s\n\n", m.Address.Hex())
+	fmt.Fprintf(&b, "%s\n\n", m.Statement)
+	fmt.Fprintf(&b, "URI: %s\n", m.URI)
+	fmt.Fprintf(&b, "Version: %s\n", m.Version)
+	fmt.Fprintf(&b, "Chain ID: %d\n", m.ChainId)
+	fmt.Fprintf(&b, "Nonce: %s\n", m.Nonce)
+	fmt.Fprintf(&b, "Issued At: %s\n", m.IssuedAt.Format(time.RFC3339))
+	fmt.Fprintf(&b, "Expiration Time: %s", m.ExpirationTime.Format(time.RFC3339))
+	return b.String()
+}
+
+// parseSiweNonce extracts the Nonce field, which identifies the challenge a signed message answers
+func parseSiweNonce(message string) (string, error) {
+	for _, line := range strings.Split(message, "\n") {
+		if nonce, ok := strings.CutPrefix(line, "Nonce: "); ok {
+			return strings.TrimSpace(nonce), nil
+		}
+	}
+	return "", fmt.Errorf("message has no Nonce field")
+}
@@ -0,0 +1,185 @@
+package candle
+
+import (
+	"dexbe/internal/domains/trade"
+	"dexbe/internal/infra/api"
+	"dexbe/internal/infra/metrics"
+	"encoding/json"
+	"log"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// MaxCandlesPerSeries bounds the bars kept for each pair and interval
+const MaxCandlesPerSeries = 5000
+
+type seriesKey struct {
+	pair     string
+	interval Interval
+}
+
+// Aggregator folds confirmed trades into OHLCV candles per pair and interval and streams updated
+// candles to subscribers of that pair and interval
+type Aggregator struct {
+	series      map[seriesKey][]*Candle
+	subscribers map[seriesKey]map[api.WsSubscriber]bool
+	updateCh    chan *Candle
+	mu          sync.RWMutex
+}
+
+func NewAggregator() *Aggregator {
+	aggregator := &Aggregator{
+		series:      make(map[seriesKey][]*Candle),
+		subscribers: make(map[seriesKey]map[api.WsSubscriber]bool),
+		updateCh:    make(chan *Candle, 256),
+	}
+	aggregator.StartBroadcast()
+	return aggregator
+}
+
+// AddTrade updates the candle containing the trade in every interval and publishes the updated bars
+func (aggregator *Aggregator) AddTrade(t *trade.Trade) {
+	aggregator.mu.Lock()
+	updated := make([]*Candle, 0, len(Intervals))
+	for _, interval := range Intervals {
+		updated = append(updated, aggregator.apply(t, interval).copy())
+	}
+	aggregator.mu.Unlock()
+
+	for _, c := range updated {
+		aggregator.updateCh <- c
+	}
+}
+
+// apply must be called with the lock held
+func (aggregator *Aggregator) apply(t *trade.Trade, interval Interval) *Candle {
+	key := seriesKey{pair: t.Pair, interval: interval}
+	openTime := interval.Start(t.Timestamp)
+	candles := aggregator.series[key]
+
+	// Trades arrive in time order, so the bucket is almost always the last one
+	i := sort.Search(len(candles), func(i int) bool {
+		return !candles[i].OpenTime.Before(openTime)
+	})
+	if i < len(candles) && candles[i].OpenTime.Equal(openTime) {
+		candles[i].apply(t)
+		return candles[i]
+	}
+
+	c := newCandle(t.Pair, interval, openTime, t)
+	candles = append(candles, nil)
+	copy(candles[i+1:], candles[i:])
+	candles[i] = c
+	if len(candles) > MaxCandlesPerSeries {
+		candles = candles[len(candles)-MaxCandlesPerSeries:]
+	}
+	aggregator.series[key] = candles
+	return c
+}
+
+// Rebuild discards every candle and replays the full trade history
+func (aggregator *Aggregator) Rebuild(history *trade.TradeStore) {
+	aggregator.mu.Lock()
+	defer aggregator.mu.Unlock()
+
+	aggregator.series = make(map[seriesKey][]*Candle)
+	count := 0
+	for _, pair := range history.Pairs() {
+		for _, t := range history.All(pair) {
+			for _, interval := range Intervals {
+				aggregator.apply(t, interval)
+			}
+			count++
+		}
+	}
+	log.Printf("**Candles Rebuilt**: replayed %d trades", count)
+}
+
+// Range returns the candles of pair and interval opening in [from, to), oldest first, at most limit
+// of them counted back from to. A zero from or to leaves that end open.
+func (aggregator *Aggregator) Range(pair string, interval Interval, from, to time.Time, limit int) []*Candle {
+	aggregator.mu.RLock()
+	defer aggregator.mu.RUnlock()
+
+	candles := aggregator.series[seriesKey{pair: pair, interval: interval}]
+	start := 0
+	if !from.IsZero() {
+		start = sort.Search(len(candles), func(i int) bool {
+			return !candles[i].OpenTime.Before(from)
+		})
+	}
+	end := len(candles)
+	if !to.IsZero() {
+		end = sort.Search(len(candles), func(i int) bool {
+			return !candles[i].OpenTime.Before(to)
+		})
+	}
+	if end-start > limit {
+		start = end - limit
+	}
+
+	result := make([]*Candle, 0, max(end-start, 0))
+	for i := start; i < end; i++ {
+		result = append(result, candles[i].copy())
+	}
+	return result
+}
+
+func (aggregator *Aggregator) AddSubscriber(pair string, interval Interval, conn api.WsSubscriber) {
+	key := seriesKey{pair: pair, interval: interval}
+	aggregator.mu.Lock()
+	defer aggregator.mu.Unlock()
+	if aggregator.subscribers[key] == nil {
+		aggregator.subscribers[key] = make(map[api.WsSubscriber]bool)
+	}
+	if !aggregator.subscribers[key][conn] {
+		metrics.Subscribers.WithLabelValues("candles").Inc()
+	}
+	aggregator.subscribers[key][conn] = true
+}
+
+func (aggregator *Aggregator) RemoveSubscriber(pair string, interval Interval, conn api.WsSubscriber) {
+	aggregator.mu.Lock()
+	defer aggregator.mu.Unlock()
+	key := seriesKey{pair: pair, interval: interval}
+	if aggregator.subscribers[key][conn] {
+		metrics.Subscribers.WithLabelValues("candles").Dec()
+		delete(aggregator.subscribers[key], conn)
+	}
+}
+
+// QueueLength returns how many updated candles are waiting to be broadcast
+func (aggregator *Aggregator) QueueLength() int {
+	return len(aggregator.updateCh)
+}
+
+func (aggregator *Aggregator) StartBroadcast() {
+	go func() {
+		for c := range aggregator.updateCh {
+			encoded, _ := json.Marshal(map[string]any{
+				"event":    "Candle",
+				"pair":     c.Pair,
+				"interval": c.Interval,
+				"data":     c,
+			})
+
+			key := seriesKey{pair: c.Pair, interval: c.Interval}
+			aggregator.mu.RLock()
+			conns := make([]api.WsSubscriber, 0, len(aggregator.subscribers[key]))
+			for conn := range aggregator.subscribers[key] {
+				conns = append(conns, conn)
+			}
+			aggregator.mu.RUnlock()
+
+			for _, conn := range conns {
+				if err := conn.WriteMessage(websocket.TextMessage, encoded); err != nil {
+					aggregator.RemoveSubscriber(c.Pair, c.Interval, conn)
+					conn.Close()
+				}
+			}
+		}
+	}()
+}
@@ -0,0 +1,106 @@
+package candle
+
+import (
+	"dexbe/internal/domains/trade"
+	"fmt"
+	"math/big"
+	"time"
+)
+
+// Interval is the width of a candle, e.g. "1m"
+type Interval string
+
+const (
+	Interval1m Interval = "1m"
+	Interval5m Interval = "5m"
+	Interval1h Interval = "1h"
+	Interval1d Interval = "1d"
+)
+
+// Intervals lists every interval the aggregator maintains
+var Intervals = []Interval{Interval1m, Interval5m, Interval1h, Interval1d}
+
+var intervalDurations = map[Interval]time.Duration{
+	Interval1m: time.Minute,
+	Interval5m: 5 * time.Minute,
+	Interval1h: time.Hour,
+	Interval1d: 24 * time.Hour,
+}
+
+func ParseInterval(s string) (Interval, error) {
+	interval := Interval(s)
+	if _, ok := intervalDurations[interval]; !ok {
+		return "", fmt.Errorf("unsupported interval %q, expected one of %v", s, Intervals)
+	}
+	return interval, nil
+}
+
+func (i Interval) Duration() time.Duration {
+	return intervalDurations[i]
+}
+
+// Start returns the open time of the candle of this interval containing t (UTC aligned)
+func (i Interval) Start(t time.Time) time.Time {
+	return t.UTC().Truncate(i.Duration())
+}
+
+// Candle is one OHLCV bar. Prices are in the book's 1e18 price scale, volumes in token base units.
+type Candle struct {
+	Pair        string    `json:"pair"`
+	Interval    Interval  `json:"interval"`
+	OpenTime    time.Time `json:"openTime"`
+	CloseTime   time.Time `json:"closeTime"`
+	Open        *big.Int  `json:"open"`
+	High        *big.Int  `json:"high"`
+	Low         *big.Int  `json:"low"`
+	Close       *big.Int  `json:"close"`
+	VolumeBase  *big.Int  `json:"volumeBase"`
+	VolumeQuote *big.Int  `json:"volumeQuote"`
+	TradeCount  int       `json:"tradeCount"`
+}
+
+func newCandle(pair string, interval Interval, openTime time.Time, t *trade.Trade) *Candle {
+	return &Candle{
+		Pair:        pair,
+		Interval:    interval,
+		OpenTime:    openTime,
+		CloseTime:   openTime.Add(interval.Duration()),
+		Open:        new(big.Int).Set(t.Price),
+		High:        new(big.Int).Set(t.Price),
+		Low:         new(big.Int).Set(t.Price),
+		Close:       new(big.Int).Set(t.Price),
+		VolumeBase:  new(big.Int).Set(t.BaseQty),
+		VolumeQuote: new(big.Int).Set(t.QuoteQty),
+		TradeCount:  1,
+	}
+}
+
+// apply folds a later trade of the same bucket into the candle
+func (c *Candle) apply(t *trade.Trade) {
+	if t.Price.Cmp(c.High) > 0 {
+		c.High.Set(t.Price)
+	}
+	if t.Price.Cmp(c.Low) < 0 {
+		c.Low.Set(t.Price)
+	}
+	c.Close.Set(t.Price)
+	c.VolumeBase.Add(c.VolumeBase, t.BaseQty)
+	c.VolumeQuote.Add(c.VolumeQuote, t.QuoteQty)
+	c.TradeCount++
+}
+
+func (c *Candle) copy() *Candle {
+	return &Candle{
+		Pair:        c.Pair,
+		Interval:    c.Interval,
+		OpenTime:    c.OpenTime,
+		CloseTime:   c.CloseTime,
+		Open:        new(big.Int).Set(c.Open),
+		High:        new(big.Int).Set(c.High),
+		Low:         new(big.Int).Set(c.Low),
+		Close:       new(big.Int).Set(c.Close),
+		VolumeBase:  new(big.Int).Set(c.VolumeBase),
+		VolumeQuote: new(big.Int).Set(c.VolumeQuote),
+		TradeCount:  c.TradeCount,
+	}
+}
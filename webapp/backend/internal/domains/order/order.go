@@ -9,6 +9,7 @@ import (
 	"math/big"
 	"reflect"
 	"strings"
+	"time"
 
 	"github.com/ethereum/go-ethereum/accounts/abi"
 	"github.com/ethereum/go-ethereum/common"
@@ -50,6 +51,8 @@ type Order struct {
 	Status            OrderStatus    `json:"status"`
 	ConditionalOrder  *Order         `json:"conditionalOrder"`
 	TransactionHashes []string
+	CancelSignature   []byte    `json:"cancelSignature,omitempty"`
+	CreatedAt         time.Time `json:"createdAt"`
 }
 
 func NewOrder(createdBy string, symbolIn string, symbolOut string, amtIn string, amtOut string, nonce string, signature string, limitPrice string, filledAmtIn string, status int, conditionalOrder *Order, triggerPrice string) *Order {
@@ -108,6 +111,7 @@ func (o *Order) DeepCopy() *Order {
 		SymbolIn:  o.SymbolIn,  // strings are immutable in Go
 		SymbolOut: o.SymbolOut,
 		Status:    o.Status,
+		CreatedAt: o.CreatedAt,
 	}
 
 	// Deep copy big.Int pointers
@@ -133,6 +137,11 @@ func (o *Order) DeepCopy() *Order {
 		copy(orderCopy.Signature, o.Signature)
 	}
 
+	if o.CancelSignature != nil {
+		orderCopy.CancelSignature = make([]byte, len(o.CancelSignature))
+		copy(orderCopy.CancelSignature, o.CancelSignature)
+	}
+
 	// Deep copy string slice
 	if o.TransactionHashes != nil {
 		orderCopy.TransactionHashes = make([]string, len(o.TransactionHashes))
@@ -187,6 +196,8 @@ func (o *Order) ToStringMap() map[string]string {
 			strVal = f.String()
 		case common.Address:
 			strVal = f.Hex()
+		case time.Time:
+			strVal = f.Format(time.RFC3339Nano)
 		case []byte:
 			if f != nil {
 				strVal = hex.EncodeToString(f)
@@ -299,6 +310,29 @@ func (o *Order) HashOrder(chainID *big.Int, verifyingContract common.Address) ([
 	structHashInput := append(orderTypeHash, packedOrderData...)
 	structHash := crypto.Keccak256(structHashInput)
 
+	domainSeparator, err := hashDomain(chainID, verifyingContract)
+	if err != nil {
+		return nil, err
+	}
+
+	return hashTypedData(domainSeparator, structHash), nil
+}
+
+// hashDomain computes the EIP-712 domain separator shared by every SmashDEX typed message
+func hashDomain(chainID *big.Int, verifyingContract common.Address) ([]byte, error) {
+	addrType, err := abi.NewType("address", "", nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create address type: %w", err)
+	}
+	bytes32Type, err := abi.NewType("bytes32", "", nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create bytes32 type: %w", err)
+	}
+	uint256Type, err := abi.NewType("uint256", "", nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create uint256 type: %w", err)
+	}
+
 	domainTypeHash := crypto.Keccak256([]byte(EIP712DomainType))
 	nameHashSlice := crypto.Keccak256([]byte(EIP712DomainName))
 	versionHashSlice := crypto.Keccak256([]byte(EIP712DomainVersion))
@@ -324,13 +358,16 @@ func (o *Order) HashOrder(chainID *big.Int, verifyingContract common.Address) ([
 		return nil, fmt.Errorf("failed to EIP-712 encode domain data: %w", err)
 	}
 	domainHashInput := append(domainTypeHash, packedDomainData...)
-	domainSeparator := crypto.Keccak256(domainHashInput)
+	return crypto.Keccak256(domainHashInput), nil
+}
+
+func hashTypedData(domainSeparator, structHash []byte) []byte {
 	prefix := []byte{0x19, 0x01}
 
 	finalHashInput := append(prefix, domainSeparator...)
 	finalHashInput = append(finalHashInput, structHash...)
 
-	return crypto.Keccak256(finalHashInput), nil
+	return crypto.Keccak256(finalHashInput)
 }
 
 func (o *Order) VerifyOrder(sig []byte, chainID *big.Int, verifyingContract common.Address) (bool, error) {
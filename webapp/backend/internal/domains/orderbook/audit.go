@@ -0,0 +1,69 @@
+package orderbook
+
+import (
+	"dexbe/internal/domains/order"
+	"dexbe/internal/infra/audit"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// auditStatus records an order moving to status (named as in order.OrderStatus, since the engine's
+// own status numbers differ), with the reason and the transaction or signed cancel behind it
+func auditStatus(o *order.Order, status, reason, txHash string) {
+	filled := "0"
+	if o.FilledAmtIn != nil {
+		filled = o.FilledAmtIn.String()
+	}
+	data := map[string]any{
+		"orderId":     getOrderKey(o),
+		"status":      status,
+		"filledAmtIn": filled,
+		"reason":      reason,
+		"txHash":      txHash,
+	}
+	if len(o.CancelSignature) > 0 {
+		data["cancelSignature"] = hexutil.Encode(o.CancelSignature)
+	}
+	audit.Record(audit.KindOrderStatus, data)
+}
+
+// auditSettlement records the receipt of a match or ring transaction
+func auditSettlement(kind, txHash string, result int) {
+	outcome := "confirmed"
+	if result != 1 {
+		outcome = "reverted"
+	}
+	audit.Record(audit.KindSettlement, map[string]any{
+		"kind":   kind,
+		"txHash": txHash,
+		"result": outcome,
+	})
+}
+
+func bigStrings(values []*big.Int) []string {
+	result := make([]string, len(values))
+	for i, v := range values {
+		result[i] = v.String()
+	}
+	return result
+}
+
+// latestTx is the transaction that produced the order's latest fill
+func latestTx(o *order.Order) string {
+	if len(o.TransactionHashes) == 0 {
+		return ""
+	}
+	return o.TransactionHashes[len(o.TransactionHashes)-1]
+}
+
+// auditTrigger records a conditional order leaving the conditional store for its book
+func auditTrigger(orderKey string, entry *ConditionalOrderEntry, reason string) {
+	audit.Record(audit.KindConditionalTriggered, map[string]any{
+		"orderId":      orderKey,
+		"parentId":     entry.ParentOrderID,
+		"type":         entry.ConditionalType,
+		"triggerPrice": entry.TriggerPrice.String(),
+		"reason":       reason,
+	})
+}
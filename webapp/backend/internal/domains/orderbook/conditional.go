@@ -3,6 +3,7 @@ package orderbook
 import (
 	"context"
 	"dexbe/internal/domains/order"
+	"dexbe/internal/infra/audit"
 	"fmt"
 	"log"
 	"math/big"
@@ -134,6 +135,15 @@ func (store *ConditionalOrderStore) AddConditionalOrder(
 		conditionalOrder.AmtOut.String(),
 		conditionalOrder.SymbolOut,
 	)
+	audit.Record(audit.KindConditionalStored, map[string]any{
+		"orderId":        orderKey,
+		"parentId":       parentOrderID,
+		"type":           conditionalType,
+		"triggerPair":    triggerSymbolIn + "/" + triggerSymbolOut,
+		"triggerPrice":   entry.TriggerPrice.String(),
+		"isTriggerAbove": isTriggerAbove,
+		"order":          conditionalOrder.ToStringMap(),
+	})
 
 	return nil
 }
@@ -154,6 +164,7 @@ func (store *ConditionalOrderStore) RemoveConditionalOrder(creator common.Addres
 
 	log.Printf("**Conditional Order Removed**: %s/%s",
 		creator.Hex()[:10], nonce.String())
+	audit.Record(audit.KindConditionalRemoved, map[string]any{"orderId": orderKey})
 
 	_ = entry // Avoid unused variable warning
 	return nil
@@ -269,6 +280,7 @@ func (store *ConditionalOrderStore) checkAndTriggerConditionalOrders() {
 				entry.ConditionalType,
 				triggerReason,
 			)
+			auditTrigger(orderKey, entry, triggerReason)
 
 			// Add the order to the order book
 			err := store.orderBookStore.AddOrder(entry.Order)
@@ -484,6 +496,7 @@ func (store *ConditionalOrderStore) CheckPriceTriggersForBook(symbolIn, symbolOu
 				entry.ConditionalType,
 				triggerReason,
 			)
+			auditTrigger(orderKey, entry, triggerReason)
 
 			// Add the order to the order book
 			log.Printf("🔄 Calling AddOrder...")
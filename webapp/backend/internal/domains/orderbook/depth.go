@@ -0,0 +1,260 @@
+package orderbook
+
+import (
+	"dexbe/internal/domains/order"
+	"encoding/json"
+	"fmt"
+	"math/big"
+
+	rbtree "github.com/emirpasic/gods/trees/redblacktree"
+)
+
+// Level-2 depth feed
+//
+// Every book numbers its depth changes with a sequence that starts at 0 and grows by one per
+// "Depth" message. Subscriptions with price grouping or a depth cap (?group=0.01&depth=20) get
+// their own feed with its own sequence, computed on the grouped levels. A Depth message carries
+// seq, prevSeq (always seq-1) and only the price levels that changed since prevSeq; a level with
+// quantity "0" has been removed. Levels are keyed by priceRaw. A "Snapshot" carries the full book
+// as of its seq.
+//
+// Clients keep the book in sync as follows:
+//  1. Open /orderbook/ws/:in/:out and buffer Depth messages. The first message is a Snapshot.
+//  2. Drop buffered Depth messages with seq <= the snapshot's seq, apply the rest in order.
+//  3. If a Depth message arrives whose prevSeq is not the last applied seq, a message was lost
+//     (for example because the server dropped it for a slow consumer). Discard the local book,
+//     send {"type":"snapshot"} on the socket (or GET /orderbook/:in/:out/snapshot) and resume
+//     from step 2 with the new snapshot.
+
+// DepthLevel is one aggregated price level of active orders. PriceRaw is the exact price in the
+// 1e18 price scale and identifies the level; Price is the same value as a float for display.
+type DepthLevel struct {
+	PriceRaw   string  `json:"priceRaw"`
+	Price      float64 `json:"price"`
+	Quantity   string  `json:"quantity"`
+	OrderCount int     `json:"orderCount"`
+}
+
+// depthLevels aggregates the active orders of one side in tree order, skipping levels without any
+func depthLevels(tree *rbtree.Tree) []DepthLevel {
+	result := []DepthLevel{}
+	iter := tree.Iterator()
+	for iter.Next() {
+		priceKey := iter.Key().(*big.Int)
+		level := aggregateLevel(priceKey, iter.Value().(*PriceLevel))
+		if level.OrderCount > 0 {
+			result = append(result, level)
+		}
+	}
+	return result
+}
+
+func aggregateLevel(priceKey *big.Int, priceLevel *PriceLevel) DepthLevel {
+	priceFloat := new(big.Float).Quo(
+		new(big.Float).SetInt(priceKey),
+		new(big.Float).SetInt(PriceFactor),
+	)
+	price, _ := priceFloat.Float64()
+
+	totalQuantity := big.NewInt(0)
+	activeCount := 0
+	for e := priceLevel.Orders.Front(); e != nil; e = e.Next() {
+		o := e.Value.(*order.Order)
+		if o.Status == 0 {
+			activeCount++
+			totalQuantity.Add(totalQuantity, new(big.Int).Sub(o.AmtIn, o.FilledAmtIn))
+		}
+	}
+	return DepthLevel{
+		PriceRaw:   priceKey.String(),
+		Price:      price,
+		Quantity:   totalQuantity.String(),
+		OrderCount: activeCount,
+	}
+}
+
+// diffLevels returns the levels of current that differ from previous, plus zero-quantity entries
+// for levels that disappeared, and the new state keyed by raw price
+func diffLevels(previous map[string]DepthLevel, current []DepthLevel) ([]DepthLevel, map[string]DepthLevel) {
+	changes := []DepthLevel{}
+	next := make(map[string]DepthLevel, len(current))
+	for _, level := range current {
+		next[level.PriceRaw] = level
+		if old, exists := previous[level.PriceRaw]; !exists || old != level {
+			changes = append(changes, level)
+		}
+	}
+	for priceRaw, old := range previous {
+		if _, exists := next[priceRaw]; !exists {
+			changes = append(changes, DepthLevel{
+				PriceRaw: priceRaw,
+				Price:    old.Price,
+				Quantity: "0",
+			})
+		}
+	}
+	return changes, next
+}
+
+// DepthView selects how a depth feed or snapshot aggregates the book. Group is the price bucket in
+// the 1e18 price scale (nil keeps raw levels) and Depth caps the levels per side (0 keeps all).
+type DepthView struct {
+	Group *big.Int
+	Depth int
+}
+
+// ParseDepthView reads a decimal price increment such as "0.01", "0.1" or "1" (empty for raw
+// levels) and a depth cap (0 for all levels)
+func ParseDepthView(group string, depth int) (DepthView, error) {
+	view := DepthView{Depth: depth}
+	if depth < 0 {
+		return view, fmt.Errorf("depth must not be negative")
+	}
+	if group == "" {
+		return view, nil
+	}
+	increment, ok := new(big.Rat).SetString(group)
+	if !ok || increment.Sign() <= 0 {
+		return view, fmt.Errorf("group must be a positive decimal such as 0.01")
+	}
+	increment.Mul(increment, new(big.Rat).SetInt(PriceFactor))
+	if !increment.IsInt() {
+		return view, fmt.Errorf("group %s has more than %d decimals", group, PricePrecision)
+	}
+	view.Group = new(big.Int).Set(increment.Num())
+	return view, nil
+}
+
+// Key identifies the view, "" being raw levels at full depth
+func (view DepthView) Key() string {
+	if view.Group == nil && view.Depth == 0 {
+		return ""
+	}
+	group := ""
+	if view.Group != nil {
+		group = view.Group.String()
+	}
+	return fmt.Sprintf("%s:%d", group, view.Depth)
+}
+
+// apply buckets each side by the view's group and caps it to the view's depth. Bids round down and
+// asks round up so grouped levels never cross.
+func (view DepthView) apply(levels []DepthLevel, roundUp bool) []DepthLevel {
+	if view.Group != nil {
+		grouped := []DepthLevel{}
+		for _, level := range levels {
+			price, _ := new(big.Int).SetString(level.PriceRaw, 10)
+			bucket, remainder := new(big.Int).QuoRem(price, view.Group, new(big.Int))
+			if roundUp && remainder.Sign() > 0 {
+				bucket.Add(bucket, big.NewInt(1))
+			}
+			bucket.Mul(bucket, view.Group)
+
+			// Levels arrive in price order, so each bucket's levels are adjacent
+			if n := len(grouped); n > 0 && grouped[n-1].PriceRaw == bucket.String() {
+				total, _ := new(big.Int).SetString(grouped[n-1].Quantity, 10)
+				quantity, _ := new(big.Int).SetString(level.Quantity, 10)
+				grouped[n-1].Quantity = total.Add(total, quantity).String()
+				grouped[n-1].OrderCount += level.OrderCount
+				continue
+			}
+			bucketFloat, _ := new(big.Float).Quo(new(big.Float).SetInt(bucket), new(big.Float).SetInt(PriceFactor)).Float64()
+			grouped = append(grouped, DepthLevel{
+				PriceRaw:   bucket.String(),
+				Price:      bucketFloat,
+				Quantity:   level.Quantity,
+				OrderCount: level.OrderCount,
+			})
+		}
+		levels = grouped
+	}
+	if view.Depth > 0 && len(levels) > view.Depth {
+		levels = levels[:view.Depth]
+	}
+	return levels
+}
+
+// depthFeed is the Depth stream of one view: its own seq and the levels last sent
+type depthFeed struct {
+	view        DepthView
+	seq         uint64
+	bids        map[string]DepthLevel
+	asks        map[string]DepthLevel
+	subscribers int
+}
+
+// depthMessage is an encoded Depth message for the subscribers of one view
+type depthMessage struct {
+	view string
+	data []byte
+}
+
+// PublishDepth sends the levels that changed since the last Depth message to the subscribers of
+// every view. Callers must hold book.Mu.
+func (book *MarketOrderBook) PublishDepth() {
+	book.depthMu.Lock()
+	defer book.depthMu.Unlock()
+	book.publishDepthLocked()
+}
+
+func (book *MarketOrderBook) publishDepthLocked() {
+	rawBids, rawAsks := depthLevels(book.Bids), depthLevels(book.Asks)
+	for key, feed := range book.depthFeeds {
+		bidChanges, bids := diffLevels(feed.bids, feed.view.apply(rawBids, false))
+		askChanges, asks := diffLevels(feed.asks, feed.view.apply(rawAsks, true))
+		if len(bidChanges) == 0 && len(askChanges) == 0 {
+			continue
+		}
+		feed.bids, feed.asks = bids, asks
+		feed.seq++
+
+		encoded, _ := json.Marshal(map[string]any{
+			"event":     "Depth",
+			"pair":      fmt.Sprintf("%s/%s", book.SymbolIn, book.SymbolOut),
+			"seq":       feed.seq,
+			"prevSeq":   feed.seq - 1,
+			"lastPrice": book.lastPriceFloat(),
+			"data": map[string]any{
+				"bids": bidChanges,
+				"asks": askChanges,
+			},
+		})
+		// Blocks rather than drops: the broadcaster never waits on depthMu or on a client
+		book.updateCh <- depthMessage{view: key, data: encoded}
+	}
+}
+
+// DepthSnapshot returns the book aggregated by view. When the view has a feed, pending changes are
+// published first and the snapshot carries the feed's seq so it lines up with the Depth stream.
+// Callers must hold book.Mu (a read lock is enough).
+func (book *MarketOrderBook) DepthSnapshot(view DepthView) map[string]any {
+	book.depthMu.Lock()
+	defer book.depthMu.Unlock()
+	book.publishDepthLocked()
+
+	snapshot := map[string]any{
+		"pair":      fmt.Sprintf("%s/%s", book.SymbolIn, book.SymbolOut),
+		"symbolIn":  book.SymbolIn,
+		"symbolOut": book.SymbolOut,
+		"bids":      view.apply(depthLevels(book.Bids), false),
+		"asks":      view.apply(depthLevels(book.Asks), true),
+		"lastPrice": book.lastPriceFloat(),
+		"depth":     view.Depth,
+	}
+	if view.Group != nil {
+		snapshot["group"] = view.Group.String()
+	}
+	if feed, exists := book.depthFeeds[view.Key()]; exists {
+		snapshot["seq"] = feed.seq
+	}
+	return snapshot
+}
+
+func (book *MarketOrderBook) lastPriceFloat() float64 {
+	priceFloat := new(big.Float).Quo(
+		new(big.Float).SetInt(book.LastPrice),
+		new(big.Float).SetInt(PriceFactor),
+	)
+	lastPrice, _ := priceFloat.Float64()
+	return lastPrice
+}
@@ -0,0 +1,39 @@
+package orderbook
+
+import "time"
+
+// MatcherHeartbeat is when the oracle last finished a matching cycle, zero before the first one
+func (store *OrderBookStore) MatcherHeartbeat() time.Time {
+	nanos := store.lastMatchCycle.Load()
+	if nanos == 0 {
+		return time.Time{}
+	}
+	return time.Unix(0, nanos)
+}
+
+// SettlementStatus describes the match and ring transactions still waiting for their receipt
+type SettlementStatus struct {
+	InFlight    int
+	Stuck       int // waiting longer than the stuck threshold
+	StuckOrders int // orders left pending by the stuck ones
+	OldestAge   time.Duration
+}
+
+// Settlements reports the transactions awaiting a receipt, counting those submitted more than
+// stuckAfter ago as stuck
+func (store *OrderBookStore) Settlements(stuckAfter time.Duration) SettlementStatus {
+	store.settlementsMu.Lock()
+	defer store.settlementsMu.Unlock()
+	status := SettlementStatus{InFlight: len(store.inFlight)}
+	for pending := range store.inFlight {
+		age := time.Since(pending.startedAt)
+		if age > status.OldestAge {
+			status.OldestAge = age
+		}
+		if age > stuckAfter {
+			status.Stuck++
+			status.StuckOrders += pending.orders
+		}
+	}
+	return status
+}
@@ -0,0 +1,227 @@
+package orderbook
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"dexbe/internal/domains/order"
+	"dexbe/internal/infra/api"
+	"dexbe/internal/infra/metrics"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"time"
+
+	rbtree "github.com/emirpasic/gods/trees/redblacktree"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/gorilla/websocket"
+)
+
+// Level-3 order-by-order feed
+//
+// Every resting order is published under an opaque id so queue position can be tracked without
+// revealing who placed it; only a subscriber signed in as the maker sees maker and nonce. Events
+// are numbered per book like the Depth feed (seq/prevSeq) and follow the same gap recovery, with
+// "L3Snapshot" in place of "Snapshot".
+
+const (
+	L3Add         = "add"
+	L3PartialFill = "partial_fill"
+	L3Fill        = "fill"
+	L3Cancel      = "cancel"
+)
+
+// l3IdKey keys the opaque order ids; a fresh key per process keeps ids unlinkable across restarts
+var l3IdKey = func() []byte {
+	key := make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		panic(fmt.Sprintf("failed to generate L3 id key: %v", err))
+	}
+	return key
+}()
+
+// L3OrderId returns the opaque id of an order in the L3 feed
+func L3OrderId(o *order.Order) string {
+	mac := hmac.New(sha256.New, l3IdKey)
+	mac.Write(o.CreatedBy.Bytes())
+	mac.Write(o.Nonce.Bytes())
+	return hex.EncodeToString(mac.Sum(nil)[:16])
+}
+
+// L3Order is one resting order. QueuePosition counts the orders ahead of it at its price level.
+// Remaining and Filled are in units of the token the order gives.
+type L3Order struct {
+	OrderId       string          `json:"orderId"`
+	Side          string          `json:"side"`
+	Price         string          `json:"price"`
+	Remaining     string          `json:"remaining"`
+	QueuePosition int             `json:"queuePosition"`
+	Filled        string          `json:"filled,omitempty"`
+	Maker         *common.Address `json:"maker,omitempty"`
+	Nonce         string          `json:"nonce,omitempty"`
+}
+
+// l3Event is an event ready for broadcast with the maker kept aside for owner-only delivery
+type l3Event struct {
+	Type      string
+	Seq       uint64
+	Order     L3Order
+	Maker     common.Address
+	Nonce     string
+	Timestamp time.Time
+}
+
+func (book *MarketOrderBook) sideOf(o *order.Order) string {
+	if o.SymbolIn == book.SymbolIn {
+		return "ask"
+	}
+	return "bid"
+}
+
+func queuePosition(level *PriceLevel, o *order.Order) int {
+	position := 0
+	for e := level.Orders.Front(); e != nil; e = e.Next() {
+		if e.Value.(*order.Order) == o {
+			return position
+		}
+		position++
+	}
+	return position
+}
+
+func (book *MarketOrderBook) l3Order(o *order.Order, level *PriceLevel) L3Order {
+	filledIn := o.FilledAmtIn
+	if filledIn == nil {
+		filledIn = big.NewInt(0)
+	}
+	return L3Order{
+		OrderId:       L3OrderId(o),
+		Side:          book.sideOf(o),
+		Price:         o.LimitPrice.String(),
+		Remaining:     new(big.Int).Sub(o.AmtIn, filledIn).String(),
+		QueuePosition: queuePosition(level, o),
+	}
+}
+
+// PublishL3 publishes an order event. It must be called while the order is still in level, after
+// its filled amount is updated, and with book.Mu held.
+func (book *MarketOrderBook) PublishL3(eventType string, o *order.Order, level *PriceLevel, filled *big.Int) {
+	book.l3Seq++
+	event := &l3Event{
+		Type:      eventType,
+		Seq:       book.l3Seq,
+		Order:     book.l3Order(o, level),
+		Maker:     o.CreatedBy,
+		Nonce:     o.Nonce.String(),
+		Timestamp: time.Now().UTC(),
+	}
+	if filled != nil {
+		event.Order.Filled = filled.String()
+	}
+	// Blocks rather than drops: the broadcaster only takes l3Mu and never waits on a client
+	book.l3Ch <- event
+}
+
+func (event *l3Event) encode(pair string, withMaker bool) []byte {
+	data := event.Order
+	if withMaker {
+		maker := event.Maker
+		data.Maker = &maker
+		data.Nonce = event.Nonce
+	}
+	encoded, _ := json.Marshal(map[string]any{
+		"event":     "L3",
+		"type":      event.Type,
+		"pair":      pair,
+		"seq":       event.Seq,
+		"prevSeq":   event.Seq - 1,
+		"timestamp": event.Timestamp,
+		"data":      data,
+	})
+	return encoded
+}
+
+// L3Snapshot returns every resting order of the book in price-time priority with the seq it is
+// current as of, revealing maker and nonce only on the viewer's own orders.
+// Callers must hold book.Mu (a read lock is enough).
+func (book *MarketOrderBook) L3Snapshot(viewer common.Address) map[string]any {
+	side := func(tree *rbtree.Tree) []L3Order {
+		result := []L3Order{}
+		iter := tree.Iterator()
+		for iter.Next() {
+			level := iter.Value().(*PriceLevel)
+			for e := level.Orders.Front(); e != nil; e = e.Next() {
+				o := e.Value.(*order.Order)
+				entry := book.l3Order(o, level)
+				if viewer != (common.Address{}) && o.CreatedBy == viewer {
+					maker := o.CreatedBy
+					entry.Maker = &maker
+					entry.Nonce = o.Nonce.String()
+				}
+				result = append(result, entry)
+			}
+		}
+		return result
+	}
+	return map[string]any{
+		"event":     "L3Snapshot",
+		"pair":      fmt.Sprintf("%s/%s", book.SymbolIn, book.SymbolOut),
+		"seq":       book.l3Seq,
+		"symbolIn":  book.SymbolIn,
+		"symbolOut": book.SymbolOut,
+		"bids":      side(book.Bids),
+		"asks":      side(book.Asks),
+	}
+}
+
+// AddL3Subscriber subscribes conn to the L3 feed; viewer is the signed-in address, or the zero
+// address for anonymous subscribers
+func (book *MarketOrderBook) AddL3Subscriber(conn api.WsSubscriber, viewer common.Address) {
+	book.l3Mu.Lock()
+	if _, exists := book.l3Subscribers[conn]; !exists {
+		metrics.Subscribers.WithLabelValues("l3").Inc()
+	}
+	book.l3Subscribers[conn] = viewer
+	book.l3Mu.Unlock()
+}
+
+func (book *MarketOrderBook) RemoveL3Subscriber(conn api.WsSubscriber) {
+	book.l3Mu.Lock()
+	if _, exists := book.l3Subscribers[conn]; exists {
+		metrics.Subscribers.WithLabelValues("l3").Dec()
+		delete(book.l3Subscribers, conn)
+	}
+	book.l3Mu.Unlock()
+}
+
+func (book *MarketOrderBook) StartL3Broadcast() {
+	pair := fmt.Sprintf("%s/%s", book.SymbolIn, book.SymbolOut)
+	go func() {
+		for event := range book.l3Ch {
+			public := event.encode(pair, false)
+			var owner []byte
+
+			book.l3Mu.Lock()
+			subscribers := make(map[api.WsSubscriber]common.Address, len(book.l3Subscribers))
+			for conn, viewer := range book.l3Subscribers {
+				subscribers[conn] = viewer
+			}
+			book.l3Mu.Unlock()
+
+			for conn, viewer := range subscribers {
+				msg := public
+				if viewer != (common.Address{}) && viewer == event.Maker {
+					if owner == nil {
+						owner = event.encode(pair, true)
+					}
+					msg = owner
+				}
+				if err := conn.WriteMessage(websocket.TextMessage, msg); err != nil {
+					book.RemoveL3Subscriber(conn)
+					conn.Close()
+				}
+			}
+		}
+	}()
+}
@@ -4,16 +4,22 @@ import (
 	"container/list"
 	"context"
 	"dexbe/internal/domains/order"
+	"dexbe/internal/domains/trade"
 	"dexbe/internal/infra/api"
+	"dexbe/internal/infra/audit"
 	"dexbe/internal/infra/eth/exchange"
-	"encoding/json"
+	"dexbe/internal/infra/metrics"
+	"dexbe/internal/infra/tracing"
 	"fmt"
 	"log"
 	"math/big"
 	"sync"
+	"time"
 
 	rbtree "github.com/emirpasic/gods/trees/redblacktree"
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/gorilla/websocket"
+	"go.opentelemetry.io/otel/attribute"
 )
 
 type MarketOrderBook struct {
@@ -23,8 +29,16 @@ type MarketOrderBook struct {
 	Asks        *rbtree.Tree
 	LastPrice   *big.Int
 	Mu          sync.RWMutex
-	subscribers map[*websocket.Conn]bool
-	updateCh    chan []byte
+	subscribers map[api.WsSubscriber]string // depth view key of each subscriber
+	updateCh    chan depthMessage
+	depthFeeds  map[string]*depthFeed
+	depthMu     sync.Mutex
+	subsMu      sync.Mutex // guards subscribers; taken after depthMu, and alone by the broadcaster
+
+	l3Seq         uint64
+	l3Subscribers map[api.WsSubscriber]common.Address
+	l3Ch          chan *l3Event
+	l3Mu          sync.Mutex
 }
 
 const PricePrecision = 18
@@ -50,23 +64,57 @@ func NewMarketOrderBook(symbolIn, symbolOut string) *MarketOrderBook {
 		Bids:        rbtree.NewWith(BigIntDescendingComparator), // Highest price first
 		Asks:        rbtree.NewWith(BigIntAscendingComparator),  // Lowest price first
 		LastPrice:   big.NewInt(0),
-		subscribers: map[*websocket.Conn]bool{},
-		updateCh:    make(chan []byte, 256),
+		subscribers: map[api.WsSubscriber]string{},
+		updateCh:    make(chan depthMessage, 256),
+		depthFeeds:  map[string]*depthFeed{"": {}},
+
+		l3Subscribers: map[api.WsSubscriber]common.Address{},
+		l3Ch:          make(chan *l3Event, 1024),
 	}
 	book.StartBroadcast()
+	book.StartL3Broadcast()
 	return book
 }
 
-func (book *MarketOrderBook) AddSubscriber(conn *websocket.Conn) {
-	book.Mu.Lock()
-	book.subscribers[conn] = true
-	book.Mu.Unlock()
+// AddSubscriber subscribes conn to the depth feed of view, starting that feed if it is the first
+// subscriber to ask for it
+func (book *MarketOrderBook) AddSubscriber(conn api.WsSubscriber, view DepthView) {
+	book.depthMu.Lock()
+	defer book.depthMu.Unlock()
+	key := view.Key()
+	feed, exists := book.depthFeeds[key]
+	if !exists {
+		feed = &depthFeed{view: view}
+		book.depthFeeds[key] = feed
+	}
+	feed.subscribers++
+	book.subsMu.Lock()
+	if _, exists := book.subscribers[conn]; !exists {
+		metrics.Subscribers.WithLabelValues("depth").Inc()
+	}
+	book.subscribers[conn] = key
+	book.subsMu.Unlock()
 }
 
-func (book *MarketOrderBook) RemoveSubscriber(conn *websocket.Conn) {
-	book.Mu.Lock()
+// RemoveSubscriber unsubscribes conn and stops its view's feed once nobody else uses it.
+// The raw feed is never stopped since its seq also backs REST snapshots.
+func (book *MarketOrderBook) RemoveSubscriber(conn api.WsSubscriber) {
+	book.depthMu.Lock()
+	defer book.depthMu.Unlock()
+	book.subsMu.Lock()
+	key, exists := book.subscribers[conn]
 	delete(book.subscribers, conn)
-	book.Mu.Unlock()
+	book.subsMu.Unlock()
+	if !exists {
+		return
+	}
+	metrics.Subscribers.WithLabelValues("depth").Dec()
+	if feed := book.depthFeeds[key]; feed != nil {
+		feed.subscribers--
+		if feed.subscribers <= 0 && key != "" {
+			delete(book.depthFeeds, key)
+		}
+	}
 }
 
 func (book *MarketOrderBook) Snapshot() map[string]any {
@@ -88,31 +136,26 @@ func (book *MarketOrderBook) Snapshot() map[string]any {
 func (book *MarketOrderBook) StartBroadcast() {
 	go func() {
 		for msg := range book.updateCh {
-			for conn := range book.subscribers {
-				err := conn.WriteMessage(websocket.TextMessage, msg)
-				if err != nil {
+			book.subsMu.Lock()
+			conns := make([]api.WsSubscriber, 0, len(book.subscribers))
+			for conn, key := range book.subscribers {
+				if key == msg.view {
+					conns = append(conns, conn)
+				}
+			}
+			book.subsMu.Unlock()
+
+			for _, conn := range conns {
+				// The connection's handler unsubscribes it on the way out; doing it here would take
+				// depthMu, which a publisher may hold while waiting on this loop
+				if err := conn.WriteMessage(websocket.TextMessage, msg.data); err != nil {
 					conn.Close()
-					delete(book.subscribers, conn)
 				}
 			}
 		}
 	}()
 }
 
-func (book *MarketOrderBook) NotifyUpdate(event string, data any) {
-	msg := map[string]any{
-		"event": event,
-		"pair":  fmt.Sprintf("%s/%s", book.SymbolIn, book.SymbolOut),
-		"data":  data,
-	}
-	encoded, _ := json.Marshal(msg)
-
-	select {
-	case book.updateCh <- encoded:
-	default:
-	}
-}
-
 func (book *MarketOrderBook) serializeSide(tree *rbtree.Tree) []map[string]any {
 	result := []map[string]any{}
 	iter := tree.Iterator()
@@ -169,6 +212,8 @@ type OrderBookStoreInterface interface {
 	AddOrder(*order.Order) error
 	StoreConditionalOrder(*order.Order, string) error
 	AddToPastHistory(*order.Order)
+	RecordTrade(*trade.Trade)
+	beginSettlement(orders int) func()
 }
 
 func matchBook(book *MarketOrderBook, exchange *exchange.ExchangeContract, store OrderBookStoreInterface) {
@@ -410,20 +455,60 @@ func matchBook(book *MarketOrderBook, exchange *exchange.ExchangeContract, store
 			bidOrder.CreatedBy.Hex()[:10], bidOrder.Nonce.String(),
 			tradeBaseQty.String())
 
+		// The match span runs until the fills are applied, in the trace of both orders
+		matchSpans := tracing.StartOrderSpans("orderbook.match",
+			[]string{tracing.OrderID(bidOrder.CreatedBy, bidOrder.Nonce), tracing.OrderID(askOrder.CreatedBy, askOrder.Nonce)},
+			tracing.AttrPair.String(book.SymbolIn+"/"+book.SymbolOut),
+			attribute.String("match.price", executionPrice.String()),
+			attribute.String("match.base_qty", tradeBaseQty.String()))
+		submitSpans := matchSpans.Child("exchange.ExecuteMatch")
+
 		// Use ExecuteMatch to submit the transaction
+		matchedAt := time.Now()
 		tx, err := exchange.ExecuteMatch(askOrder, bidOrder, tradeBaseQty)
 
 		if err != nil {
 			log.Printf("ERROR EXECUTING MATCH: %+v", err)
+			metrics.FailedTransactions.WithLabelValues("match", "submit").Inc()
+			submitSpans.Fail(err)
+			submitSpans.End()
+			matchSpans.Fail(err)
+			matchSpans.End()
+			audit.Record(audit.KindMatchSkipped, map[string]any{
+				"pair":    book.SymbolIn + "/" + book.SymbolOut,
+				"bidId":   getOrderKey(bidOrder),
+				"askId":   getOrderKey(askOrder),
+				"baseQty": tradeBaseQty.String(),
+				"error":   err.Error(),
+			})
 			bidOrder.Status = 0
 			askOrder.Status = 0
 			continue // Try next match
 		}
+		// Pending orders leave the visible depth until the match is confirmed or reverted
+		book.PublishDepth()
 		api.NotifyUpdate("TransactionChange", askOrder.CreatedBy, askOrder.ToStringMap())
 		api.NotifyUpdate("TransactionChange", bidOrder.CreatedBy, bidOrder.ToStringMap())
 
 		txHash := tx.Hash().Hex()
 		log.Printf("Transaction sent: %s", txHash)
+		submitSpans.SetAttributes(tracing.AttrTxHash.String(txHash))
+		submitSpans.End()
+		matchSpans.SetAttributes(tracing.AttrTxHash.String(txHash))
+		audit.Record(audit.KindMatch, map[string]any{
+			"pair":     book.SymbolIn + "/" + book.SymbolOut,
+			"bidId":    getOrderKey(bidOrder),
+			"askId":    getOrderKey(askOrder),
+			"bidPrice": bidPriceKey.String(),
+			"askPrice": askPriceKey.String(),
+			"price":    executionPrice.String(),
+			"baseQty":  tradeBaseQty.String(),
+			"quoteQty": tradeQuoteQty.String(),
+			"txHash":   txHash,
+		})
+		auditStatus(bidOrder, "PendingConfirmation", "match submitted", txHash)
+		auditStatus(askOrder, "PendingConfirmation", "match submitted", txHash)
+		metrics.Matches.WithLabelValues(book.SymbolIn + "/" + book.SymbolOut).Inc()
 
 		// Create copies of values needed in goroutine to avoid race conditions
 		finalBidOrder := bidOrder
@@ -439,12 +524,22 @@ func matchBook(book *MarketOrderBook, exchange *exchange.ExchangeContract, store
 		finalExecutionPrice := new(big.Int).Set(executionPrice)
 
 		// Goroutine to wait for confirmation
+		settled := store.beginSettlement(2)
 		go func() {
+			defer settled()
+			defer matchSpans.End()
+
 			// Wait for transaction receipt
+			receiptSpans := matchSpans.Child("eth.CheckTxReceipt", tracing.AttrTxHash.String(txHash))
 			minedStatus := make(chan int)
 			go exchange.Client.CheckTxReceipt(context.Background(), tx, minedStatus)
 
 			result := <-minedStatus
+			observeSettlement("match", matchedAt, result)
+			auditSettlement("match", txHash, result)
+			endReceiptSpans(receiptSpans, matchSpans, txHash, result)
+			fillSpans := matchSpans.Child(settlementStep(result), tracing.AttrTxHash.String(txHash))
+			defer fillSpans.End()
 
 			book.Mu.Lock()
 			defer book.Mu.Unlock()
@@ -472,6 +567,14 @@ func matchBook(book *MarketOrderBook, exchange *exchange.ExchangeContract, store
 				finalBidOrder.FilledAmtIn.Add(finalBidOrder.FilledAmtIn, finalTradeQuoteQty)
 				finalAskOrder.FilledAmtIn.Add(finalAskOrder.FilledAmtIn, finalTradeBaseQty)
 
+				// The order that reached the book last crossed the spread and is the aggressor
+				aggressorSide, makerOrder, takerOrder := trade.Buy, finalAskOrder, finalBidOrder
+				if finalAskOrder.CreatedAt.After(finalBidOrder.CreatedAt) {
+					aggressorSide, makerOrder, takerOrder = trade.Sell, finalBidOrder, finalAskOrder
+				}
+				store.RecordTrade(trade.NewTrade(book.SymbolIn, book.SymbolOut, makerOrder, takerOrder,
+					aggressorSide, finalExecutionPrice, finalTradeBaseQty, finalTradeQuoteQty, txHash))
+
 				log.Printf("  After: Bid %s/%s (%.1f%%) | Ask %s/%s (%.1f%%)",
 					finalBidOrder.FilledAmtIn.String(), finalBidOrder.AmtIn.String(),
 					percent(finalBidOrder.FilledAmtIn, finalBidOrder.AmtIn),
@@ -486,6 +589,17 @@ func matchBook(book *MarketOrderBook, exchange *exchange.ExchangeContract, store
 				bidNewRemaining := new(big.Int).Sub(finalBidOrder.AmtIn, finalBidOrder.FilledAmtIn)
 				askNewRemaining := new(big.Int).Sub(finalAskOrder.AmtIn, finalAskOrder.FilledAmtIn)
 
+				// Publish the fills to the L3 feed while both orders are still queued
+				bidEvent, askEvent := L3PartialFill, L3PartialFill
+				if bidNewRemaining.Sign() == 0 {
+					bidEvent = L3Fill
+				}
+				if askNewRemaining.Sign() == 0 {
+					askEvent = L3Fill
+				}
+				book.PublishL3(bidEvent, finalBidOrder, finalBidLevel, finalTradeQuoteQty)
+				book.PublishL3(askEvent, finalAskOrder, finalAskLevel, finalTradeBaseQty)
+
 				// Handle bid order completion
 				if bidNewRemaining.Cmp(big.NewInt(0)) == 0 {
 					// Fully filled - status 3, add to history, then set to 2
@@ -497,8 +611,6 @@ func matchBook(book *MarketOrderBook, exchange *exchange.ExchangeContract, store
 					log.Printf("**Order Fully Filled**: Bid %s/%s (100%%) - Added to history",
 						finalBidOrder.CreatedBy.Hex()[:10], finalBidOrder.Nonce.String())
 
-					book.NotifyUpdate("orderbook_update", book.Snapshot())
-
 					if finalBidOrder.ConditionalOrder != nil {
 						log.Printf("**Conditional Order Detected**: Storing conditional order for Bid %s/%s",
 							finalBidOrder.CreatedBy.Hex()[:10], finalBidOrder.Nonce.String())
@@ -534,8 +646,6 @@ func matchBook(book *MarketOrderBook, exchange *exchange.ExchangeContract, store
 					log.Printf("**Order Fully Filled**: Ask %s/%s (100%%) - Added to history",
 						finalAskOrder.CreatedBy.Hex()[:10], finalAskOrder.Nonce.String())
 
-					book.NotifyUpdate("orderbook_update", book.Snapshot())
-
 					if finalAskOrder.ConditionalOrder != nil {
 						log.Printf("**Conditional Order Detected**: Storing conditional order for Ask %s/%s",
 							finalAskOrder.CreatedBy.Hex()[:10], finalAskOrder.Nonce.String())
@@ -562,12 +672,11 @@ func matchBook(book *MarketOrderBook, exchange *exchange.ExchangeContract, store
 
 				if finalBidLevel.Orders.Len() == 0 {
 					book.Bids.Remove(finalBidPriceKey)
-					book.NotifyUpdate("orderbook_update", book.Snapshot())
 				}
 				if finalAskLevel.Orders.Len() == 0 {
 					book.Asks.Remove(finalAskPriceKey)
-					book.NotifyUpdate("orderbook_update", book.Snapshot())
 				}
+				book.PublishDepth()
 
 				api.NotifyUpdate("TransactionChange", finalAskOrder.CreatedBy, finalAskOrder.ToStringMap())
 				api.NotifyUpdate("TransactionChange", finalBidOrder.CreatedBy, finalBidOrder.ToStringMap())
@@ -583,6 +692,9 @@ func matchBook(book *MarketOrderBook, exchange *exchange.ExchangeContract, store
 				log.Printf("❌ Transaction %s failed or reverted", txHash)
 				finalBidOrder.Status = 0
 				finalAskOrder.Status = 0
+				auditStatus(finalBidOrder, "Matching", "match reverted", txHash)
+				auditStatus(finalAskOrder, "Matching", "match reverted", txHash)
+				book.PublishDepth()
 
 				api.NotifyUpdate("TransactionChange", finalAskOrder.CreatedBy, finalAskOrder.ToStringMap())
 				api.NotifyUpdate("TransactionChange", finalBidOrder.CreatedBy, finalBidOrder.ToStringMap())
@@ -0,0 +1,87 @@
+package orderbook
+
+import (
+	"dexbe/internal/domains/order"
+	"dexbe/internal/infra/metrics"
+	"time"
+
+	rbtree "github.com/emirpasic/gods/trees/redblacktree"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	bookLevelsDesc = prometheus.NewDesc("dex_book_levels", "Price levels per book side.",
+		[]string{"pair", "side"}, nil)
+	bookOrdersDesc = prometheus.NewDesc("dex_book_orders", "Resting orders per book side, pending settlement excluded.",
+		[]string{"pair", "side"}, nil)
+	bookQueueDesc = prometheus.NewDesc("dex_book_queue_length", "Messages waiting in a book's depth or L3 broadcast queue.",
+		[]string{"pair", "queue"}, nil)
+	conditionalOrdersDesc = prometheus.NewDesc("dex_conditional_orders", "Conditional orders waiting for their trigger.",
+		nil, nil)
+)
+
+// observeSettlement records how long a match or ring took from being found to its receipt, and
+// counts it as failed if it reverted
+func observeSettlement(kind string, foundAt time.Time, result int) {
+	outcome := "confirmed"
+	if result != 1 {
+		outcome = "reverted"
+		metrics.FailedTransactions.WithLabelValues(kind, "reverted").Inc()
+	}
+	metrics.SettlementLatency.WithLabelValues(kind, outcome).Observe(time.Since(foundAt).Seconds())
+}
+
+// storeCollector reads book depth, broadcast backlogs and the conditional order count at scrape time
+type storeCollector struct {
+	store *OrderBookStore
+}
+
+// Collector exposes the store's books and conditional orders to Prometheus
+func (store *OrderBookStore) Collector() prometheus.Collector {
+	return &storeCollector{store: store}
+}
+
+func (c *storeCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- bookLevelsDesc
+	ch <- bookOrdersDesc
+	ch <- bookQueueDesc
+	ch <- conditionalOrdersDesc
+}
+
+func (c *storeCollector) Collect(ch chan<- prometheus.Metric) {
+	c.store.mu.RLock()
+	books := make(map[string]*MarketOrderBook, len(c.store.Books))
+	for pair, book := range c.store.Books {
+		books[pair] = book
+	}
+	c.store.mu.RUnlock()
+
+	for pair, book := range books {
+		book.Mu.RLock()
+		for side, tree := range map[string]*rbtree.Tree{"bid": book.Bids, "ask": book.Asks} {
+			ch <- prometheus.MustNewConstMetric(bookLevelsDesc, prometheus.GaugeValue, float64(tree.Size()), pair, side)
+			ch <- prometheus.MustNewConstMetric(bookOrdersDesc, prometheus.GaugeValue, float64(restingOrders(tree)), pair, side)
+		}
+		book.Mu.RUnlock()
+		ch <- prometheus.MustNewConstMetric(bookQueueDesc, prometheus.GaugeValue, float64(len(book.updateCh)), pair, "depth")
+		ch <- prometheus.MustNewConstMetric(bookQueueDesc, prometheus.GaugeValue, float64(len(book.l3Ch)), pair, "l3")
+	}
+
+	if c.store.ConditionalOrderStore != nil {
+		ch <- prometheus.MustNewConstMetric(conditionalOrdersDesc, prometheus.GaugeValue,
+			float64(c.store.ConditionalOrderStore.GetConditionalOrderCount()))
+	}
+}
+
+func restingOrders(tree *rbtree.Tree) int {
+	count := 0
+	iter := tree.Iterator()
+	for iter.Next() {
+		for e := iter.Value().(*PriceLevel).Orders.Front(); e != nil; e = e.Next() {
+			if e.Value.(*order.Order).Status == 0 {
+				count++
+			}
+		}
+	}
+	return count
+}
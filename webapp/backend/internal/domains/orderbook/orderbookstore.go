@@ -4,16 +4,25 @@ import (
 	"container/list"
 	"context"
 	"dexbe/internal/domains/order"
+	"dexbe/internal/domains/trade"
 	"dexbe/internal/infra/api"
+	"dexbe/internal/infra/audit"
 	"dexbe/internal/infra/eth/exchange"
+	"dexbe/internal/infra/metrics"
+	"dexbe/internal/infra/tracing"
+	"errors"
 	"fmt"
 	"log"
 	"math/big"
+	"sort"
+	"strconv"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	rbtree "github.com/emirpasic/gods/trees/redblacktree"
 	"github.com/ethereum/go-ethereum/common"
+	"go.opentelemetry.io/otel/attribute"
 )
 
 // UnmatchedOrder represents an order that couldn't be fully matched in its book
@@ -35,6 +44,8 @@ type RingPath struct {
 	TradeAmounts []*big.Int
 }
 
+var ErrOrderNotFound = errors.New("order not found")
+
 type OrderBookStore struct {
 	Books                 map[string]*MarketOrderBook
 	Exchange              *exchange.ExchangeContract
@@ -45,14 +56,23 @@ type OrderBookStore struct {
 	ringMatchingEnabled   bool
 	ConditionalOrderStore *ConditionalOrderStore
 	PastHistoryStore      map[common.Address]map[string][]order.Order
+	Trades                *trade.TradeStore
+
+	stopping       atomic.Bool
+	oracleDone     chan struct{}
+	lastMatchCycle atomic.Int64 // unix nanos
+	settlements    sync.WaitGroup
+	inFlight       map[*settlement]bool
+	settlementsMu  sync.Mutex
 }
 
 type MarketPrice struct {
-	BestBid   *big.Int
-	BestAsk   *big.Int
-	MidPrice  *big.Int
-	Spread    *big.Int
-	LastPrice *big.Int
+	BestBid     *big.Int
+	BestAsk     *big.Int
+	MidPrice    *big.Int
+	Spread      *big.Int
+	LastPrice   *big.Int
+	LastTradeAt *time.Time
 }
 
 func NewOrderBookStore(exchange *exchange.ExchangeContract, symbols []string) *OrderBookStore {
@@ -62,6 +82,8 @@ func NewOrderBookStore(exchange *exchange.ExchangeContract, symbols []string) *O
 		maxRingDepth:        5,    // default max depth of 5
 		ringMatchingEnabled: true, // enable by default
 		PastHistoryStore:    make(map[common.Address]map[string][]order.Order),
+		Trades:              trade.NewTradeStore(),
+		inFlight:            make(map[*settlement]bool),
 	}
 
 	// Initialize conditional order store
@@ -105,6 +127,25 @@ func (store *OrderBookStore) AddToPastHistory(o *order.Order) {
 
 	log.Printf("**History**: Added order snapshot to PastHistoryStore - Address: %s, Nonce: %s, Status: %d",
 		o.CreatedBy.Hex()[:10], nonceKey, o.Status)
+
+	// Every fill and cancel passes through here, so this is where an order's trace is closed
+	traceID := tracing.OrderID(o.CreatedBy, o.Nonce)
+	switch o.Status {
+	case 3: // fully filled
+		auditStatus(o, "Completed", "filled", latestTx(o))
+		tracing.EndOrder(traceID, "filled", nil)
+	case 4: // cancelled
+		auditStatus(o, "Cancelled", "signed cancel", "")
+		tracing.EndOrder(traceID, "cancelled", nil)
+	case 5: // partially filled
+		auditStatus(o, "PartialFill", "filled", latestTx(o))
+		tracing.OrderEvent(traceID, "partial fill", attribute.String("order.filled_amt_in", o.FilledAmtIn.String()))
+	}
+}
+
+// RecordTrade adds a confirmed fill to the public trade tape
+func (store *OrderBookStore) RecordTrade(t *trade.Trade) {
+	store.Trades.Record(t)
 }
 
 // GetOrderHistory retrieves all historical snapshots for a specific order
@@ -146,6 +187,25 @@ func (store *OrderBookStore) SetMaxRingDepth(depth int) {
 	log.Printf("Max ring depth set to %d", depth)
 }
 
+// GetMatchingSettings returns whether ring matching is enabled and the max ring depth
+func (store *OrderBookStore) GetMatchingSettings() (bool, int) {
+	store.mu.RLock()
+	defer store.mu.RUnlock()
+	return store.ringMatchingEnabled, store.maxRingDepth
+}
+
+// Pairs returns the ids of every book, e.g. "TKA/TKB"
+func (store *OrderBookStore) Pairs() []string {
+	store.mu.RLock()
+	defer store.mu.RUnlock()
+	pairs := make([]string, 0, len(store.Books))
+	for pairId := range store.Books {
+		pairs = append(pairs, pairId)
+	}
+	sort.Strings(pairs)
+	return pairs
+}
+
 // GetPairKey returns base and quote tokens in canonical order
 // The FIRST token returned is always the BASE, second is QUOTE
 func GetPairKey(tokenA, tokenB string) (base, quote string) {
@@ -156,9 +216,11 @@ func GetPairKey(tokenA, tokenB string) (base, quote string) {
 }
 
 func (store *OrderBookStore) StartOracle(ctx context.Context, interval time.Duration) {
+	store.oracleDone = make(chan struct{})
 	go func() {
 		ticker := time.NewTicker(interval)
 		defer ticker.Stop()
+		defer close(store.oracleDone)
 		log.Println("**Oracle Started**: Matching engine running")
 		for {
 			select {
@@ -167,6 +229,7 @@ func (store *OrderBookStore) StartOracle(ctx context.Context, interval time.Dura
 				return
 			case <-ticker.C:
 				store.matchAllBooks()
+				store.lastMatchCycle.Store(time.Now().UnixNano())
 			}
 		}
 	}()
@@ -300,6 +363,7 @@ func (store *OrderBookStore) findAndExecuteOneRing(unmatched []*UnmatchedOrder)
 
 		if ring != nil {
 			//log.Printf(" Found ring with %d orders", len(ring.Orders))
+			metrics.RingsFound.WithLabelValues(strconv.Itoa(len(ring.Orders))).Inc()
 
 			// Execute the ring
 			err := store.executeRing(ring)
@@ -647,6 +711,7 @@ func (store *OrderBookStore) calculateRingBottleneck(ring *RingPath) *big.Int {
 }
 
 func (store *OrderBookStore) executeRing(ring *RingPath) error {
+	foundAt := time.Now()
 	bottleneck := store.calculateRingBottleneck(ring)
 	if bottleneck.Cmp(big.NewInt(0)) <= 0 {
 		return fmt.Errorf("invalid ring: no tradeable amount")
@@ -704,6 +769,16 @@ func (store *OrderBookStore) executeRing(ring *RingPath) error {
 			order.FilledAmtIn.String(), order.AmtIn.String())
 	}
 
+	// The ring span runs until the fills are applied, in the trace of every order in the ring
+	ringIDs := make([]string, len(ring.Orders))
+	for i, order := range ring.Orders {
+		ringIDs[i] = tracing.OrderID(order.CreatedBy, order.Nonce)
+	}
+	ringSpans := tracing.StartOrderSpans("orderbook.ring", ringIDs,
+		attribute.Int("ring.size", len(ring.Orders)),
+		attribute.String("ring.path", store.ringToString(ring)))
+	submitSpans := ringSpans.Child("exchange.ExecuteRingTrade")
+
 	// Mark all orders as PENDING before sending transaction
 	for _, order := range ring.Orders {
 		order.Status = 1
@@ -716,12 +791,24 @@ func (store *OrderBookStore) executeRing(ring *RingPath) error {
 		for _, order := range ring.Orders {
 			order.Status = 0
 		}
-		return fmt.Errorf("exchange contract not initialized")
+		err := fmt.Errorf("exchange contract not initialized")
+		submitSpans.Fail(err)
+		submitSpans.End()
+		ringSpans.Fail(err)
+		ringSpans.End()
+		audit.Record(audit.KindRingSkipped, map[string]any{"orderIds": ringIDs, "fillAmounts": bigStrings(fillAmounts), "error": err.Error()})
+		return err
 	}
 
 	tx, err := store.Exchange.ExecuteRingTrade(ring.Orders, fillAmounts)
 	if err != nil {
 		log.Printf("ERROR EXECUTING RING TRADE: %+v", err)
+		metrics.FailedTransactions.WithLabelValues("ring", "submit").Inc()
+		submitSpans.Fail(err)
+		submitSpans.End()
+		ringSpans.Fail(err)
+		ringSpans.End()
+		audit.Record(audit.KindRingSkipped, map[string]any{"orderIds": ringIDs, "fillAmounts": bigStrings(fillAmounts), "error": err.Error()})
 		// Reset status on immediate error
 		for _, order := range ring.Orders {
 			order.Status = 0
@@ -731,8 +818,24 @@ func (store *OrderBookStore) executeRing(ring *RingPath) error {
 
 	txHash := tx.Hash().Hex()
 	log.Printf(" Submitted Ring Trade TX: %s", txHash)
+	submitSpans.SetAttributes(tracing.AttrTxHash.String(txHash))
+	submitSpans.End()
+	ringSpans.SetAttributes(tracing.AttrTxHash.String(txHash))
+	audit.Record(audit.KindRing, map[string]any{
+		"orderIds":    ringIDs,
+		"path":        store.ringToString(ring),
+		"bottleneck":  bottleneck.String(),
+		"fillAmounts": bigStrings(fillAmounts),
+		"txHash":      txHash,
+	})
+	for _, order := range ring.Orders {
+		auditStatus(order, "PendingConfirmation", "ring submitted", txHash)
+	}
 
 	// Notify all users that their orders are pending
+	for _, book := range ring.Books {
+		book.PublishDepth()
+	}
 	for _, order := range ring.Orders {
 		api.NotifyUpdate("TransactionChange", order.CreatedBy, order.ToStringMap())
 	}
@@ -753,12 +856,22 @@ func (store *OrderBookStore) executeRing(ring *RingPath) error {
 	}
 
 	// Launch async goroutine to wait for confirmation
+	settled := store.beginSettlement(len(finalOrders))
 	go func() {
+		defer settled()
+		defer ringSpans.End()
+
 		// Wait for transaction receipt
+		receiptSpans := ringSpans.Child("eth.CheckTxReceipt", tracing.AttrTxHash.String(txHash))
 		minedStatus := make(chan int)
 		go store.Exchange.Client.CheckTxReceipt(context.Background(), tx, minedStatus)
 
 		result := <-minedStatus
+		observeSettlement("ring", foundAt, result)
+		auditSettlement("ring", txHash, result)
+		endReceiptSpans(receiptSpans, ringSpans, txHash, result)
+		fillSpans := ringSpans.Child(settlementStep(result), tracing.AttrTxHash.String(txHash))
+		defer fillSpans.End()
 
 		// Re-acquire locks for all books involved
 		for _, book := range finalBooks {
@@ -784,12 +897,29 @@ func (store *OrderBookStore) executeRing(ring *RingPath) error {
 					order.FilledAmtIn.String(), order.AmtIn.String(),
 					percent(order.FilledAmtIn, order.AmtIn))
 
+				// Record the leg on its book's trade tape
+				legBook := finalBooks[i]
+				legOut := new(big.Int).Mul(finalFillAmounts[i], order.AmtOut)
+				legOut.Div(legOut, order.AmtIn)
+				legBaseQty, legQuoteQty := finalFillAmounts[i], legOut
+				if order.SymbolIn == legBook.SymbolOut {
+					legBaseQty, legQuoteQty = legOut, finalFillAmounts[i]
+				}
+				store.RecordTrade(trade.NewRingLegTrade(legBook.SymbolIn, legBook.SymbolOut, order,
+					order.LimitPrice, legBaseQty, legQuoteQty, len(finalOrders), txHash))
+
 				// Update price level total quantity
 				finalPriceLevels[i].TotalQuantity.Sub(
 					finalPriceLevels[i].TotalQuantity,
 					finalFillAmounts[i],
 				)
 
+				legEvent := L3PartialFill
+				if remaining.Sign() == 0 {
+					legEvent = L3Fill
+				}
+				legBook.PublishL3(legEvent, order, finalPriceLevels[i], finalFillAmounts[i])
+
 				if remaining.Cmp(big.NewInt(0)) == 0 {
 					// Fully filled - status 3, add to history, then set to 2
 					order.Status = 3
@@ -853,9 +983,9 @@ func (store *OrderBookStore) executeRing(ring *RingPath) error {
 					}
 				}
 
-				// Send orderbook update notification (only once per book)
+				// Publish depth changes (only once per book)
 				if !processedBooks[book] {
-					book.NotifyUpdate("RingMatch", book.Snapshot())
+					book.PublishDepth()
 					processedBooks[book] = true
 				}
 			}
@@ -866,8 +996,12 @@ func (store *OrderBookStore) executeRing(ring *RingPath) error {
 			// Reset all orders to active status
 			for _, order := range finalOrders {
 				order.Status = 0
+				auditStatus(order, "Matching", "ring reverted", txHash)
 				api.NotifyUpdate("TransactionChange", order.CreatedBy, order.ToStringMap())
 			}
+			for _, book := range finalBooks {
+				book.PublishDepth()
+			}
 		}
 	}()
 
@@ -905,10 +1039,23 @@ func (store *OrderBookStore) InitializeBook(tokenA, tokenB string) {
 	}
 }
 
-func (store *OrderBookStore) AddOrder(orderIn *order.Order) error {
+func (store *OrderBookStore) AddOrder(orderIn *order.Order) (err error) {
 	base, quote := GetPairKey(orderIn.SymbolIn, orderIn.SymbolOut)
 	pairID := base + "/" + quote
 
+	traceID := tracing.OrderID(orderIn.CreatedBy, orderIn.Nonce)
+	traceCtx, opened := tracing.StartOrder(traceID, tracing.AttrPair.String(pairID))
+	_, span := tracing.Start(traceCtx, "orderbook.AddOrder", tracing.AttrOrderID.String(traceID), tracing.AttrPair.String(pairID))
+	defer func() {
+		if err != nil {
+			tracing.Fail(span, err)
+			if opened {
+				tracing.EndOrder(traceID, "rejected", err)
+			}
+		}
+		span.End()
+	}()
+
 	store.mu.RLock()
 	book, exists := store.Books[pairID]
 	store.mu.RUnlock()
@@ -918,19 +1065,40 @@ func (store *OrderBookStore) AddOrder(orderIn *order.Order) error {
 		return fmt.Errorf("order book for %s not initialized", pairID)
 	}
 
-	orderId := orderIn.CreatedBy.String() + "/" + orderIn.Nonce.String()
-
 	book.Mu.Lock()
 	defer book.Mu.Unlock()
 
+	pl, side, priceKey, err := book.insertOrder(orderIn)
+	if err != nil {
+		return err
+	}
+	book.PublishDepth()
+	book.PublishL3(L3Add, orderIn, pl, nil)
+	api.NotifyUpdate("OrderAdd", orderIn.CreatedBy, orderIn.ToStringMap())
+	audit.Record(audit.KindOrderAccepted, map[string]any{
+		"orderId":  getOrderKey(orderIn),
+		"pair":     pairID,
+		"side":     side,
+		"priceKey": priceKey.String(),
+		"order":    orderIn.ToStringMap(),
+	})
+	return nil
+}
+
+// insertOrder queues an order at the back of its price level. The caller holds book.Mu.
+func (book *MarketOrderBook) insertOrder(orderIn *order.Order) (pl *PriceLevel, side string, priceKey *big.Int, err error) {
+	base, quote := book.SymbolIn, book.SymbolOut
+	orderId := orderIn.CreatedBy.String() + "/" + orderIn.Nonce.String()
+
 	// Initialize FilledAmtIn if not set (new orders start with 0 filled)
 	if orderIn.FilledAmtIn == nil {
 		orderIn.FilledAmtIn = big.NewInt(0)
 	}
+	if orderIn.CreatedAt.IsZero() {
+		orderIn.CreatedAt = time.Now().UTC()
+	}
 
 	var isBid bool
-	var priceKey *big.Int
-	var side string
 
 	if orderIn.SymbolIn == base && orderIn.SymbolOut == quote {
 		// SELL/ASK: Giving base, wanting quote
@@ -959,7 +1127,7 @@ func (store *OrderBookStore) AddOrder(orderIn *order.Order) error {
 		priceBigFloat.Int(priceKey)
 
 	} else {
-		return fmt.Errorf("invalid order: tokens don't match book %s/%s", base, quote)
+		return nil, "", nil, fmt.Errorf("invalid order: tokens don't match book %s/%s", base, quote)
 	}
 
 	// Store the price key on the order
@@ -993,20 +1161,18 @@ func (store *OrderBookStore) AddOrder(orderIn *order.Order) error {
 	// Note: TotalQuantity should track remaining amounts, not original
 	val, found := tree.Get(priceKey)
 	if !found {
-		pl := &PriceLevel{
+		pl = &PriceLevel{
 			Orders:        list.New(),
 			TotalQuantity: new(big.Int).Set(remainingIn), // Use remaining, not original
 		}
 		pl.Orders.PushBack(orderIn)
 		tree.Put(priceKey, pl)
 	} else {
-		pl := val.(*PriceLevel)
+		pl = val.(*PriceLevel)
 		pl.Orders.PushBack(orderIn)
 		pl.TotalQuantity.Add(pl.TotalQuantity, remainingIn) // Add remaining, not original
 	}
-	book.NotifyUpdate("Add", book.Snapshot())
-	api.NotifyUpdate("OrderAdd", orderIn.CreatedBy, orderIn.ToStringMap())
-	return nil
+	return pl, side, priceKey, nil
 }
 
 // StoreConditionalOrder stores a conditional order for later execution when price conditions are met
@@ -1062,31 +1228,40 @@ func (store *OrderBookStore) StoreConditionalOrder(conditionalOrder *order.Order
 	)
 }
 
-// RemoveOrder removes an order from the order book using minimal identifiers
-func (store *OrderBookStore) RemoveOrder(createdBy common.Address, nonce *big.Int, limitPrice *big.Int, tokenA, tokenB string) {
+// RemoveOrder removes an order from the order book using minimal identifiers.
+// cancelSignature is the creator's signed Cancel and is kept on the history record.
+func (store *OrderBookStore) RemoveOrder(createdBy common.Address, nonce *big.Int, limitPrice *big.Int, tokenA, tokenB string, cancelSignature []byte) (err error) {
 	base, quote := GetPairKey(tokenA, tokenB)
 	pairID := base + "/" + quote
 
+	traceID := tracing.OrderID(createdBy, nonce)
+	_, span := tracing.Start(tracing.OrderContext(traceID), "orderbook.RemoveOrder", tracing.AttrOrderID.String(traceID), tracing.AttrPair.String(pairID))
+	defer func() {
+		if err != nil {
+			tracing.Fail(span, err)
+		}
+		span.End()
+	}()
+
 	store.mu.RLock()
 	book, exists := store.Books[pairID]
 	store.mu.RUnlock()
 
 	if !exists {
 		log.Printf("**Order Rejected**: Book for %s not initialized", pairID)
-		return
+		return fmt.Errorf("order book for %s not initialized", pairID)
 	}
 
 	orderId := createdBy.String() + "/" + nonce.String()
+	if limitPrice == nil {
+		log.Printf("**Error**: Order %s has no price key", orderId)
+		return fmt.Errorf("order %s has no price key", orderId)
+	}
 	log.Printf("**Order Removal**: Removing %s from %s at price %s", orderId, pairID, limitPrice.String())
 
 	book.Mu.Lock()
 	defer book.Mu.Unlock()
 
-	if limitPrice == nil {
-		log.Printf("**Error**: Order %s has no price key", orderId)
-		return
-	}
-
 	// Try to find the order in both bids and asks
 	// We'll search both since we dk which side without full order details
 	var foundOrder *order.Order
@@ -1130,11 +1305,12 @@ func (store *OrderBookStore) RemoveOrder(createdBy common.Address, nonce *big.In
 
 	if foundOrder == nil {
 		log.Printf("**Warning**: Order %s not found at price level %s", orderId, limitPrice.String())
-		return
+		return fmt.Errorf("%w: %s at price level %s", ErrOrderNotFound, orderId, limitPrice.String())
 	}
 
-	// Set status to 4 (cancelled) and add to history
+	// Set status to 4 (cancelled) and add to history with the signed cancel
 	foundOrder.Status = 4
+	foundOrder.CancelSignature = cancelSignature
 	store.AddToPastHistory(foundOrder)
 
 	// Calculate remaining amount to remove from TotalQuantity
@@ -1144,6 +1320,7 @@ func (store *OrderBookStore) RemoveOrder(createdBy common.Address, nonce *big.In
 	quantityToRemove := new(big.Int).Sub(foundOrder.AmtIn, foundOrder.FilledAmtIn)
 
 	// Remove the order from the list
+	book.PublishL3(L3Cancel, foundOrder, foundLevel, nil)
 	foundLevel.Orders.Remove(foundElem)
 
 	log.Printf("**Order Removed**: %s from %s (was %s/%s filled) - Added to history with status 4 (cancelled)",
@@ -1158,8 +1335,9 @@ func (store *OrderBookStore) RemoveOrder(createdBy common.Address, nonce *big.In
 		log.Printf("   Removed empty price level %s from %s", limitPrice.String(), pairID)
 	}
 
-	book.NotifyUpdate("Remove", book.Snapshot())
+	book.PublishDepth()
 	api.NotifyUpdate("OrderRemove", createdBy, map[string]any{"nonce": nonce})
+	return nil
 }
 
 // GetOrdersByCreator returns all orders for a given creator across all books
@@ -1285,6 +1463,13 @@ func (store *OrderBookStore) GetMarketPrice(tokenA, tokenB string) (*MarketPrice
 		price.Spread = new(big.Int).Sub(price.BestAsk, price.BestBid)
 	}
 
+	// Last trade from the tape, which also covers ring legs that do not move book.LastPrice
+	if last := store.Trades.Last(pairID); last != nil {
+		price.LastPrice = new(big.Int).Set(last.Price)
+		lastTradeAt := last.Timestamp
+		price.LastTradeAt = &lastTradeAt
+	}
+
 	return price, nil
 }
 
@@ -1332,8 +1517,8 @@ func (store *OrderBookStore) GetPriceFloat(tokenA, tokenB string) (map[string]fl
 	return result, nil
 }
 
-// GetOrderBookSnapshot returns top depth levels of bids and asks with prices
-func (store *OrderBookStore) GetOrderBookSnapshot(tokenA, tokenB string, depth int) (map[string]any, error) {
+// GetOrderBookSnapshot returns the book aggregated by view, e.g. the top 20 levels in 0.01 buckets
+func (store *OrderBookStore) GetOrderBookSnapshot(tokenA, tokenB string, view DepthView) (map[string]any, error) {
 	base, quote := GetPairKey(tokenA, tokenB)
 	pairID := base + "/" + quote
 
@@ -1348,54 +1533,8 @@ func (store *OrderBookStore) GetOrderBookSnapshot(tokenA, tokenB string, depth i
 	book.Mu.RLock()
 	defer book.Mu.RUnlock()
 
-	snapshot := make(map[string]any)
-	snapshot["pair"] = pairID
+	snapshot := book.DepthSnapshot(view)
 	snapshot["base"] = base
 	snapshot["quote"] = quote
-
-	bids := []map[string]string{}
-	bidIterator := book.Bids.Iterator()
-	count := 0
-	for bidIterator.Next() && count < depth {
-		priceKey := bidIterator.Key().(*big.Int)
-		level := bidIterator.Value().(*PriceLevel)
-
-		priceFloat := new(big.Float).Quo(
-			new(big.Float).SetInt(priceKey),
-			new(big.Float).SetInt(PriceFactor),
-		)
-		priceStr, _ := priceFloat.Float64()
-
-		bids = append(bids, map[string]string{
-			"price":    fmt.Sprintf("%.6f", priceStr),
-			"quantity": level.TotalQuantity.String(),
-			"orders":   fmt.Sprintf("%d", level.Orders.Len()),
-		})
-		count++
-	}
-	snapshot["bids"] = bids
-
-	asks := []map[string]string{}
-	askIterator := book.Asks.Iterator()
-	count = 0
-	for askIterator.Next() && count < depth {
-		priceKey := askIterator.Key().(*big.Int)
-		level := askIterator.Value().(*PriceLevel)
-
-		priceFloat := new(big.Float).Quo(
-			new(big.Float).SetInt(priceKey),
-			new(big.Float).SetInt(PriceFactor),
-		)
-		priceStr, _ := priceFloat.Float64()
-
-		asks = append(asks, map[string]string{
-			"price":    fmt.Sprintf("%.6f", priceStr),
-			"quantity": level.TotalQuantity.String(),
-			"orders":   fmt.Sprintf("%d", level.Orders.Len()),
-		})
-		count++
-	}
-	snapshot["asks"] = asks
-
 	return snapshot, nil
 }
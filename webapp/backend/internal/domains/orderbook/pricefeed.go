@@ -0,0 +1,170 @@
+package orderbook
+
+import (
+	"dexbe/internal/infra/api"
+	"dexbe/internal/infra/metrics"
+	"encoding/json"
+	"log"
+	"math/big"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// priceFeedInterval is how often changed price summaries are streamed
+const priceFeedInterval = time.Second
+
+// PriceValue is a price in the 1e18 price scale as the raw integer and as an exact decimal string
+type PriceValue struct {
+	Raw     string `json:"raw"`
+	Decimal string `json:"decimal"`
+}
+
+func NewPriceValue(price *big.Int) *PriceValue {
+	if price == nil {
+		return nil
+	}
+	return &PriceValue{
+		Raw:     price.String(),
+		Decimal: FormatPrice(price),
+	}
+}
+
+// FormatPrice renders a 1e18-scaled price as an exact decimal without trailing zeros, e.g. "1.25"
+func FormatPrice(price *big.Int) string {
+	abs := new(big.Int).Abs(price)
+	whole, frac := new(big.Int).QuoRem(abs, PriceFactor, new(big.Int))
+	result := whole.String()
+	if frac.Sign() != 0 {
+		digits := frac.String()
+		digits = strings.Repeat("0", PricePrecision-len(digits)) + digits
+		result += "." + strings.TrimRight(digits, "0")
+	}
+	if price.Sign() < 0 {
+		result = "-" + result
+	}
+	return result
+}
+
+// PriceSummary is the top of book and last trade of one pair. Fields are null when the book side is
+// empty or the pair never traded.
+type PriceSummary struct {
+	Pair        string      `json:"pair"`
+	Base        string      `json:"base"`
+	Quote       string      `json:"quote"`
+	BestBid     *PriceValue `json:"bestBid"`
+	BestAsk     *PriceValue `json:"bestAsk"`
+	MidPrice    *PriceValue `json:"midPrice"`
+	Spread      *PriceValue `json:"spread"`
+	LastPrice   *PriceValue `json:"lastPrice"`
+	LastTradeAt *time.Time  `json:"lastTradeAt"`
+}
+
+// GetPriceSummary returns best bid/ask, mid, spread and last trade of a pair
+func (store *OrderBookStore) GetPriceSummary(tokenA, tokenB string) (*PriceSummary, error) {
+	base, quote := GetPairKey(tokenA, tokenB)
+	price, err := store.GetMarketPrice(base, quote)
+	if err != nil {
+		return nil, err
+	}
+	return &PriceSummary{
+		Pair:        base + "/" + quote,
+		Base:        base,
+		Quote:       quote,
+		BestBid:     NewPriceValue(price.BestBid),
+		BestAsk:     NewPriceValue(price.BestAsk),
+		MidPrice:    NewPriceValue(price.MidPrice),
+		Spread:      NewPriceValue(price.Spread),
+		LastPrice:   NewPriceValue(price.LastPrice),
+		LastTradeAt: price.LastTradeAt,
+	}, nil
+}
+
+// GetPriceSummaries returns the price summary of every book, ordered by pair
+func (store *OrderBookStore) GetPriceSummaries() []*PriceSummary {
+	summaries := []*PriceSummary{}
+	for _, pairId := range store.Pairs() {
+		base, quote, _ := strings.Cut(pairId, "/")
+		if summary, err := store.GetPriceSummary(base, quote); err == nil {
+			summaries = append(summaries, summary)
+		}
+	}
+	return summaries
+}
+
+// PriceFeed streams price summaries to websocket subscribers whenever they change
+type PriceFeed struct {
+	OrderBookStore *OrderBookStore
+	subscribers    map[api.WsSubscriber]string // pair filter, "" for every pair
+	lastPushed     map[string][]byte
+	mu             sync.Mutex
+}
+
+func NewPriceFeed(store *OrderBookStore) *PriceFeed {
+	feed := &PriceFeed{
+		OrderBookStore: store,
+		subscribers:    make(map[api.WsSubscriber]string),
+		lastPushed:     make(map[string][]byte),
+	}
+	feed.StartBroadcast()
+	return feed
+}
+
+// AddSubscriber subscribes conn to one pair, or to every pair when pair is ""
+func (feed *PriceFeed) AddSubscriber(conn api.WsSubscriber, pair string) {
+	feed.mu.Lock()
+	defer feed.mu.Unlock()
+	if _, exists := feed.subscribers[conn]; !exists {
+		metrics.Subscribers.WithLabelValues("prices").Inc()
+	}
+	feed.subscribers[conn] = pair
+}
+
+func (feed *PriceFeed) RemoveSubscriber(conn api.WsSubscriber) {
+	feed.mu.Lock()
+	defer feed.mu.Unlock()
+	if _, exists := feed.subscribers[conn]; exists {
+		metrics.Subscribers.WithLabelValues("prices").Dec()
+		delete(feed.subscribers, conn)
+	}
+}
+
+func (feed *PriceFeed) StartBroadcast() {
+	go func() {
+		interval := time.NewTicker(priceFeedInterval)
+		defer interval.Stop()
+		for range interval.C {
+			for _, summary := range feed.OrderBookStore.GetPriceSummaries() {
+				encoded, _ := json.Marshal(map[string]any{
+					"event": "Price",
+					"pair":  summary.Pair,
+					"data":  summary,
+				})
+
+				feed.mu.Lock()
+				changed := string(feed.lastPushed[summary.Pair]) != string(encoded)
+				feed.lastPushed[summary.Pair] = encoded
+				conns := []api.WsSubscriber{}
+				for conn, pair := range feed.subscribers {
+					if pair == "" || pair == summary.Pair {
+						conns = append(conns, conn)
+					}
+				}
+				feed.mu.Unlock()
+				if !changed {
+					continue
+				}
+
+				for _, conn := range conns {
+					if err := conn.WriteMessage(websocket.TextMessage, encoded); err != nil {
+						log.Printf("**Price Feed**: dropping subscriber: %v", err)
+						feed.RemoveSubscriber(conn)
+						conn.Close()
+					}
+				}
+			}
+		}
+	}()
+}
@@ -0,0 +1,82 @@
+package orderbook
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+)
+
+// ErrShuttingDown rejects orders once the engine has started shutting down
+var ErrShuttingDown = errors.New("exchange is shutting down, not accepting orders")
+
+// Accepting reports whether new orders are admitted
+func (store *OrderBookStore) Accepting() bool {
+	return !store.stopping.Load()
+}
+
+// StopAccepting turns away every new order from now on. Cancels are still honoured and
+// conditional orders already stored still trigger.
+func (store *OrderBookStore) StopAccepting() {
+	if store.stopping.CompareAndSwap(false, true) {
+		log.Println("**Shutdown**: no longer accepting orders")
+	}
+}
+
+// settlement is a submitted match or ring transaction whose receipt has not been handled yet
+type settlement struct {
+	orders    int
+	startedAt time.Time
+}
+
+// beginSettlement counts a transaction settling orders until the returned func is called with its
+// receipt handled
+func (store *OrderBookStore) beginSettlement(orders int) func() {
+	pending := &settlement{orders: orders, startedAt: time.Now()}
+	store.settlementsMu.Lock()
+	store.inFlight[pending] = true
+	store.settlementsMu.Unlock()
+	store.settlements.Add(1)
+	return func() {
+		store.settlementsMu.Lock()
+		delete(store.inFlight, pending)
+		store.settlementsMu.Unlock()
+		store.settlements.Done()
+	}
+}
+
+func (store *OrderBookStore) inFlightCount() int {
+	store.settlementsMu.Lock()
+	defer store.settlementsMu.Unlock()
+	return len(store.inFlight)
+}
+
+// Drain waits for the oracle started by StartOracle to finish its cycle, its context having been
+// cancelled, and then for every submitted match and ring to be confirmed or reverted. Orders whose
+// receipt has not arrived by the time ctx ends stay pending and an error says how many.
+func (store *OrderBookStore) Drain(ctx context.Context) error {
+	if store.oracleDone != nil {
+		select {
+		case <-store.oracleDone:
+		case <-ctx.Done():
+			return fmt.Errorf("matching oracle did not stop: %w", ctx.Err())
+		}
+	}
+
+	settled := make(chan struct{})
+	go func() {
+		store.settlements.Wait()
+		close(settled)
+	}()
+	if pending := store.inFlightCount(); pending > 0 {
+		log.Printf("**Shutdown**: waiting for %d settlement receipts", pending)
+	}
+	select {
+	case <-settled:
+		log.Println("**Shutdown**: every settlement confirmed or reverted")
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("%d settlements still unconfirmed: %w", store.inFlightCount(), ctx.Err())
+	}
+}
@@ -0,0 +1,239 @@
+package orderbook
+
+import (
+	"dexbe/internal/domains/order"
+	"dexbe/internal/domains/trade"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"math/big"
+	"os"
+	"time"
+
+	rbtree "github.com/emirpasic/gods/trees/redblacktree"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// engineState is what SaveState writes and LoadState reads back: everything the engine only keeps
+// in memory
+type engineState struct {
+	SavedAt     time.Time                                   `json:"savedAt"`
+	Books       map[string]*bookState                       `json:"books"`
+	Conditional []*ConditionalOrderEntry                    `json:"conditional"`
+	History     map[common.Address]map[string][]order.Order `json:"history"`
+	Trades      []*trade.Trade                              `json:"trades"`
+}
+
+type bookState struct {
+	LastPrice *big.Int       `json:"lastPrice"`
+	Bids      []*order.Order `json:"bids"` // best price first, time priority within a price
+	Asks      []*order.Order `json:"asks"`
+}
+
+func savedOrders(tree *rbtree.Tree) []*order.Order {
+	orders := []*order.Order{}
+	it := tree.Iterator()
+	for it.Next() {
+		level := it.Value().(*PriceLevel)
+		for e := level.Orders.Front(); e != nil; e = e.Next() {
+			orders = append(orders, e.Value.(*order.Order).DeepCopy())
+		}
+	}
+	return orders
+}
+
+// SaveState writes the books, conditional orders, order history and trade tape to path, replacing
+// it only once the new state is fully on disk. It should run after Drain and after the listeners
+// are closed, when nothing, not even a cancel, can move the books.
+func (store *OrderBookStore) SaveState(path string) error {
+	state := engineState{
+		SavedAt: time.Now().UTC(),
+		Books:   make(map[string]*bookState),
+	}
+
+	store.mu.RLock()
+	books := make(map[string]*MarketOrderBook, len(store.Books))
+	for pairID, book := range store.Books {
+		books[pairID] = book
+	}
+	state.History = make(map[common.Address]map[string][]order.Order, len(store.PastHistoryStore))
+	for creator, byNonce := range store.PastHistoryStore {
+		state.History[creator] = make(map[string][]order.Order, len(byNonce))
+		for nonce, snapshots := range byNonce {
+			state.History[creator][nonce] = append([]order.Order(nil), snapshots...)
+		}
+	}
+	store.mu.RUnlock()
+
+	pending := 0
+	for pairID, book := range books {
+		book.Mu.Lock()
+		saved := &bookState{
+			LastPrice: new(big.Int).Set(book.LastPrice),
+			Bids:      savedOrders(book.Bids),
+			Asks:      savedOrders(book.Asks),
+		}
+		book.Mu.Unlock()
+		for _, o := range append(saved.Bids, saved.Asks...) {
+			if o.Status == 1 {
+				pending++
+			}
+		}
+		state.Books[pairID] = saved
+	}
+	state.Conditional = store.ConditionalOrderStore.GetAllConditionalOrders()
+	for _, pair := range store.Trades.Pairs() {
+		state.Trades = append(state.Trades, store.Trades.All(pair)...)
+	}
+
+	encoded, err := json.Marshal(state)
+	if err != nil {
+		return fmt.Errorf("failed to encode engine state: %w", err)
+	}
+	tmpPath := path + ".tmp"
+	file, err := os.OpenFile(tmpPath, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0o600)
+	if err != nil {
+		return err
+	}
+	if _, err := file.Write(encoded); err != nil {
+		file.Close()
+		return err
+	}
+	if err := file.Sync(); err != nil {
+		file.Close()
+		return err
+	}
+	if err := file.Close(); err != nil {
+		return err
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		return err
+	}
+	log.Printf("**State Saved**: %d books, %d conditional orders, %d trades to %s (%d orders awaiting settlement)",
+		len(state.Books), len(state.Conditional), len(state.Trades), path, pending)
+	return nil
+}
+
+// LoadState puts back what SaveState wrote. A missing file is a fresh start. It must run before
+// the oracle starts and before anything rebuilds from the trade tape.
+//
+// The saved fill of an order can be behind the chain: a settlement still unconfirmed at shutdown
+// may have been mined since. Every restored order therefore takes its filled amount from the
+// exchange contract, and orders the chain has already filled completely go to history instead of
+// back on the book. Those fills are missing from the trade tape, which only records confirmed
+// receipts. Failing to read a fill aborts the load rather than restore a stale book.
+func (store *OrderBookStore) LoadState(path string) error {
+	encoded, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	var state engineState
+	if err := json.Unmarshal(encoded, &state); err != nil {
+		return fmt.Errorf("failed to decode engine state %s: %w", path, err)
+	}
+	advanced := make(map[*order.Order]bool)
+	for _, saved := range state.Books {
+		for _, o := range append(saved.Bids, saved.Asks...) {
+			moved, err := store.reconcileFill(o)
+			if err != nil {
+				return err
+			}
+			advanced[o] = moved
+		}
+	}
+
+	// History goes back first so the fills found on chain append to it
+	store.mu.Lock()
+	if state.History != nil {
+		store.PastHistoryStore = state.History
+	}
+	store.mu.Unlock()
+
+	store.ConditionalOrderStore.mu.Lock()
+	for _, entry := range state.Conditional {
+		store.ConditionalOrderStore.conditionalOrders[getConditionalOrderKey(entry.Order.CreatedBy, entry.Order.Nonce)] = entry
+	}
+	store.ConditionalOrderStore.mu.Unlock()
+
+	restored, completed := 0, 0
+	var filledParents []*order.Order
+	for pairID, saved := range state.Books {
+		store.mu.RLock()
+		book, exists := store.Books[pairID]
+		store.mu.RUnlock()
+		if !exists {
+			log.Printf("**State Warning**: book %s no longer exists, dropping its %d orders", pairID, len(saved.Bids)+len(saved.Asks))
+			continue
+		}
+		book.Mu.Lock()
+		if saved.LastPrice != nil {
+			book.LastPrice.Set(saved.LastPrice)
+		}
+		for _, o := range append(saved.Bids, saved.Asks...) {
+			if o.FilledAmtIn.Cmp(o.AmtIn) >= 0 {
+				log.Printf("**State Warning**: order %s was filled on chain while the engine was down, moving it to history", getOrderKey(o))
+				o.Status = 3
+				store.AddToPastHistory(o)
+				if o.ConditionalOrder != nil {
+					filledParents = append(filledParents, o)
+				}
+				completed++
+				continue
+			}
+			if advanced[o] {
+				o.Status = 5
+				store.AddToPastHistory(o)
+				o.Status = 0
+			} else if o.Status == 1 {
+				log.Printf("**State Warning**: order %s was awaiting settlement at shutdown, restoring it as matching", getOrderKey(o))
+				o.Status = 0
+				auditStatus(o, "Matching", "restored with settlement unconfirmed", "")
+			}
+			if _, _, _, err := book.insertOrder(o); err != nil {
+				log.Printf("**State Warning**: could not restore order %s: %v", getOrderKey(o), err)
+				continue
+			}
+			restored++
+		}
+		book.Mu.Unlock()
+	}
+
+	for _, parent := range filledParents {
+		parentOrderID := fmt.Sprintf("%s-%s", parent.CreatedBy.Hex(), parent.Nonce.String())
+		if err := store.StoreConditionalOrder(parent.ConditionalOrder, parentOrderID); err != nil {
+			log.Printf("**Error Storing Conditional Order**: %v", err)
+		}
+	}
+
+	store.Trades.Restore(state.Trades)
+
+	log.Printf("**State Loaded**: %d orders, %d filled on chain since, %d conditional orders, %d trades from %s (saved %s)",
+		restored, completed, len(state.Conditional), len(state.Trades), path, state.SavedAt.Format(time.RFC3339))
+	return nil
+}
+
+// reconcileFill sets a saved order's filled amount to what the exchange has settled for it and
+// reports whether the chain was ahead of the saved state. Without an exchange contract the saved
+// amount stands.
+func (store *OrderBookStore) reconcileFill(o *order.Order) (bool, error) {
+	if o.FilledAmtIn == nil {
+		o.FilledAmtIn = big.NewInt(0)
+	}
+	if store.Exchange == nil {
+		return false, nil
+	}
+	filled, err := store.Exchange.FilledAmtIn(o.CreatedBy, o.Nonce)
+	if err != nil {
+		return false, fmt.Errorf("failed to read on-chain fill of order %s: %w", getOrderKey(o), err)
+	}
+	moved := filled.Cmp(o.FilledAmtIn) > 0
+	if filled.Cmp(o.FilledAmtIn) != 0 {
+		log.Printf("**State Warning**: order %s was saved with %s filled, the exchange has %s", getOrderKey(o), o.FilledAmtIn, filled)
+		o.FilledAmtIn = filled
+	}
+	return moved, nil
+}
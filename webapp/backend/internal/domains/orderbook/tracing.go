@@ -0,0 +1,29 @@
+package orderbook
+
+import (
+	"dexbe/internal/infra/tracing"
+	"fmt"
+
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// endReceiptSpans closes the receipt wait of a match or ring, failing it and its settlement spans
+// if the transaction reverted
+func endReceiptSpans(receipt, settlement *tracing.OrderSpans, txHash string, result int) {
+	receipt.SetAttributes(attribute.Int("tx.status", result))
+	if result != 1 {
+		err := fmt.Errorf("transaction %s failed or reverted", txHash)
+		receipt.Fail(err)
+		settlement.Fail(err)
+	}
+	receipt.End()
+}
+
+// settlementStep names the span that applies a receipt to the book: the fills, or the reset of
+// orders whose transaction reverted
+func settlementStep(result int) string {
+	if result == 1 {
+		return "orderbook.fill"
+	}
+	return "orderbook.revert"
+}
@@ -0,0 +1,149 @@
+package permit
+
+import (
+	"context"
+	"dexbe/internal/domains/registry"
+	"fmt"
+	"log"
+	"math/big"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// PermitService verifies signed EIP-2612 permits and relays them on-chain with the operator key
+type PermitService struct {
+	TokenRegistry *registry.Registry
+	ChainId       *big.Int
+	Spender       common.Address
+
+	// relayed holds the deadline of every permit sent or being sent, by owner, token and nonce. A
+	// permit can only be used once, so an identical one arriving before the first is mined would
+	// pass Validate too and cost gas for a transaction that reverts.
+	relayed map[string]*big.Int
+	mu      sync.Mutex
+}
+
+func NewPermitService(tokenRegistry *registry.Registry, chainId int, spender string) *PermitService {
+	return &PermitService{
+		TokenRegistry: tokenRegistry,
+		ChainId:       big.NewInt(int64(chainId)),
+		Spender:       common.HexToAddress(spender),
+		relayed:       make(map[string]*big.Int),
+	}
+}
+
+func relayKey(p *Permit, symbol string) string {
+	return p.Owner.Hex() + "/" + symbol + "/" + p.Nonce.String()
+}
+
+// claim reserves the permit's owner, token and nonce for one submission. Entries are dropped once
+// their deadline passes, after which the permit could not be relayed anyway.
+func (service *PermitService) claim(p *Permit, symbol string) error {
+	now := big.NewInt(time.Now().Unix())
+	key := relayKey(p, symbol)
+
+	service.mu.Lock()
+	defer service.mu.Unlock()
+	for k, deadline := range service.relayed {
+		if deadline.Cmp(now) <= 0 {
+			delete(service.relayed, k)
+		}
+	}
+	if _, taken := service.relayed[key]; taken {
+		return fmt.Errorf("permit %s for %s nonce %s was already submitted", symbol, p.Owner.Hex(), p.Nonce.String())
+	}
+	service.relayed[key] = p.Deadline
+	return nil
+}
+
+// release gives the permit back when it never made it on-chain, so it can be submitted again
+func (service *PermitService) release(p *Permit, symbol string) {
+	service.mu.Lock()
+	delete(service.relayed, relayKey(p, symbol))
+	service.mu.Unlock()
+}
+
+// Validate checks the permit against the registered token, its deadline and the owner's on-chain nonce
+func (service *PermitService) Validate(p *Permit, symbol string, sig []byte) error {
+	if p.Value == nil || p.Nonce == nil || p.Deadline == nil {
+		return fmt.Errorf("permit has invalid value, nonce or deadline")
+	}
+
+	// Only approvals for the exchange are relayed, otherwise the operator pays gas for arbitrary spenders
+	if p.Spender != service.Spender {
+		return fmt.Errorf("permit spender %s is not the exchange %s", p.Spender.Hex(), service.Spender.Hex())
+	}
+
+	if p.Deadline.Cmp(big.NewInt(time.Now().Unix())) <= 0 {
+		return fmt.Errorf("permit deadline %s has passed", p.Deadline.String())
+	}
+
+	t := service.TokenRegistry.Get(symbol)
+	if t == nil {
+		return fmt.Errorf("token %s is not registered", symbol)
+	}
+	tokenContract, err := service.TokenRegistry.GetTokenContract(symbol)
+	if err != nil {
+		return err
+	}
+
+	onChainNonce, err := tokenContract.Nonces(p.Owner)
+	if err != nil {
+		return err
+	}
+	if onChainNonce.Cmp(p.Nonce) != 0 {
+		return fmt.Errorf("permit nonce %s does not match on-chain nonce %s", p.Nonce.String(), onChainNonce.String())
+	}
+
+	if _, err := p.VerifyPermit(sig, service.ChainId, t.Address, t.Name); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Submit validates the permit and sends it to the token contract. Concurrent submissions of the
+// same owner, token and nonce are refused rather than both relayed.
+func (service *PermitService) Submit(p *Permit, symbol string, sig []byte) (*types.Transaction, error) {
+	if p.Nonce == nil || p.Deadline == nil {
+		return nil, fmt.Errorf("permit has invalid value, nonce or deadline")
+	}
+	if err := service.claim(p, symbol); err != nil {
+		return nil, err
+	}
+	if err := service.Validate(p, symbol, sig); err != nil {
+		service.release(p, symbol)
+		return nil, err
+	}
+	tokenContract, err := service.TokenRegistry.GetTokenContract(symbol)
+	if err != nil {
+		service.release(p, symbol)
+		return nil, err
+	}
+	tx, err := tokenContract.Permit(p.Owner, p.Spender, p.Value, p.Deadline, sig)
+	if err != nil {
+		service.release(p, symbol)
+		return nil, err
+	}
+	log.Printf("**Permit Submitted**: %s approves %s %s (TX: %s)",
+		p.Owner.Hex()[:10], p.Value.String(), symbol, tx.Hash().Hex())
+	return tx, nil
+}
+
+// SubmitAndWait submits the permit and blocks until the approval is mined
+func (service *PermitService) SubmitAndWait(ctx context.Context, p *Permit, symbol string, sig []byte) (*types.Transaction, error) {
+	tx, err := service.Submit(p, symbol, sig)
+	if err != nil {
+		return nil, err
+	}
+	if err := service.TokenRegistry.RegistryContract.Client.WaitTxSuccess(ctx, tx); err != nil {
+		// The tx may still be mined after a timeout, so only a known failure frees the nonce
+		if ctx.Err() == nil {
+			service.release(p, symbol)
+		}
+		return tx, err
+	}
+	return tx, nil
+}
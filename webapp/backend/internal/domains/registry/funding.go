@@ -0,0 +1,98 @@
+package registry
+
+import (
+	tokenC "dexbe/internal/infra/eth/token"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+const (
+	FundingUnknownToken          = "UNKNOWN_TOKEN"
+	FundingQueryFailed           = "FUNDING_QUERY_FAILED"
+	FundingInsufficientBalance   = "INSUFFICIENT_BALANCE"
+	FundingInsufficientAllowance = "INSUFFICIENT_ALLOWANCE"
+)
+
+// FundingError describes why an owner cannot cover an amount of a registered token
+type FundingError struct {
+	Code      string
+	Symbol    string
+	Owner     common.Address
+	Required  *big.Int
+	Available *big.Int
+	Err       error
+}
+
+func (e *FundingError) Error() string {
+	switch e.Code {
+	case FundingUnknownToken:
+		return fmt.Sprintf("token %s is not registered", e.Symbol)
+	case FundingQueryFailed:
+		return fmt.Sprintf("could not query %s funding for %s: %v", e.Symbol, e.Owner.Hex(), e.Err)
+	case FundingInsufficientBalance:
+		return fmt.Sprintf("insufficient %s balance: required %s, available %s", e.Symbol, e.Required.String(), e.Available.String())
+	case FundingInsufficientAllowance:
+		return fmt.Sprintf("insufficient %s allowance for exchange: required %s, approved %s", e.Symbol, e.Required.String(), e.Available.String())
+	default:
+		return fmt.Sprintf("funding check failed for %s", e.Symbol)
+	}
+}
+
+func (e *FundingError) Unwrap() error {
+	return e.Err
+}
+
+// ToStringMap renders the error in the same shape as other API error replies
+func (e *FundingError) ToStringMap() map[string]string {
+	result := map[string]string{
+		"Error":  e.Error(),
+		"Code":   e.Code,
+		"Symbol": e.Symbol,
+		"Owner":  e.Owner.Hex(),
+	}
+	if e.Required != nil {
+		result["Required"] = e.Required.String()
+	}
+	if e.Available != nil {
+		result["Available"] = e.Available.String()
+	}
+	return result
+}
+
+// GetTokenContract returns a contract binding for a registered token symbol
+func (r *Registry) GetTokenContract(symbol string) (*tokenC.TokenContract, error) {
+	t := r.Get(symbol)
+	if t == nil {
+		return nil, fmt.Errorf("token %s is not registered", symbol)
+	}
+	return tokenC.NewTokenContract(r.RegistryContract.Client, t.Address.Hex()), nil
+}
+
+// CheckFunding verifies that owner holds at least required of symbol and has approved spender for it
+func (r *Registry) CheckFunding(owner, spender common.Address, symbol string, required *big.Int) error {
+	t := r.Get(symbol)
+	if t == nil {
+		return &FundingError{Code: FundingUnknownToken, Symbol: symbol, Owner: owner, Required: required}
+	}
+	tokenContract := tokenC.NewTokenContract(r.RegistryContract.Client, t.Address.Hex())
+
+	balance, err := tokenContract.BalanceOf(owner)
+	if err != nil {
+		return &FundingError{Code: FundingQueryFailed, Symbol: symbol, Owner: owner, Required: required, Err: err}
+	}
+	if balance.Cmp(required) < 0 {
+		return &FundingError{Code: FundingInsufficientBalance, Symbol: symbol, Owner: owner, Required: required, Available: balance}
+	}
+
+	allowance, err := tokenContract.Allowance(owner, spender)
+	if err != nil {
+		return &FundingError{Code: FundingQueryFailed, Symbol: symbol, Owner: owner, Required: required, Err: err}
+	}
+	if allowance.Cmp(required) < 0 {
+		return &FundingError{Code: FundingInsufficientAllowance, Symbol: symbol, Owner: owner, Required: required, Available: allowance}
+	}
+
+	return nil
+}
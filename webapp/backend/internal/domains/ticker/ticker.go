@@ -0,0 +1,225 @@
+package ticker
+
+import (
+	"dexbe/internal/domains/orderbook"
+	"dexbe/internal/domains/trade"
+	"dexbe/internal/infra/api"
+	"dexbe/internal/infra/metrics"
+	"encoding/json"
+	"log"
+	"math/big"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// Period is the length of the rolling window summarized by a ticker
+const Period = 24 * time.Hour
+
+// pushInterval is how often changed tickers are streamed, which also picks up trades leaving the window
+const pushInterval = time.Second
+
+// Ticker summarizes the last 24h of one book. Prices are in the book's 1e18 price scale, volumes in
+// token base units. Open, High, Low and PriceChange are null when nothing traded in the window.
+type Ticker struct {
+	Pair               string     `json:"pair"`
+	Base               string     `json:"base"`
+	Quote              string     `json:"quote"`
+	Open               *big.Int   `json:"open"`
+	High               *big.Int   `json:"high"`
+	Low                *big.Int   `json:"low"`
+	Last               *big.Int   `json:"last"`
+	LastTradeAt        *time.Time `json:"lastTradeAt"`
+	VolumeBase         *big.Int   `json:"volumeBase"`
+	VolumeQuote        *big.Int   `json:"volumeQuote"`
+	TradeCount         int        `json:"tradeCount"`
+	BestBid            *big.Int   `json:"bestBid"`
+	BestAsk            *big.Int   `json:"bestAsk"`
+	PriceChange        *big.Int   `json:"priceChange"`
+	PriceChangePercent string     `json:"priceChangePercent"`
+	WindowStart        time.Time  `json:"windowStart"`
+	WindowEnd          time.Time  `json:"windowEnd"`
+}
+
+// TickerService keeps a rolling 24h window of trades for every book and streams tickers that changed
+type TickerService struct {
+	OrderBookStore *orderbook.OrderBookStore
+	windows        map[string]*window
+	subscribers    map[api.WsSubscriber]bool
+	lastPushed     map[string][]byte
+	mu             sync.Mutex
+}
+
+func NewTickerService(store *orderbook.OrderBookStore) *TickerService {
+	service := &TickerService{
+		OrderBookStore: store,
+		windows:        make(map[string]*window),
+		subscribers:    make(map[api.WsSubscriber]bool),
+		lastPushed:     make(map[string][]byte),
+	}
+	service.StartBroadcast()
+	return service
+}
+
+// AddTrade adds a confirmed trade to its pair's window
+func (service *TickerService) AddTrade(t *trade.Trade) {
+	service.mu.Lock()
+	defer service.mu.Unlock()
+	service.window(t.Pair).push(t)
+}
+
+// Rebuild refills every window from the trade history
+func (service *TickerService) Rebuild(history *trade.TradeStore) {
+	service.mu.Lock()
+	defer service.mu.Unlock()
+
+	service.windows = make(map[string]*window)
+	cutoff := time.Now().Add(-Period)
+	for _, pair := range history.Pairs() {
+		trades := history.All(pair)
+		w := service.window(pair)
+		for _, t := range trades {
+			w.push(t)
+		}
+		w.evict(cutoff)
+	}
+}
+
+// window must be called with the lock held
+func (service *TickerService) window(pair string) *window {
+	w, exists := service.windows[pair]
+	if !exists {
+		w = newWindow()
+		service.windows[pair] = w
+	}
+	return w
+}
+
+// Tickers returns the ticker of every book, ordered by pair
+func (service *TickerService) Tickers() []*Ticker {
+	now := time.Now().UTC()
+	pairs := service.OrderBookStore.Pairs()
+	tickers := make([]*Ticker, 0, len(pairs))
+	for _, pair := range pairs {
+		tickers = append(tickers, service.ticker(pair, now))
+	}
+	return tickers
+}
+
+// Get returns the ticker of one book, or nil if the pair has no book
+func (service *TickerService) Get(pair string) *Ticker {
+	for _, p := range service.OrderBookStore.Pairs() {
+		if p == pair {
+			return service.ticker(pair, time.Now().UTC())
+		}
+	}
+	return nil
+}
+
+func (service *TickerService) ticker(pair string, now time.Time) *Ticker {
+	base, quote, _ := strings.Cut(pair, "/")
+	t := &Ticker{
+		Pair:        pair,
+		Base:        base,
+		Quote:       quote,
+		VolumeBase:  big.NewInt(0),
+		VolumeQuote: big.NewInt(0),
+		WindowStart: now.Add(-Period),
+		WindowEnd:   now,
+	}
+
+	service.mu.Lock()
+	if w, exists := service.windows[pair]; exists {
+		w.evict(t.WindowStart)
+		if w.last != nil {
+			t.Last = new(big.Int).Set(w.last.Price)
+			lastTradeAt := w.last.Timestamp
+			t.LastTradeAt = &lastTradeAt
+		}
+		if len(w.trades) > 0 {
+			t.Open = new(big.Int).Set(w.trades[0].Price)
+			t.High = new(big.Int).Set(w.highs[0].Price)
+			t.Low = new(big.Int).Set(w.lows[0].Price)
+			t.VolumeBase.Set(w.volumeBase)
+			t.VolumeQuote.Set(w.volumeQuote)
+			t.TradeCount = len(w.trades)
+		}
+	}
+	service.mu.Unlock()
+
+	if t.Open != nil && t.Last != nil {
+		t.PriceChange = new(big.Int).Sub(t.Last, t.Open)
+		if t.Open.Sign() != 0 {
+			percent := new(big.Float).Quo(new(big.Float).SetInt(t.PriceChange), new(big.Float).SetInt(t.Open))
+			percent.Mul(percent, big.NewFloat(100))
+			t.PriceChangePercent = percent.Text('f', 2)
+		}
+	}
+
+	if price, err := service.OrderBookStore.GetMarketPrice(base, quote); err == nil {
+		t.BestBid = price.BestBid
+		t.BestAsk = price.BestAsk
+	}
+	return t
+}
+
+func (service *TickerService) AddSubscriber(conn api.WsSubscriber) {
+	service.mu.Lock()
+	defer service.mu.Unlock()
+	if !service.subscribers[conn] {
+		metrics.Subscribers.WithLabelValues("tickers").Inc()
+	}
+	service.subscribers[conn] = true
+}
+
+func (service *TickerService) RemoveSubscriber(conn api.WsSubscriber) {
+	service.mu.Lock()
+	defer service.mu.Unlock()
+	if service.subscribers[conn] {
+		metrics.Subscribers.WithLabelValues("tickers").Dec()
+		delete(service.subscribers, conn)
+	}
+}
+
+// StartBroadcast pushes each ticker to subscribers whenever it differs from the last one pushed
+func (service *TickerService) StartBroadcast() {
+	go func() {
+		interval := time.NewTicker(pushInterval)
+		defer interval.Stop()
+		for range interval.C {
+			for _, t := range service.Tickers() {
+				// Compare without the window bounds, which move on every tick
+				key := *t
+				key.WindowStart, key.WindowEnd = time.Time{}, time.Time{}
+				fingerprint, _ := json.Marshal(key)
+
+				service.mu.Lock()
+				changed := string(service.lastPushed[t.Pair]) != string(fingerprint)
+				service.lastPushed[t.Pair] = fingerprint
+				conns := make([]api.WsSubscriber, 0, len(service.subscribers))
+				for conn := range service.subscribers {
+					conns = append(conns, conn)
+				}
+				service.mu.Unlock()
+				if !changed {
+					continue
+				}
+
+				encoded, _ := json.Marshal(map[string]any{
+					"event": "Ticker",
+					"pair":  t.Pair,
+					"data":  t,
+				})
+				for _, conn := range conns {
+					if err := conn.WriteMessage(websocket.TextMessage, encoded); err != nil {
+						log.Printf("**Ticker**: dropping subscriber: %v", err)
+						service.RemoveSubscriber(conn)
+						conn.Close()
+					}
+				}
+			}
+		}
+	}()
+}
@@ -0,0 +1,63 @@
+package ticker
+
+import (
+	"dexbe/internal/domains/trade"
+	"math/big"
+	"time"
+)
+
+// window holds the trades of one pair inside the rolling period, with running volume totals and
+// monotonic queues so the high and low stay exact as old trades fall out
+type window struct {
+	trades      []*trade.Trade
+	highs       []*trade.Trade // prices non-increasing, front is the window high
+	lows        []*trade.Trade // prices non-decreasing, front is the window low
+	volumeBase  *big.Int
+	volumeQuote *big.Int
+	last        *trade.Trade // last trade ever, kept after it leaves the window
+}
+
+func newWindow() *window {
+	return &window{
+		volumeBase:  big.NewInt(0),
+		volumeQuote: big.NewInt(0),
+	}
+}
+
+func (w *window) push(t *trade.Trade) {
+	w.trades = append(w.trades, t)
+	w.volumeBase.Add(w.volumeBase, t.BaseQty)
+	w.volumeQuote.Add(w.volumeQuote, t.QuoteQty)
+
+	for len(w.highs) > 0 && w.highs[len(w.highs)-1].Price.Cmp(t.Price) < 0 {
+		w.highs = w.highs[:len(w.highs)-1]
+	}
+	w.highs = append(w.highs, t)
+	for len(w.lows) > 0 && w.lows[len(w.lows)-1].Price.Cmp(t.Price) > 0 {
+		w.lows = w.lows[:len(w.lows)-1]
+	}
+	w.lows = append(w.lows, t)
+
+	if w.last == nil || !t.Timestamp.Before(w.last.Timestamp) {
+		w.last = t
+	}
+}
+
+// evict drops every trade at or before cutoff
+func (w *window) evict(cutoff time.Time) {
+	dropped := 0
+	for dropped < len(w.trades) && !w.trades[dropped].Timestamp.After(cutoff) {
+		t := w.trades[dropped]
+		w.volumeBase.Sub(w.volumeBase, t.BaseQty)
+		w.volumeQuote.Sub(w.volumeQuote, t.QuoteQty)
+		if len(w.highs) > 0 && w.highs[0] == t {
+			w.highs = w.highs[1:]
+		}
+		if len(w.lows) > 0 && w.lows[0] == t {
+			w.lows = w.lows[1:]
+		}
+		dropped++
+	}
+	// The evicted head is released once append next grows the backing array
+	w.trades = w.trades[dropped:]
+}
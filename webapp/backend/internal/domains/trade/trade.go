@@ -2,16 +2,77 @@ package trade
 
 import (
 	"dexbe/internal/domains/order"
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
 )
 
+type Side string
+
+const (
+	Buy  Side = "buy"
+	Sell Side = "sell"
+)
+
+// Trade is one confirmed fill in a book. Direct matches produce one trade per transaction,
+// ring trades produce one per leg with the ring as the (unnamed) aggressor.
 type Trade struct {
-	Maker *order.Order
-	Taker *order.Order
+	ID            uint64         `json:"id"`
+	Pair          string         `json:"pair"`
+	Base          string         `json:"base"`
+	Quote         string         `json:"quote"`
+	Price         *big.Int       `json:"price"`
+	BaseQty       *big.Int       `json:"baseQty"`
+	QuoteQty      *big.Int       `json:"quoteQty"`
+	AggressorSide Side           `json:"aggressorSide"`
+	Maker         common.Address `json:"maker"`
+	MakerNonce    *big.Int       `json:"makerNonce"`
+	Taker         common.Address `json:"taker"`
+	TakerNonce    *big.Int       `json:"takerNonce,omitempty"`
+	RingSize      int            `json:"ringSize,omitempty"`
+	TxHash        string         `json:"txHash"`
+	Timestamp     time.Time      `json:"timestamp"`
 }
 
-func NewTrade(maker *order.Order, taker *order.Order) *Trade {
+// NewTrade records a direct match between a resting maker and the aggressing taker
+func NewTrade(base, quote string, maker *order.Order, taker *order.Order, aggressorSide Side, price, baseQty, quoteQty *big.Int, txHash string) *Trade {
+	return &Trade{
+		Pair:          base + "/" + quote,
+		Base:          base,
+		Quote:         quote,
+		Price:         new(big.Int).Set(price),
+		BaseQty:       new(big.Int).Set(baseQty),
+		QuoteQty:      new(big.Int).Set(quoteQty),
+		AggressorSide: aggressorSide,
+		Maker:         maker.CreatedBy,
+		MakerNonce:    new(big.Int).Set(maker.Nonce),
+		Taker:         taker.CreatedBy,
+		TakerNonce:    new(big.Int).Set(taker.Nonce),
+		TxHash:        txHash,
+		Timestamp:     time.Now().UTC(),
+	}
+}
+
+// NewRingLegTrade records the fill of one resting order by a ring trade. The ring takes its
+// liquidity, so the aggressor side is the opposite of the leg order's side.
+func NewRingLegTrade(base, quote string, leg *order.Order, price, baseQty, quoteQty *big.Int, ringSize int, txHash string) *Trade {
+	aggressorSide := Buy
+	if leg.SymbolIn == quote {
+		aggressorSide = Sell
+	}
 	return &Trade{
-		Maker: maker,
-		Taker: taker,
+		Pair:          base + "/" + quote,
+		Base:          base,
+		Quote:         quote,
+		Price:         new(big.Int).Set(price),
+		BaseQty:       new(big.Int).Set(baseQty),
+		QuoteQty:      new(big.Int).Set(quoteQty),
+		AggressorSide: aggressorSide,
+		Maker:         leg.CreatedBy,
+		MakerNonce:    new(big.Int).Set(leg.Nonce),
+		RingSize:      ringSize,
+		TxHash:        txHash,
+		Timestamp:     time.Now().UTC(),
 	}
 }
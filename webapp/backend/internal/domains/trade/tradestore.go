@@ -0,0 +1,186 @@
+package trade
+
+import (
+	"dexbe/internal/infra/api"
+	"dexbe/internal/infra/metrics"
+	"encoding/json"
+	"log"
+	"sort"
+	"sync"
+
+	"github.com/gorilla/websocket"
+)
+
+// MaxTradesPerPair bounds the in-memory tape of each pair
+const MaxTradesPerPair = 100000
+
+// AllPairs subscribes to the trades of every pair
+const AllPairs = "*"
+
+// TradeStore keeps the public trade tape per pair and streams new trades to pair subscribers
+type TradeStore struct {
+	trades      map[string][]*Trade
+	nextID      uint64
+	subscribers map[string]map[api.WsSubscriber]bool
+	listeners   []func(*Trade)
+	updateCh    chan *Trade
+	mu          sync.RWMutex
+}
+
+func NewTradeStore() *TradeStore {
+	store := &TradeStore{
+		trades:      make(map[string][]*Trade),
+		nextID:      1,
+		subscribers: make(map[string]map[api.WsSubscriber]bool),
+		updateCh:    make(chan *Trade, 256),
+	}
+	store.StartBroadcast()
+	return store
+}
+
+// Record assigns the trade its id, appends it to its pair's tape and publishes it
+func (store *TradeStore) Record(t *Trade) {
+	store.mu.Lock()
+	t.ID = store.nextID
+	store.nextID++
+	tape := append(store.trades[t.Pair], t)
+	if len(tape) > MaxTradesPerPair {
+		tape = tape[len(tape)-MaxTradesPerPair:]
+	}
+	store.trades[t.Pair] = tape
+	listeners := store.listeners
+	store.mu.Unlock()
+
+	for _, listener := range listeners {
+		listener(t)
+	}
+
+	log.Printf("**Trade Recorded**: #%d %s | %s | Base: %s | Quote: %s | TX: %s",
+		t.ID, t.Pair, t.AggressorSide, t.BaseQty.String(), t.QuoteQty.String(), t.TxHash)
+
+	store.updateCh <- t
+}
+
+// OnTrade registers fn to be called with every trade after it is recorded
+func (store *TradeStore) OnTrade(fn func(*Trade)) {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+	store.listeners = append(store.listeners, fn)
+}
+
+// Pairs returns every pair that has trades on its tape
+func (store *TradeStore) Pairs() []string {
+	store.mu.RLock()
+	defer store.mu.RUnlock()
+	pairs := make([]string, 0, len(store.trades))
+	for pair := range store.trades {
+		pairs = append(pairs, pair)
+	}
+	return pairs
+}
+
+// List returns up to limit trades of pair, newest first, older than the before id (0 means latest)
+func (store *TradeStore) List(pair string, before uint64, limit int) []*Trade {
+	store.mu.RLock()
+	defer store.mu.RUnlock()
+
+	tape := store.trades[pair]
+	result := []*Trade{}
+	for i := len(tape) - 1; i >= 0 && len(result) < limit; i-- {
+		if before != 0 && tape[i].ID >= before {
+			continue
+		}
+		result = append(result, tape[i])
+	}
+	return result
+}
+
+// Last returns the most recent trade of pair, or nil if it never traded
+func (store *TradeStore) Last(pair string) *Trade {
+	store.mu.RLock()
+	defer store.mu.RUnlock()
+	tape := store.trades[pair]
+	if len(tape) == 0 {
+		return nil
+	}
+	return tape[len(tape)-1]
+}
+
+// Restore puts trades saved by an earlier run back on the tape with their ids. Nothing is published,
+// so it must run before listeners rebuild from the tape.
+func (store *TradeStore) Restore(trades []*Trade) {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+	sort.Slice(trades, func(i, j int) bool { return trades[i].ID < trades[j].ID })
+	for _, t := range trades {
+		store.trades[t.Pair] = append(store.trades[t.Pair], t)
+		if t.ID >= store.nextID {
+			store.nextID = t.ID + 1
+		}
+	}
+}
+
+// All returns every stored trade of pair, oldest first
+func (store *TradeStore) All(pair string) []*Trade {
+	store.mu.RLock()
+	defer store.mu.RUnlock()
+	result := make([]*Trade, len(store.trades[pair]))
+	copy(result, store.trades[pair])
+	return result
+}
+
+func (store *TradeStore) AddSubscriber(pair string, conn api.WsSubscriber) {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+	if store.subscribers[pair] == nil {
+		store.subscribers[pair] = make(map[api.WsSubscriber]bool)
+	}
+	if !store.subscribers[pair][conn] {
+		metrics.Subscribers.WithLabelValues("trades").Inc()
+	}
+	store.subscribers[pair][conn] = true
+}
+
+func (store *TradeStore) RemoveSubscriber(pair string, conn api.WsSubscriber) {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+	if store.subscribers[pair][conn] {
+		metrics.Subscribers.WithLabelValues("trades").Dec()
+		delete(store.subscribers[pair], conn)
+	}
+}
+
+// QueueLength returns how many recorded trades are waiting to be broadcast
+func (store *TradeStore) QueueLength() int {
+	return len(store.updateCh)
+}
+
+func (store *TradeStore) StartBroadcast() {
+	go func() {
+		for t := range store.updateCh {
+			encoded, _ := json.Marshal(map[string]any{
+				"event": "Trade",
+				"pair":  t.Pair,
+				"data":  t,
+			})
+
+			store.mu.RLock()
+			conns := make([]api.WsSubscriber, 0, len(store.subscribers[t.Pair])+len(store.subscribers[AllPairs]))
+			for conn := range store.subscribers[t.Pair] {
+				conns = append(conns, conn)
+			}
+			for conn := range store.subscribers[AllPairs] {
+				conns = append(conns, conn)
+			}
+			store.mu.RUnlock()
+
+			for _, conn := range conns {
+				if err := conn.WriteMessage(websocket.TextMessage, encoded); err != nil {
+					store.RemoveSubscriber(t.Pair, conn)
+					store.RemoveSubscriber(AllPairs, conn)
+					conn.Close()
+				}
+			}
+		}
+	}()
+}
@@ -0,0 +1,135 @@
+package api
+
+import (
+	"bytes"
+	"dexbe/internal/domains/admin"
+	"dexbe/internal/domains/auth"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/gorilla/websocket"
+	"github.com/labstack/echo/v4"
+	"github.com/labstack/echo/v4/middleware"
+)
+
+const sessionContextKey = "session"
+
+// BearerToken returns the session token from the Authorization header
+func BearerToken(ctx echo.Context) string {
+	if header := ctx.Request().Header.Get(echo.HeaderAuthorization); header != "" {
+		if token, ok := strings.CutPrefix(header, "Bearer "); ok {
+			return strings.TrimSpace(token)
+		}
+	}
+	return ""
+}
+
+// UpgradeToken is BearerToken for websocket upgrades, which also take the token query parameter
+// since browsers cannot set headers on them. Other requests ignore it, so a token only ends up in
+// a URL where there is no other way.
+func UpgradeToken(ctx echo.Context) string {
+	if token := BearerToken(ctx); token != "" {
+		return token
+	}
+	if websocket.IsWebSocketUpgrade(ctx.Request()) {
+		return ctx.QueryParam("token")
+	}
+	return ""
+}
+
+// AccessLogger is echo's request logger with the token query parameter masked in the logged URI
+func AccessLogger() echo.MiddlewareFunc {
+	config := middleware.DefaultLoggerConfig
+	config.Format = strings.Replace(config.Format, `"uri":"${uri}"`, `"uri":"${custom}"`, 1)
+	config.CustomTagFunc = func(ctx echo.Context, buf *bytes.Buffer) (int, error) {
+		return buf.WriteString(redactedURI(ctx.Request()))
+	}
+	return middleware.LoggerWithConfig(config)
+}
+
+func redactedURI(req *http.Request) string {
+	query := req.URL.Query()
+	if !query.Has("token") {
+		return req.RequestURI
+	}
+	query.Set("token", "REDACTED")
+	redacted := *req.URL
+	redacted.RawQuery = query.Encode()
+	return redacted.RequestURI()
+}
+
+// RequireSession rejects requests without a live SIWE session and stores the session on the context
+func RequireSession(sessions *auth.SessionStore) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(ctx echo.Context) error {
+			session, err := sessions.Get(BearerToken(ctx))
+			if err != nil {
+				return ctx.JSON(http.StatusUnauthorized, map[string]string{"Error": err.Error()})
+			}
+			ctx.Set(sessionContextKey, session)
+			return next(ctx)
+		}
+	}
+}
+
+// RequireSessionAddress only lets the session owner access routes scoped by an address path parameter
+func RequireSessionAddress(param string) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(ctx echo.Context) error {
+			session := SessionFromContext(ctx)
+			if session == nil {
+				return ctx.JSON(http.StatusUnauthorized, map[string]string{"Error": auth.ErrInvalidSession.Error()})
+			}
+			if common.HexToAddress(ctx.Param(param)) != session.Address {
+				return ctx.JSON(http.StatusForbidden, map[string]string{"Error": "session is not authorized for this address"})
+			}
+			return next(ctx)
+		}
+	}
+}
+
+func SessionFromContext(ctx echo.Context) *auth.Session {
+	session, _ := ctx.Get(sessionContextKey).(*auth.Session)
+	return session
+}
+
+// RequireAdmin only lets sessions of registered admins through and records every attempt in the audit trail.
+// It must run after RequireSession.
+func RequireAdmin(admins *auth.AdminSet, trail *admin.AuditTrail) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(ctx echo.Context) error {
+			session := SessionFromContext(ctx)
+			if session == nil {
+				return ctx.JSON(http.StatusUnauthorized, map[string]string{"Error": auth.ErrInvalidSession.Error()})
+			}
+			action := ctx.Request().Method + " " + ctx.Path()
+			if !admins.IsAdmin(session.Address) {
+				trail.Record(admin.AuditEntry{Admin: session.Address, Action: action, Status: http.StatusForbidden, Error: "not an admin"})
+				return ctx.JSON(http.StatusForbidden, map[string]string{"Error": "admin role required"})
+			}
+
+			// Keep a copy of the body for the audit entry and hand the original to the handler
+			var body []byte
+			if ctx.Request().Body != nil {
+				body, _ = io.ReadAll(ctx.Request().Body)
+				ctx.Request().Body = io.NopCloser(bytes.NewReader(body))
+			}
+
+			err := next(ctx)
+
+			entry := admin.AuditEntry{
+				Admin:   session.Address,
+				Action:  action,
+				Request: string(body),
+				Status:  ctx.Response().Status,
+			}
+			if err != nil {
+				entry.Error = err.Error()
+			}
+			trail.Record(entry)
+			return err
+		}
+	}
+}
@@ -0,0 +1,75 @@
+package controller
+
+import (
+	"dexbe/internal/domains/admin"
+	"dexbe/internal/domains/auth"
+	"dexbe/internal/domains/orderbook"
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+)
+
+type AdminController struct {
+	OrderBookStore *orderbook.OrderBookStore
+	Admins         *auth.AdminSet
+	AuditTrail     *admin.AuditTrail
+}
+
+func NewAdminController(store *orderbook.OrderBookStore, admins *auth.AdminSet, trail *admin.AuditTrail) *AdminController {
+	return &AdminController{
+		OrderBookStore: store,
+		Admins:         admins,
+		AuditTrail:     trail,
+	}
+}
+
+type MatchingSettingsRequest struct {
+	RingMatchingEnabled *bool `json:"ringMatchingEnabled,omitempty"`
+	MaxRingDepth        *int  `json:"maxRingDepth,omitempty"`
+}
+
+func (ctrl *AdminController) matchingSettings() map[string]any {
+	enabled, depth := ctrl.OrderBookStore.GetMatchingSettings()
+	return map[string]any{
+		"ringMatchingEnabled": enabled,
+		"maxRingDepth":        depth,
+	}
+}
+
+func (ctrl *AdminController) GetMatchingSettings(ctx echo.Context) error {
+	return ctx.JSON(http.StatusOK, ctrl.matchingSettings())
+}
+
+func (ctrl *AdminController) UpdateMatchingSettings(ctx echo.Context) error {
+	var req MatchingSettingsRequest
+	if err := ctx.Bind(&req); err != nil {
+		return ctx.JSON(http.StatusBadRequest, map[string]string{"Error": err.Error()})
+	}
+	if req.MaxRingDepth != nil && *req.MaxRingDepth < 2 {
+		return ctx.JSON(http.StatusBadRequest, map[string]string{"Error": "maxRingDepth must be at least 2"})
+	}
+	if req.RingMatchingEnabled != nil {
+		ctrl.OrderBookStore.SetRingMatchingEnabled(*req.RingMatchingEnabled)
+	}
+	if req.MaxRingDepth != nil {
+		ctrl.OrderBookStore.SetMaxRingDepth(*req.MaxRingDepth)
+	}
+	return ctx.JSON(http.StatusOK, ctrl.matchingSettings())
+}
+
+func (ctrl *AdminController) GetAdmins(ctx echo.Context) error {
+	return ctx.JSON(http.StatusOK, map[string]any{"admins": ctrl.Admins.List()})
+}
+
+func (ctrl *AdminController) GetAuditTrail(ctx echo.Context) error {
+	limit := 100
+	if raw := ctx.QueryParam("limit"); raw != "" {
+		parsed, err := strconv.Atoi(raw)
+		if err != nil || parsed <= 0 {
+			return ctx.JSON(http.StatusBadRequest, map[string]string{"Error": "Invalid limit"})
+		}
+		limit = parsed
+	}
+	return ctx.JSON(http.StatusOK, map[string]any{"entries": ctrl.AuditTrail.Entries(limit)})
+}
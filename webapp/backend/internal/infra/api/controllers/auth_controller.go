@@ -0,0 +1,74 @@
+package controller
+
+import (
+	"dexbe/internal/domains/auth"
+	"dexbe/internal/infra/api"
+	"log"
+	"net/http"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/labstack/echo/v4"
+)
+
+type AuthController struct {
+	Sessions *auth.SessionStore
+}
+
+func NewAuthController(sessions *auth.SessionStore) *AuthController {
+	return &AuthController{
+		Sessions: sessions,
+	}
+}
+
+type ChallengeRequest struct {
+	Address string `json:"address"`
+}
+
+type LoginRequest struct {
+	Message   string `json:"message"`
+	Signature string `json:"signature"`
+}
+
+func (ctrl *AuthController) GetChallenge(ctx echo.Context) error {
+	var req ChallengeRequest
+	if err := ctx.Bind(&req); err != nil {
+		return ctx.JSON(http.StatusBadRequest, map[string]string{"Error": err.Error()})
+	}
+	if !common.IsHexAddress(req.Address) {
+		return ctx.JSON(http.StatusBadRequest, map[string]string{"Error": "Invalid Address"})
+	}
+	host := ctx.Request().Host
+	uri := ctx.Scheme() + "://" + host
+	msg, err := ctrl.Sessions.IssueChallenge(common.HexToAddress(req.Address), host, uri)
+	if err != nil {
+		return ctx.JSON(http.StatusInternalServerError, map[string]string{"Error": err.Error()})
+	}
+	return ctx.JSON(http.StatusOK, map[string]any{
+		"message":        msg.String(),
+		"nonce":          msg.Nonce,
+		"expirationTime": msg.ExpirationTime,
+	})
+}
+
+func (ctrl *AuthController) Login(ctx echo.Context) error {
+	var req LoginRequest
+	if err := ctx.Bind(&req); err != nil {
+		return ctx.JSON(http.StatusBadRequest, map[string]string{"Error": err.Error()})
+	}
+	sig, err := hexutil.Decode(req.Signature)
+	if err != nil {
+		return ctx.JSON(http.StatusBadRequest, map[string]string{"Error": "Invalid Signature"})
+	}
+	session, err := ctrl.Sessions.Login(req.Message, sig)
+	if err != nil {
+		log.Printf("**Login Rejected**: %v", err)
+		return ctx.JSON(http.StatusUnauthorized, map[string]string{"Error": err.Error()})
+	}
+	return ctx.JSON(http.StatusOK, session)
+}
+
+func (ctrl *AuthController) Logout(ctx echo.Context) error {
+	ctrl.Sessions.Revoke(api.BearerToken(ctx))
+	return ctx.NoContent(http.StatusOK)
+}
@@ -0,0 +1,133 @@
+package controller
+
+import (
+	"dexbe/internal/domains/candle"
+	"dexbe/internal/domains/orderbook"
+	"dexbe/internal/infra/api"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/labstack/echo/v4"
+)
+
+const (
+	defaultCandleLimit = 500
+	maxCandleLimit     = 1000
+)
+
+type CandleController struct {
+	OrderBookStore *orderbook.OrderBookStore
+	Candles        *candle.Aggregator
+	RateLimits     *api.RateLimits
+}
+
+func NewCandleController(store *orderbook.OrderBookStore, candles *candle.Aggregator, limits *api.RateLimits) *CandleController {
+	return &CandleController{
+		OrderBookStore: store,
+		Candles:        candles,
+		RateLimits:     limits,
+	}
+}
+
+// GetCandles returns the candles of a pair, oldest first. ?interval defaults to 1m, ?from and ?to are
+// unix seconds bounding the candle open time.
+func (ctrl *CandleController) GetCandles(ctx echo.Context) error {
+	left, right := orderbook.GetPairKey(ctx.Param("in"), ctx.Param("out"))
+	pairId := left + "/" + right
+
+	interval, err := parseCandleInterval(ctx)
+	if err != nil {
+		return ctx.JSON(http.StatusBadRequest, map[string]string{"Error": err.Error()})
+	}
+
+	var from, to time.Time
+	for _, bound := range []struct {
+		name   string
+		target *time.Time
+	}{{"from", &from}, {"to", &to}} {
+		raw := ctx.QueryParam(bound.name)
+		if raw == "" {
+			continue
+		}
+		seconds, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil || seconds < 0 {
+			return ctx.JSON(http.StatusBadRequest, map[string]string{"Error": bound.name + " must be a unix timestamp in seconds"})
+		}
+		*bound.target = time.Unix(seconds, 0).UTC()
+	}
+	if !from.IsZero() && !to.IsZero() && !from.Before(to) {
+		return ctx.JSON(http.StatusBadRequest, map[string]string{"Error": "from must be before to"})
+	}
+
+	limit := defaultCandleLimit
+	if raw := ctx.QueryParam("limit"); raw != "" {
+		parsed, err := strconv.Atoi(raw)
+		if err != nil || parsed < 1 {
+			return ctx.JSON(http.StatusBadRequest, map[string]string{"Error": "limit must be a positive integer"})
+		}
+		limit = min(parsed, maxCandleLimit)
+	}
+
+	return ctx.JSON(http.StatusOK, map[string]any{
+		"pair":     pairId,
+		"interval": interval,
+		"candles":  ctrl.Candles.Range(pairId, interval, from, to, limit),
+	})
+}
+
+// HandleCandleWebSocket sends the recent candles of a pair and interval, then every update to them
+func (ctrl *CandleController) HandleCandleWebSocket(ctx echo.Context) error {
+	left, right := orderbook.GetPairKey(ctx.Param("in"), ctx.Param("out"))
+	pairId := left + "/" + right
+	if _, ok := ctrl.OrderBookStore.Books[pairId]; !ok {
+		return ctx.NoContent(http.StatusBadRequest)
+	}
+	interval, err := parseCandleInterval(ctx)
+	if err != nil {
+		return ctx.JSON(http.StatusBadRequest, map[string]string{"Error": err.Error()})
+	}
+
+	clientIP := ctx.RealIP()
+	ws, err := api.Upgrader.Upgrade(ctx.Response(), ctx.Request(), nil)
+	if err != nil {
+		return err
+	}
+	conn := api.NewWsClient(ws)
+	ctrl.Candles.AddSubscriber(pairId, interval, conn)
+	snapshot, _ := json.Marshal(map[string]any{
+		"event":    "CandleSnapshot",
+		"pair":     pairId,
+		"interval": interval,
+		"data":     ctrl.Candles.Range(pairId, interval, time.Time{}, time.Time{}, defaultCandleLimit),
+	})
+	conn.WriteMessage(websocket.TextMessage, snapshot)
+
+	go func() {
+		defer func() {
+			ctrl.Candles.RemoveSubscriber(pairId, interval, conn)
+			conn.Close()
+		}()
+		for {
+			_, _, err := ws.ReadMessage()
+			if err != nil {
+				break
+			}
+			if ok, retry := ctrl.RateLimits.AllowWsMessage(clientIP, api.WsMsgOther); !ok {
+				conn.WriteMessage(websocket.TextMessage, api.WsRateLimitedReply(api.WsMsgOther, retry))
+			}
+		}
+	}()
+
+	return nil
+}
+
+func parseCandleInterval(ctx echo.Context) (candle.Interval, error) {
+	raw := ctx.QueryParam("interval")
+	if raw == "" {
+		return candle.Interval1m, nil
+	}
+	return candle.ParseInterval(raw)
+}
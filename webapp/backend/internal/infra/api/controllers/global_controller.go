@@ -1,48 +1,129 @@
 package controller
 
 import (
+	"dexbe/internal/domains/auth"
+	"dexbe/internal/domains/orderbook"
 	"dexbe/internal/infra/api"
 	"encoding/json"
-	"github.com/ethereum/go-ethereum/common"
+	"fmt"
 	"github.com/gorilla/websocket"
 	"github.com/labstack/echo/v4"
 	"log"
+	"net/http"
+	"strconv"
 )
 
 type GlobalController struct {
+	OrderBookStore *orderbook.OrderBookStore
+	Sessions       *auth.SessionStore
+	RateLimits     *api.RateLimits
 }
 
-func NewGlobalController() *GlobalController {
-	return &GlobalController{}
+func NewGlobalController(store *orderbook.OrderBookStore, sessions *auth.SessionStore, limits *api.RateLimits) *GlobalController {
+	return &GlobalController{
+		OrderBookStore: store,
+		Sessions:       sessions,
+		RateLimits:     limits,
+	}
+}
+
+// resumePoint is the last event a reconnecting client saw
+type resumePoint struct {
+	Epoch string  `json:"epoch"`
+	Since *uint64 `json:"since"`
 }
 
+// HandleGlobalWebSocket streams a user's order events once the connection presents a SIWE session,
+// either as ?token= on the upgrade or as {"token": "..."} in the first message.
+//
+// Every event carries a per-address seq, and "Subscribed" reports the epoch and current seq. A
+// client reconnecting with the epoch and last seq it saw (?epoch=&since= or "epoch"/"since" next to
+// the token) gets the events it missed replayed before live ones. If they are no longer buffered,
+// it gets a "Resync" with all of its open orders as of a seq instead, followed by the events after it.
 func (ctrl *GlobalController) HandleGlobalWebSocket(ctx echo.Context) error {
-	conn, err := api.Upgrader.Upgrade(ctx.Response(), ctx.Request(), nil)
+	var session *auth.Session
+	if token := api.UpgradeToken(ctx); token != "" {
+		s, err := ctrl.Sessions.Get(token)
+		if err != nil {
+			return ctx.JSON(http.StatusUnauthorized, map[string]string{"Error": err.Error()})
+		}
+		session = s
+	}
+	resume := resumePoint{Epoch: ctx.QueryParam("epoch")}
+	if since := ctx.QueryParam("since"); since != "" {
+		seq, err := strconv.ParseUint(since, 10, 64)
+		if err != nil {
+			return ctx.JSON(http.StatusBadRequest, map[string]string{"Error": "invalid since"})
+		}
+		resume.Since = &seq
+	}
+
+	clientIP := ctx.RealIP()
+	ws, err := api.Upgrader.Upgrade(ctx.Response(), ctx.Request(), nil)
 	if err != nil {
 		return err
 	}
+	conn := api.NewWsClient(ws)
 	go func() {
 		defer conn.Close()
+		if session != nil {
+			ctrl.subscribe(conn, session, resume)
+			defer api.RemoveSubscriber(session.Address, conn)
+		}
 		for {
-			messageType, data, err := conn.ReadMessage()
+			messageType, data, err := ws.ReadMessage()
 			if err != nil {
 				break
 			}
-			if messageType == websocket.TextMessage {
+			msgType := api.WsMsgOther
+			if session == nil {
+				msgType = api.WsMsgAuth
+			}
+			if ok, retry := ctrl.RateLimits.AllowWsMessage(clientIP, msgType); !ok {
+				conn.WriteMessage(websocket.TextMessage, api.WsRateLimitedReply(msgType, retry))
+				continue
+			}
+			if messageType == websocket.TextMessage && session == nil {
 				var msg struct {
-					Address string `json:"address"`
+					Token string `json:"token"`
+					resumePoint
 				}
 				if err := json.Unmarshal(data, &msg); err != nil {
-					conn.Close()
 					log.Println("invalid message:", err)
 					continue
 				}
-				log.Printf("Websocket connected. Address: %+v", msg.Address)
-				addr := common.HexToAddress(msg.Address)
-				api.AddSubscriber(addr, conn)
+				s, err := ctrl.Sessions.Get(msg.Token)
+				if err != nil {
+					reply, _ := json.Marshal(map[string]any{"event": "Error", "data": err.Error()})
+					conn.WriteMessage(websocket.TextMessage, reply)
+					log.Printf("Websocket rejected: %v", err)
+					return
+				}
+				session = s
+				ctrl.subscribe(conn, session, msg.resumePoint)
+				defer api.RemoveSubscriber(session.Address, conn)
 			}
 		}
 	}()
 
 	return nil
 }
+
+func (ctrl *GlobalController) subscribe(conn *api.WsClient, session *auth.Session, resume resumePoint) {
+	log.Printf("Websocket connected. Address: %+v", session.Address)
+	addr := session.Address
+	openOrders := func() []map[string]string {
+		open := ctrl.OrderBookStore.GetOrdersByCreator(addr)
+		orders := make([]map[string]string, 0, len(open))
+		for _, o := range open {
+			orders = append(orders, o.ToStringMap())
+		}
+		return orders
+	}
+	if api.SubscribeUser(addr, conn, resume.Epoch, resume.Since, openOrders) {
+		return
+	}
+	reply, _ := json.Marshal(map[string]any{"event": "Error", "data": fmt.Sprintf("too many events for %s to resync, reconnect", addr.Hex())})
+	conn.WriteMessage(websocket.TextMessage, reply)
+	conn.Close()
+}
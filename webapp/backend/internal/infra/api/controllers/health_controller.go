@@ -0,0 +1,246 @@
+package controller
+
+import (
+	"context"
+	"dexbe/internal/domains/orderbook"
+	"dexbe/internal/infra/eth"
+	"fmt"
+	"math/big"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/labstack/echo/v4"
+)
+
+// Check outcomes. A warning is reported but does not take the backend out of rotation.
+const (
+	HealthOK   = "ok"
+	HealthWarn = "warn"
+	HealthFail = "fail"
+)
+
+// healthTimeout bounds the chain calls of one readiness probe
+const healthTimeout = 3 * time.Second
+
+// readyCacheTTL is how long the chain probes of one readiness check answer for, so a burst of
+// /readyz requests costs one round of RPC calls
+const readyCacheTTL = time.Second
+
+// HealthThresholds decide when a check warns or fails
+type HealthThresholds struct {
+	MaxBlockAge        time.Duration // head blocks older than this fail readiness, 0 only reports the age
+	MinOperatorBalance *big.Int      // wei the operator needs for gas
+	MatcherStaleAfter  time.Duration // matching cycles further apart than this mean the oracle is stuck
+	StuckAfter         time.Duration // settlements waiting longer than this for a receipt are stuck
+}
+
+type HealthController struct {
+	OrderBookStore  *orderbook.OrderBookStore
+	EthClient       *eth.EthClient
+	ExchangeAddress common.Address
+	RegistryAddress common.Address
+	Thresholds      HealthThresholds
+
+	probeMu  sync.Mutex // held while probing, so concurrent checks wait for one round
+	probed   map[string]healthCheck
+	probedAt time.Time
+}
+
+func NewHealthController(store *orderbook.OrderBookStore, ethClient *eth.EthClient, exchangeAddr, registryAddr string, thresholds HealthThresholds) *HealthController {
+	return &HealthController{
+		OrderBookStore:  store,
+		EthClient:       ethClient,
+		ExchangeAddress: common.HexToAddress(exchangeAddr),
+		RegistryAddress: common.HexToAddress(registryAddr),
+		Thresholds:      thresholds,
+	}
+}
+
+type healthCheck map[string]any
+
+func checkResult(status string, details map[string]any) healthCheck {
+	check := healthCheck{"status": status}
+	for key, value := range details {
+		check[key] = value
+	}
+	return check
+}
+
+func checkError(err error) healthCheck {
+	return healthCheck{"status": HealthFail, "error": err.Error()}
+}
+
+// respond answers 503 if any check failed, so a supervisor can go by the status code alone
+func respond(ctx echo.Context, checks map[string]healthCheck) error {
+	overall, code := HealthOK, http.StatusOK
+	for _, check := range checks {
+		switch check["status"] {
+		case HealthFail:
+			overall, code = HealthFail, http.StatusServiceUnavailable
+		case HealthWarn:
+			if overall == HealthOK {
+				overall = HealthWarn
+			}
+		}
+	}
+	return ctx.JSON(code, map[string]any{"status": overall, "checks": checks})
+}
+
+// Live answers whether the process should be restarted: only a matching loop that stopped turning
+// fails it. It never touches the chain.
+func (ctrl *HealthController) Live(ctx echo.Context) error {
+	return respond(ctx, map[string]healthCheck{"matcher": ctrl.checkMatcher()})
+}
+
+// Ready answers whether the backend can take orders and settle them: it is accepting orders, the
+// RPC node answers and is not lagging, the operator can pay for gas, both contracts are deployed
+// and the matcher is running. Stuck settlements are reported as a warning.
+func (ctrl *HealthController) Ready(ctx echo.Context) error {
+	checks := map[string]healthCheck{
+		"matcher":     ctrl.checkMatcher(),
+		"settlements": ctrl.checkSettlements(),
+		"orderEntry":  ctrl.checkOrderEntry(),
+	}
+	for name, check := range ctrl.chainChecks() {
+		checks[name] = check
+	}
+	return respond(ctx, checks)
+}
+
+// chainChecks runs the checks that call the RPC node, or returns the last round if it is younger
+// than readyCacheTTL. The probes are not tied to one request, so a client hanging up does not
+// leave a failed round behind for the others.
+func (ctrl *HealthController) chainChecks() map[string]healthCheck {
+	ctrl.probeMu.Lock()
+	defer ctrl.probeMu.Unlock()
+	if ctrl.probed != nil && time.Since(ctrl.probedAt) < readyCacheTTL {
+		return ctrl.probed
+	}
+
+	probeCtx, cancel := context.WithTimeout(context.Background(), healthTimeout)
+	defer cancel()
+
+	checks := make(map[string]healthCheck, 4)
+	probes := map[string]func(context.Context) healthCheck{
+		"rpc":      ctrl.checkRPC,
+		"operator": ctrl.checkOperator,
+		"exchange": func(c context.Context) healthCheck { return ctrl.checkContract(c, ctrl.ExchangeAddress) },
+		"registry": func(c context.Context) healthCheck { return ctrl.checkContract(c, ctrl.RegistryAddress) },
+	}
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	for name, probe := range probes {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			check := probe(probeCtx)
+			mu.Lock()
+			checks[name] = check
+			mu.Unlock()
+		}()
+	}
+	wg.Wait()
+	ctrl.probed, ctrl.probedAt = checks, time.Now()
+	return checks
+}
+
+func (ctrl *HealthController) checkMatcher() healthCheck {
+	if !ctrl.OrderBookStore.Accepting() {
+		// The oracle is stopped on purpose while shutdown drains settlements
+		return checkResult(HealthOK, map[string]any{"draining": true})
+	}
+	heartbeat := ctrl.OrderBookStore.MatcherHeartbeat()
+	if heartbeat.IsZero() {
+		return checkError(fmt.Errorf("matcher has not completed a cycle"))
+	}
+	age := time.Since(heartbeat)
+	details := map[string]any{"lastCycle": heartbeat.UTC(), "ageSeconds": age.Seconds()}
+	if age > ctrl.Thresholds.MatcherStaleAfter {
+		details["error"] = fmt.Sprintf("no matching cycle for %s", age.Round(time.Millisecond))
+		return checkResult(HealthFail, details)
+	}
+	return checkResult(HealthOK, details)
+}
+
+func (ctrl *HealthController) checkSettlements() healthCheck {
+	settlements := ctrl.OrderBookStore.Settlements(ctrl.Thresholds.StuckAfter)
+	status := HealthOK
+	if settlements.Stuck > 0 {
+		status = HealthWarn
+	}
+	return checkResult(status, map[string]any{
+		"inFlight":          settlements.InFlight,
+		"stuck":             settlements.Stuck,
+		"stuckOrders":       settlements.StuckOrders,
+		"oldestAgeSeconds":  settlements.OldestAge.Seconds(),
+		"stuckAfterSeconds": ctrl.Thresholds.StuckAfter.Seconds(),
+	})
+}
+
+func (ctrl *HealthController) checkOrderEntry() healthCheck {
+	if !ctrl.OrderBookStore.Accepting() {
+		return checkError(orderbook.ErrShuttingDown)
+	}
+	return checkResult(HealthOK, nil)
+}
+
+// checkRPC reports the head block and how far behind it is, both in time and, while the node is
+// syncing, in blocks
+func (ctrl *HealthController) checkRPC(ctx context.Context) healthCheck {
+	head, err := ctrl.EthClient.Client.HeaderByNumber(ctx, nil)
+	if err != nil {
+		return checkError(fmt.Errorf("RPC node unreachable: %w", err))
+	}
+	age := time.Since(time.Unix(int64(head.Time), 0))
+	details := map[string]any{"headBlock": head.Number.Uint64(), "headAgeSeconds": age.Seconds()}
+	progress, err := ctrl.EthClient.Client.SyncProgress(ctx)
+	if err != nil {
+		return checkError(fmt.Errorf("RPC node unreachable: %w", err))
+	}
+	if progress != nil {
+		details["syncing"] = true
+		details["lagBlocks"] = progress.HighestBlock - progress.CurrentBlock
+		details["error"] = "RPC node is still syncing"
+		return checkResult(HealthFail, details)
+	}
+	if ctrl.Thresholds.MaxBlockAge > 0 && age > ctrl.Thresholds.MaxBlockAge {
+		details["error"] = fmt.Sprintf("head block is %s old", age.Round(time.Second))
+		return checkResult(HealthFail, details)
+	}
+	return checkResult(HealthOK, details)
+}
+
+func (ctrl *HealthController) checkOperator(ctx context.Context) healthCheck {
+	operator := ctrl.EthClient.AuthTransact.From
+	balance, err := ctrl.EthClient.Client.BalanceAt(ctx, operator, nil)
+	if err != nil {
+		return checkError(fmt.Errorf("failed to read operator balance: %w", err))
+	}
+	details := map[string]any{
+		"address":    operator.Hex(),
+		"balance":    balance.String(),
+		"minBalance": ctrl.Thresholds.MinOperatorBalance.String(),
+	}
+	if balance.Cmp(ctrl.Thresholds.MinOperatorBalance) < 0 {
+		details["error"] = "operator balance below minimum, settlements will run out of gas"
+		return checkResult(HealthFail, details)
+	}
+	return checkResult(HealthOK, details)
+}
+
+// checkContract confirms there is code at addr, so the node is on the chain the contracts were
+// deployed to
+func (ctrl *HealthController) checkContract(ctx context.Context, addr common.Address) healthCheck {
+	code, err := ctrl.EthClient.Client.CodeAt(ctx, addr, nil)
+	if err != nil {
+		return checkError(fmt.Errorf("failed to read contract code: %w", err))
+	}
+	details := map[string]any{"address": addr.Hex()}
+	if len(code) == 0 {
+		details["error"] = "no contract deployed at this address"
+		return checkResult(HealthFail, details)
+	}
+	return checkResult(HealthOK, details)
+}
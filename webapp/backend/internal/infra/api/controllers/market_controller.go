@@ -0,0 +1,95 @@
+package controller
+
+import (
+	"dexbe/internal/domains/orderbook"
+	"dexbe/internal/infra/api"
+	"encoding/json"
+	"net/http"
+
+	"github.com/gorilla/websocket"
+	"github.com/labstack/echo/v4"
+)
+
+type MarketController struct {
+	OrderBookStore *orderbook.OrderBookStore
+	PriceFeed      *orderbook.PriceFeed
+	RateLimits     *api.RateLimits
+}
+
+func NewMarketController(store *orderbook.OrderBookStore, feed *orderbook.PriceFeed, limits *api.RateLimits) *MarketController {
+	return &MarketController{
+		OrderBookStore: store,
+		PriceFeed:      feed,
+		RateLimits:     limits,
+	}
+}
+
+// GetPrices returns best bid/ask, mid, spread and last trade of every book
+func (ctrl *MarketController) GetPrices(ctx echo.Context) error {
+	return ctx.JSON(http.StatusOK, map[string]any{"prices": ctrl.OrderBookStore.GetPriceSummaries()})
+}
+
+// GetPrice returns best bid/ask, mid, spread and last trade of one book
+func (ctrl *MarketController) GetPrice(ctx echo.Context) error {
+	summary, err := ctrl.OrderBookStore.GetPriceSummary(ctx.Param("in"), ctx.Param("out"))
+	if err != nil {
+		return ctx.JSON(http.StatusNotFound, map[string]string{"Error": err.Error()})
+	}
+	return ctx.JSON(http.StatusOK, summary)
+}
+
+// HandlePriceWebSocket sends the current prices, then a Price event whenever a pair's summary
+// changes. ?in and ?out restrict the stream to one pair.
+func (ctrl *MarketController) HandlePriceWebSocket(ctx echo.Context) error {
+	pairId := ""
+	in, out := ctx.QueryParam("in"), ctx.QueryParam("out")
+	if in != "" || out != "" {
+		summary, err := ctrl.OrderBookStore.GetPriceSummary(in, out)
+		if err != nil {
+			return ctx.JSON(http.StatusNotFound, map[string]string{"Error": err.Error()})
+		}
+		pairId = summary.Pair
+	}
+
+	clientIP := ctx.RealIP()
+	ws, err := api.Upgrader.Upgrade(ctx.Response(), ctx.Request(), nil)
+	if err != nil {
+		return err
+	}
+	conn := api.NewWsClient(ws)
+	ctrl.PriceFeed.AddSubscriber(conn, pairId)
+	// Taken after subscribing, so a change in between is in the snapshot or follows it as an event
+	summaries := ctrl.OrderBookStore.GetPriceSummaries()
+	if pairId != "" {
+		summary, err := ctrl.OrderBookStore.GetPriceSummary(in, out)
+		if err != nil {
+			ctrl.PriceFeed.RemoveSubscriber(conn)
+			conn.Close()
+			return nil
+		}
+		summaries = []*orderbook.PriceSummary{summary}
+	}
+	snapshot, _ := json.Marshal(map[string]any{
+		"event": "PriceSnapshot",
+		"data":  summaries,
+	})
+	conn.WriteMessage(websocket.TextMessage, snapshot)
+
+	go func() {
+		defer func() {
+			ctrl.PriceFeed.RemoveSubscriber(conn)
+			conn.Close()
+		}()
+		for {
+			_, _, err := ws.ReadMessage()
+			if err != nil {
+				break
+			}
+			if ok, retry := ctrl.RateLimits.AllowWsMessage(clientIP, api.WsMsgOther); !ok {
+				conn.WriteMessage(websocket.TextMessage, api.WsRateLimitedReply(api.WsMsgOther, retry))
+			}
+		}
+	}()
+
+	return nil
+}
@@ -2,6 +2,7 @@ package controller
 
 import (
 	"dexbe/internal/domains/nonce"
+	"dexbe/internal/infra/api"
 	"log"
 	"net/http"
 
@@ -10,12 +11,14 @@ import (
 )
 
 type NonceController struct {
-	Registry *nonce.NonceRegistry
+	Registry   *nonce.NonceRegistry
+	RateLimits *api.RateLimits
 }
 
-func NewNonceController(registry *nonce.NonceRegistry) *NonceController {
+func NewNonceController(registry *nonce.NonceRegistry, limits *api.RateLimits) *NonceController {
 	return &NonceController{
-		Registry: registry,
+		Registry:   registry,
+		RateLimits: limits,
 	}
 }
 
@@ -29,6 +32,10 @@ func (ctrl *NonceController) GetNextNonce(ctx echo.Context) error {
 		return ctx.JSON(http.StatusBadRequest, map[string]string{"Error": err.Error()})
 	}
 	hexAddr := common.HexToAddress(req.Address)
+	// Nothing is signed here, but the nonce being handed out is the address's, so it has its bucket
+	if limited := ctrl.RateLimits.AllowAddress(api.LimitGroupNonce, hexAddr); limited != nil {
+		return api.TooManyRequests(ctx, limited)
+	}
 	nonce := ctrl.Registry.Inc(hexAddr)
 	log.Printf("===INCOMING NONCE INCREMENT===\nAddress: %v\nNew nonce: %v", hexAddr, nonce)
 	return ctx.JSON(http.StatusOK, map[string]any{"nonce": nonce})
@@ -1,18 +1,70 @@
 package controller
 
 import (
+	"context"
 	"dexbe/internal/domains/order"
 	"dexbe/internal/domains/orderbook"
+	"dexbe/internal/domains/permit"
 	"dexbe/internal/domains/registry"
+	"dexbe/internal/infra/api"
+	"dexbe/internal/infra/metrics"
+	"dexbe/internal/infra/tracing"
+	"errors"
+	"fmt"
 	"log"
 	"math/big"
 	"net/http"
+	"time"
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/labstack/echo/v4"
+	"go.opentelemetry.io/otel/trace"
 )
 
+const permitMineTimeout = 30 * time.Second
+
+var ErrInvalidCancelSignature = errors.New("invalid cancel signature")
+
+// Order sources, the entry point an order came in through, as counted in the order metrics
+const (
+	OrderSourceREST = "rest"
+	OrderSourceFIX  = "fix"
+	OrderSourceGRPC = "grpc"
+)
+
+// orderAttempt follows one submission of an order through admission, counting the outcome and
+// tracing it as the first span of the order's trace
+type orderAttempt struct {
+	source string
+	id     string
+	opened bool // this submission opened the order's trace, so a rejection closes it
+	span   trace.Span
+}
+
+func startAttempt(o *order.Order, source, spanName string) *orderAttempt {
+	id := tracing.OrderID(o.CreatedBy, o.Nonce)
+	ctx, opened := tracing.StartOrder(id, tracing.AttrSource.String(source))
+	_, span := tracing.Start(ctx, spanName, tracing.AttrOrderID.String(id))
+	return &orderAttempt{source: source, id: id, opened: opened, span: span}
+}
+
+// reject counts the order as refused at reason: "malformed", "signature", "rate_limit", "permit",
+// "funding", "book" or "shutdown"
+func (attempt *orderAttempt) reject(reason string, err error) {
+	metrics.OrdersRejected.WithLabelValues(attempt.source, reason).Inc()
+	tracing.Fail(attempt.span, err)
+	attempt.span.End()
+	if attempt.opened {
+		tracing.EndOrder(attempt.id, "rejected", err)
+	}
+}
+
+func (attempt *orderAttempt) accept() {
+	metrics.OrdersAccepted.WithLabelValues(attempt.source).Inc()
+	attempt.span.End()
+}
+
 type OrderRequest struct {
 	CreatedBy        string           `json:"createdBy"`
 	SymbolIn         string           `json:"symbolIn"`
@@ -31,9 +83,10 @@ type Trigger struct {
 }
 
 type SwapInfoRequest struct {
-	Order            *OrderRequest `json:"order"`
-	Signature        string        `json:"signature"`
-	ConditionTrigger *Trigger      `json:"conditionTriggers"`
+	Order            *OrderRequest  `json:"order"`
+	Signature        string         `json:"signature"`
+	ConditionTrigger *Trigger       `json:"conditionTriggers"`
+	Permit           *PermitRequest `json:"permit,omitempty"`
 }
 
 type GetOrdersRequest struct {
@@ -47,32 +100,48 @@ type OrderController struct {
 	ChainId         int
 	ExchangeAddress string
 	TokenRegistry   *registry.Registry
+	PermitService   *permit.PermitService
+	RateLimits      *api.RateLimits // per-signer limits, taken once a signature is verified
 }
 
-func NewOrderController(orderBookStore *orderbook.OrderBookStore, chainId int, exchangeAddr string, registry *registry.Registry) *OrderController {
+func NewOrderController(orderBookStore *orderbook.OrderBookStore, chainId int, exchangeAddr string, registry *registry.Registry, permitService *permit.PermitService, limits *api.RateLimits) *OrderController {
 	return &OrderController{
 		OrderBookStore:  orderBookStore,
 		ChainId:         chainId,
 		ExchangeAddress: exchangeAddr,
 		TokenRegistry:   registry,
+		PermitService:   permitService,
+		RateLimits:      limits,
 	}
 }
 
 func (ctrl *OrderController) SendOrder(ctx echo.Context) error {
 	var req SwapInfoRequest
 	if err := ctx.Bind(&req); err != nil {
+		metrics.OrdersRejected.WithLabelValues(OrderSourceREST, "malformed").Inc()
 		return ctx.JSON(http.StatusBadRequest, map[string]string{"Error": err.Error()})
 	}
+	// Checked before a bundled permit is relayed, admitOrder checks again for the other entry points
+	if !ctrl.OrderBookStore.Accepting() {
+		metrics.OrdersRejected.WithLabelValues(OrderSourceREST, "shutdown").Inc()
+		return ctx.JSON(http.StatusServiceUnavailable, map[string]string{"Error": orderbook.ErrShuttingDown.Error()})
+	}
 	log.Printf("===INCOMING ORDER===\nORDER: %+v\nORDER SIGNATURE: %+v", req.Order, req.Signature)
 	// Order
 	convertedOrder := order.NewOrder(req.Order.CreatedBy, req.Order.SymbolIn, req.Order.SymbolOut, req.Order.AmtIn, req.Order.AmtOut, req.Order.Nonce, req.Signature, "", "", req.Order.Status, nil, "")
+	attempt := startAttempt(convertedOrder, OrderSourceREST, "api.SendOrder")
 	decoded_sign, _ := hexutil.Decode(req.Signature)
 	verify, err := convertedOrder.VerifyOrder(decoded_sign, big.NewInt(int64(ctrl.ChainId)), common.HexToAddress(ctrl.ExchangeAddress))
 	log.Printf("Verify Order: %v", verify)
 	if err != nil {
 		log.Printf("ERROR: %v", err)
+		attempt.reject("signature", err)
 		return ctx.JSON(http.StatusBadRequest, map[string]string{"Error": err.Error()})
 	}
+	// The signer is known now, so it pays for the order before any permit gas is spent
+	if err := ctrl.allowSigner(convertedOrder, attempt); err != nil {
+		return api.TooManyRequests(ctx, err)
+	}
 
 	// Conditional Order
 	if req.Order.ConditionalOrder != nil {
@@ -84,6 +153,7 @@ func (ctrl *OrderController) SendOrder(ctx echo.Context) error {
 		log.Printf("Verify Order: %v", verify)
 		if err != nil {
 			log.Printf("ERROR (Conditionl Order): %v", err)
+			attempt.reject("signature", err)
 			return ctx.JSON(http.StatusBadRequest, map[string]string{"Error": err.Error()})
 		}
 		convertedOrder.ConditionalOrder = convertedConditionalOrder
@@ -91,14 +161,108 @@ func (ctrl *OrderController) SendOrder(ctx echo.Context) error {
 		stopPriceBig := new(big.Int)
 		stopPriceBig, ok := new(big.Int).SetString(req.ConditionTrigger.StopPrice, 10)
 		if !ok {
+			attempt.reject("malformed", errors.New("invalid StopPrice"))
 			return ctx.JSON(http.StatusBadRequest, map[string]string{"Error": "Invalid StopPrice"})
 		}
 		convertedOrder.ConditionalOrder.TriggerPrice = stopPriceBig
 	}
-	ctrl.OrderBookStore.AddOrder(convertedOrder)
+
+	// Permit, so the approval is in place before funding is checked
+	if req.Permit != nil {
+		if err := ctrl.submitBundledPermit(ctx, req.Permit, convertedOrder); err != nil {
+			log.Printf("ERROR (Permit): %v", err)
+			attempt.reject("permit", err)
+			return ctx.JSON(http.StatusBadRequest, map[string]string{"Error": err.Error()})
+		}
+	}
+
+	if err := ctrl.admitOrder(convertedOrder, attempt); err != nil {
+		var fundingErr *registry.FundingError
+		if errors.As(err, &fundingErr) {
+			return ctx.JSON(http.StatusUnprocessableEntity, fundingErr.ToStringMap())
+		}
+		if errors.Is(err, orderbook.ErrShuttingDown) {
+			return ctx.JSON(http.StatusServiceUnavailable, map[string]string{"Error": err.Error()})
+		}
+		return ctx.JSON(http.StatusBadRequest, map[string]string{"Error": err.Error()})
+	}
 	return ctx.NoContent(http.StatusOK)
 }
 
+// allowSigner takes a token from the order group's address bucket for the verified signer
+func (ctrl *OrderController) allowSigner(o *order.Order, attempt *orderAttempt) *api.RateLimitError {
+	limited := ctrl.RateLimits.AllowAddress(api.LimitGroupOrder, o.CreatedBy)
+	if limited != nil {
+		attempt.reject("rate_limit", limited)
+	}
+	return limited
+}
+
+// admitOrder books a verified order once its signer is funded for it. Every entry point (REST,
+// FIX, gRPC) goes through here.
+func (ctrl *OrderController) admitOrder(o *order.Order, attempt *orderAttempt) error {
+	if !ctrl.OrderBookStore.Accepting() {
+		attempt.reject("shutdown", orderbook.ErrShuttingDown)
+		return orderbook.ErrShuttingDown
+	}
+	if err := ctrl.checkFunding(o); err != nil {
+		log.Printf("**Order Rejected**: %v", err)
+		attempt.reject("funding", err)
+		return err
+	}
+	if err := ctrl.OrderBookStore.AddOrder(o); err != nil {
+		attempt.reject("book", err)
+		return err
+	}
+	attempt.accept()
+	return nil
+}
+
+// submitBundledPermit relays a permit sent alongside an order and waits for it to be mined
+func (ctrl *OrderController) submitBundledPermit(ctx echo.Context, req *PermitRequest, o *order.Order) error {
+	p, sig, err := req.toPermit()
+	if err != nil {
+		return err
+	}
+	if p.Owner != o.CreatedBy {
+		return fmt.Errorf("permit owner %s does not match order creator %s", p.Owner.Hex(), o.CreatedBy.Hex())
+	}
+	if req.Symbol != o.SymbolIn {
+		return fmt.Errorf("permit token %s does not match order symbolIn %s", req.Symbol, o.SymbolIn)
+	}
+	waitCtx, cancel := context.WithTimeout(ctx.Request().Context(), permitMineTimeout)
+	defer cancel()
+	_, err = ctrl.PermitService.SubmitAndWait(waitCtx, p, req.Symbol, sig)
+	return err
+}
+
+// checkFunding verifies the signer can cover the order's remaining amount on top of
+// what their open orders in the same token already commit. The conditional leg is
+// usually funded by the parent fill, so it is not checked at admission.
+func (ctrl *OrderController) checkFunding(o *order.Order) error {
+	if o.AmtIn == nil || o.AmtIn.Sign() <= 0 {
+		return errors.New("invalid AmtIn")
+	}
+	required := new(big.Int).Set(o.AmtIn)
+	if o.FilledAmtIn != nil {
+		required.Sub(required, o.FilledAmtIn)
+	}
+	for _, open := range ctrl.OrderBookStore.GetOrdersByCreator(o.CreatedBy) {
+		if open.SymbolIn != o.SymbolIn {
+			continue
+		}
+		if open.Status != order.Matching && open.Status != order.PendingConfirmation {
+			continue
+		}
+		committed := new(big.Int).Set(open.AmtIn)
+		if open.FilledAmtIn != nil {
+			committed.Sub(committed, open.FilledAmtIn)
+		}
+		required.Add(required, committed)
+	}
+	return ctrl.TokenRegistry.CheckFunding(o.CreatedBy, common.HexToAddress(ctrl.ExchangeAddress), o.SymbolIn, required)
+}
+
 func (ctrl *OrderController) GetAllOrdersByAddress(ctx echo.Context) error {
 	addr := ctx.Param("address")
 	allOrdersByAddress := ctrl.OrderBookStore.GetOrdersByCreator(common.HexToAddress(addr))
@@ -109,13 +273,13 @@ func (ctrl *OrderController) GetAllOrdersByAddress(ctx echo.Context) error {
 	return ctx.JSON(http.StatusOK, map[string]any{"orders": converted})
 }
 
-// TODO, cancelled should not remove from map, but rather set status to cancelled and move record to order history
 type CancelOrderReq struct {
 	CreatedBy  string `json:"createdBy"`
 	Nonce      string `json:"nonce"`
 	LimitPrice string `json:"limitPrice"`
 	SymbolIn   string `json:"symbolIn"`
 	SymbolOut  string `json:"symbolOut"`
+	Signature  string `json:"signature"`
 }
 
 func (ctrl *OrderController) CancelOrder(ctx echo.Context) error {
@@ -124,8 +288,7 @@ func (ctrl *OrderController) CancelOrder(ctx echo.Context) error {
 		log.Printf("ERROR: %+v", err.Error())
 		return ctx.JSON(http.StatusBadRequest, map[string]string{"Error": err.Error()})
 	}
-	// Change to set status to cancelled (4), using createdby and nonce
-	log.Printf("===INCOMING ORDER REMOVAL===\nORDER")
+	log.Printf("===INCOMING ORDER REMOVAL===\nORDER: %s/%s", req.CreatedBy, req.Nonce)
 	addr := common.HexToAddress(req.CreatedBy)
 	nonce := new(big.Int)
 	nonce, ok := nonce.SetString(req.Nonce, 10)
@@ -140,10 +303,57 @@ func (ctrl *OrderController) CancelOrder(ctx echo.Context) error {
 		log.Println("invalid LimitPrice:", req.LimitPrice)
 		return ctx.JSON(http.StatusBadRequest, map[string]string{"Error": "Invalid LimitPrice"})
 	}
-	ctrl.OrderBookStore.RemoveOrder(addr, nonce, limitPrice, req.SymbolIn, req.SymbolOut)
+
+	decodedSign, err := hexutil.Decode(req.Signature)
+	if err != nil {
+		return ctx.JSON(http.StatusBadRequest, map[string]string{"Error": "Invalid Signature"})
+	}
+	if err := ctrl.SubmitCancel(addr, nonce, limitPrice, req.SymbolIn, req.SymbolOut, decodedSign); err != nil {
+		if errors.Is(err, ErrInvalidCancelSignature) {
+			return ctx.JSON(http.StatusUnauthorized, map[string]string{"Error": err.Error()})
+		}
+		if errors.Is(err, orderbook.ErrOrderNotFound) {
+			return ctx.JSON(http.StatusNotFound, map[string]string{"Error": err.Error()})
+		}
+		var limited *api.RateLimitError
+		if errors.As(err, &limited) {
+			return api.TooManyRequests(ctx, limited)
+		}
+		return ctx.JSON(http.StatusBadRequest, map[string]string{"Error": err.Error()})
+	}
 	return ctx.NoContent(http.StatusOK)
 }
 
+// SubmitOrder admits an already-signed order from a channel other than REST (such as FIX or gRPC):
+// signature, then the signer's rate limit, then funding, then the book
+func (ctrl *OrderController) SubmitOrder(o *order.Order, source string) error {
+	// VerifyOrder normalizes v in place, so check a copy and keep the signature as signed
+	attempt := startAttempt(o, source, "api.SubmitOrder")
+	sig := append([]byte(nil), o.Signature...)
+	if _, err := o.VerifyOrder(sig, big.NewInt(int64(ctrl.ChainId)), common.HexToAddress(ctrl.ExchangeAddress)); err != nil {
+		attempt.reject("signature", err)
+		return err
+	}
+	if err := ctrl.allowSigner(o, attempt); err != nil {
+		return err
+	}
+	return ctrl.admitOrder(o, attempt)
+}
+
+// SubmitCancel removes an order once its creator's signed Cancel(createdBy, nonce) checks out
+func (ctrl *OrderController) SubmitCancel(createdBy common.Address, nonce, limitPrice *big.Int, symbolIn, symbolOut string, signature []byte) error {
+	// Only the creator can cancel, proven by a signed Cancel(createdBy, nonce)
+	cancel := order.NewCancel(createdBy.Hex(), nonce.String())
+	if _, err := cancel.VerifyCancel(signature, big.NewInt(int64(ctrl.ChainId)), common.HexToAddress(ctrl.ExchangeAddress)); err != nil {
+		log.Printf("**Cancel Rejected**: %v", err)
+		return fmt.Errorf("%w: %v", ErrInvalidCancelSignature, err)
+	}
+	if err := ctrl.RateLimits.AllowAddress(api.LimitGroupOrder, createdBy); err != nil {
+		return err
+	}
+	return ctrl.OrderBookStore.RemoveOrder(createdBy, nonce, limitPrice, symbolIn, symbolOut, signature)
+}
+
 func (ctrl *OrderController) GetOrderBookSummary(ctx echo.Context) error {
 	symbolIn := ctx.Param("in")
 	symbolOut := ctx.Param("out")
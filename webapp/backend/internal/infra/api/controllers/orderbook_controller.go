@@ -1,12 +1,16 @@
 package controller
 
 import (
+	"dexbe/internal/domains/auth"
 	"dexbe/internal/domains/orderbook"
 	"dexbe/internal/infra/api"
 	"encoding/json"
+	"fmt"
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/gorilla/websocket"
 	"github.com/labstack/echo/v4"
 	"net/http"
+	"strconv"
 )
 
 type GetPriceRequest struct {
@@ -16,14 +20,58 @@ type GetPriceRequest struct {
 
 type OrderBookController struct {
 	OrderBookStore *orderbook.OrderBookStore
+	Sessions       *auth.SessionStore
+	RateLimits     *api.RateLimits
 }
 
-func NewOrderBookController(store *orderbook.OrderBookStore) *OrderBookController {
+func NewOrderBookController(store *orderbook.OrderBookStore, sessions *auth.SessionStore, limits *api.RateLimits) *OrderBookController {
 	return &OrderBookController{
 		OrderBookStore: store,
+		Sessions:       sessions,
+		RateLimits:     limits,
 	}
 }
 
+// snapshotMessage wraps the book's depth snapshot and seq for the websocket feed
+func snapshotMessage(book *orderbook.MarketOrderBook, view orderbook.DepthView) []byte {
+	book.Mu.RLock()
+	snapshot := book.DepthSnapshot(view)
+	book.Mu.RUnlock()
+	snapshot["event"] = "Snapshot"
+	data, _ := json.Marshal(snapshot)
+	return data
+}
+
+// parseDepthView reads ?group (a price increment such as 0.01) and ?depth (levels per side)
+func parseDepthView(ctx echo.Context) (orderbook.DepthView, error) {
+	depth := 0
+	if raw := ctx.QueryParam("depth"); raw != "" {
+		parsed, err := strconv.Atoi(raw)
+		if err != nil {
+			return orderbook.DepthView{}, fmt.Errorf("depth must be an integer")
+		}
+		depth = parsed
+	}
+	return orderbook.ParseDepthView(ctx.QueryParam("group"), depth)
+}
+
+// GetDepthSnapshot returns the aggregated book, grouped and capped by ?group and ?depth, with the
+// Depth seq it is current as of when a feed with the same parameters is running
+func (ctrl *OrderBookController) GetDepthSnapshot(ctx echo.Context) error {
+	view, err := parseDepthView(ctx)
+	if err != nil {
+		return ctx.JSON(http.StatusBadRequest, map[string]string{"Error": err.Error()})
+	}
+	snapshot, err := ctrl.OrderBookStore.GetOrderBookSnapshot(ctx.Param("in"), ctx.Param("out"), view)
+	if err != nil {
+		return ctx.JSON(http.StatusNotFound, map[string]string{"Error": err.Error()})
+	}
+	return ctx.JSON(http.StatusOK, snapshot)
+}
+
+// HandleOrderbookWebSocket sends a Snapshot followed by Depth deltas, grouped and capped by ?group and
+// ?depth; see depth.go in the orderbook package for the gap recovery procedure. Clients can send
+// {"type":"snapshot"} to resync.
 func (ctrl *OrderBookController) HandleOrderbookWebSocket(ctx echo.Context) error {
 	in := ctx.Param("in")
 	out := ctx.Param("out")
@@ -34,14 +82,19 @@ func (ctrl *OrderBookController) HandleOrderbookWebSocket(ctx echo.Context) erro
 		return ctx.NoContent(http.StatusBadRequest)
 	}
 
-	conn, err := api.Upgrader.Upgrade(ctx.Response(), ctx.Request(), nil)
+	view, err := parseDepthView(ctx)
+	if err != nil {
+		return ctx.JSON(http.StatusBadRequest, map[string]string{"Error": err.Error()})
+	}
+
+	clientIP := ctx.RealIP()
+	ws, err := api.Upgrader.Upgrade(ctx.Response(), ctx.Request(), nil)
 	if err != nil {
 		return err
 	}
-	book.AddSubscriber(conn)
-	snapshot := book.Snapshot()
-	data, _ := json.Marshal(snapshot)
-	conn.WriteMessage(websocket.TextMessage, data)
+	conn := api.NewWsClient(ws)
+	book.AddSubscriber(conn, view)
+	conn.WriteMessage(websocket.TextMessage, snapshotMessage(book, view))
 
 	go func() {
 		defer func() {
@@ -49,11 +102,81 @@ func (ctrl *OrderBookController) HandleOrderbookWebSocket(ctx echo.Context) erro
 			conn.Close()
 		}()
 		for {
-			_, _, err := conn.ReadMessage()
+			_, raw, err := ws.ReadMessage()
 			if err != nil {
 				break
 			}
+			if ok, retry := ctrl.RateLimits.AllowWsMessage(clientIP, api.WsMsgOther); !ok {
+				conn.WriteMessage(websocket.TextMessage, api.WsRateLimitedReply(api.WsMsgOther, retry))
+				continue
+			}
+			var msg struct {
+				Type string `json:"type"`
+			}
+			if json.Unmarshal(raw, &msg) == nil && msg.Type == "snapshot" {
+				conn.WriteMessage(websocket.TextMessage, snapshotMessage(book, view))
+			}
+		}
+	}()
+
+	return nil
+}
 
+func l3SnapshotMessage(book *orderbook.MarketOrderBook, viewer common.Address) []byte {
+	book.Mu.RLock()
+	snapshot := book.L3Snapshot(viewer)
+	book.Mu.RUnlock()
+	data, _ := json.Marshal(snapshot)
+	return data
+}
+
+// HandleL3WebSocket streams individual order events of a pair. Subscribers that pass a SIWE session
+// as ?token= also see maker and nonce on their own orders. Clients can send {"type":"snapshot"} to resync.
+func (ctrl *OrderBookController) HandleL3WebSocket(ctx echo.Context) error {
+	left, right := orderbook.GetPairKey(ctx.Param("in"), ctx.Param("out"))
+	book, ok := ctrl.OrderBookStore.Books[left+"/"+right]
+	if !ok {
+		return ctx.NoContent(http.StatusBadRequest)
+	}
+
+	var viewer common.Address
+	if token := api.UpgradeToken(ctx); token != "" {
+		session, err := ctrl.Sessions.Get(token)
+		if err != nil {
+			return ctx.JSON(http.StatusUnauthorized, map[string]string{"Error": err.Error()})
+		}
+		viewer = session.Address
+	}
+
+	clientIP := ctx.RealIP()
+	ws, err := api.Upgrader.Upgrade(ctx.Response(), ctx.Request(), nil)
+	if err != nil {
+		return err
+	}
+	conn := api.NewWsClient(ws)
+	book.AddL3Subscriber(conn, viewer)
+	conn.WriteMessage(websocket.TextMessage, l3SnapshotMessage(book, viewer))
+
+	go func() {
+		defer func() {
+			book.RemoveL3Subscriber(conn)
+			conn.Close()
+		}()
+		for {
+			_, raw, err := ws.ReadMessage()
+			if err != nil {
+				break
+			}
+			if ok, retry := ctrl.RateLimits.AllowWsMessage(clientIP, api.WsMsgOther); !ok {
+				conn.WriteMessage(websocket.TextMessage, api.WsRateLimitedReply(api.WsMsgOther, retry))
+				continue
+			}
+			var msg struct {
+				Type string `json:"type"`
+			}
+			if json.Unmarshal(raw, &msg) == nil && msg.Type == "snapshot" {
+				conn.WriteMessage(websocket.TextMessage, l3SnapshotMessage(book, viewer))
+			}
 		}
 	}()
 
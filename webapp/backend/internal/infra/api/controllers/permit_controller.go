@@ -0,0 +1,71 @@
+package controller
+
+import (
+	"dexbe/internal/domains/auth"
+	"dexbe/internal/domains/permit"
+	"dexbe/internal/infra/api"
+	"log"
+	"net/http"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/labstack/echo/v4"
+)
+
+type PermitRequest struct {
+	Symbol    string `json:"symbol"`
+	Owner     string `json:"owner"`
+	Spender   string `json:"spender"`
+	Value     string `json:"value"`
+	Nonce     string `json:"nonce"`
+	Deadline  string `json:"deadline"`
+	Signature string `json:"signature"`
+}
+
+func (req *PermitRequest) toPermit() (*permit.Permit, []byte, error) {
+	sig, err := hexutil.Decode(req.Signature)
+	if err != nil {
+		return nil, nil, err
+	}
+	return permit.NewPermit(req.Owner, req.Spender, req.Value, req.Nonce, req.Deadline), sig, nil
+}
+
+type PermitController struct {
+	PermitService *permit.PermitService
+	RateLimits    *api.RateLimits
+}
+
+func NewPermitController(permitService *permit.PermitService, limits *api.RateLimits) *PermitController {
+	return &PermitController{
+		PermitService: permitService,
+		RateLimits:    limits,
+	}
+}
+
+func (ctrl *PermitController) SubmitPermit(ctx echo.Context) error {
+	var req PermitRequest
+	if err := ctx.Bind(&req); err != nil {
+		return ctx.JSON(http.StatusBadRequest, map[string]string{"Error": err.Error()})
+	}
+	log.Printf("===INCOMING PERMIT===\nPERMIT: %+v", req)
+	p, sig, err := req.toPermit()
+	if err != nil {
+		return ctx.JSON(http.StatusBadRequest, map[string]string{"Error": err.Error()})
+	}
+	// The operator pays the gas, so only the owner may have a permit relayed on its own
+	session := api.SessionFromContext(ctx)
+	if session == nil {
+		return ctx.JSON(http.StatusUnauthorized, map[string]string{"Error": auth.ErrInvalidSession.Error()})
+	}
+	if session.Address != p.Owner {
+		return ctx.JSON(http.StatusForbidden, map[string]string{"Error": "session is not authorized for this permit owner"})
+	}
+	if limited := ctrl.RateLimits.AllowAddress(api.LimitGroupPermit, p.Owner); limited != nil {
+		return api.TooManyRequests(ctx, limited)
+	}
+	tx, err := ctrl.PermitService.Submit(p, req.Symbol, sig)
+	if err != nil {
+		log.Printf("ERROR: %v", err)
+		return ctx.JSON(http.StatusBadRequest, map[string]string{"Error": err.Error()})
+	}
+	return ctx.JSON(http.StatusOK, map[string]any{"transactionHash": tx.Hash().Hex()})
+}
@@ -0,0 +1,374 @@
+package controller
+
+import (
+	"dexbe/internal/domains/auth"
+	"dexbe/internal/domains/candle"
+	"dexbe/internal/domains/orderbook"
+	"dexbe/internal/domains/ticker"
+	"dexbe/internal/domains/trade"
+	"dexbe/internal/infra/api"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/gorilla/websocket"
+	"github.com/labstack/echo/v4"
+)
+
+// maxStreamSubscriptions bounds the channels one connection can hold
+const maxStreamSubscriptions = 64
+
+// StreamRequest is a client message on /stream. Op is one of subscribe, unsubscribe, auth or list;
+// Depth and Group apply to book channels, Token to auth.
+type StreamRequest struct {
+	Id      any    `json:"id,omitempty"`
+	Op      string `json:"op"`
+	Channel string `json:"channel,omitempty"`
+	Token   string `json:"token,omitempty"`
+	Depth   int    `json:"depth,omitempty"`
+	Group   string `json:"group,omitempty"`
+}
+
+type StreamController struct {
+	OrderBookStore *orderbook.OrderBookStore
+	Candles        *candle.Aggregator
+	Tickers        *ticker.TickerService
+	PriceFeed      *orderbook.PriceFeed
+	Sessions       *auth.SessionStore
+	RateLimits     *api.RateLimits
+}
+
+func NewStreamController(store *orderbook.OrderBookStore, candles *candle.Aggregator, tickers *ticker.TickerService, priceFeed *orderbook.PriceFeed, sessions *auth.SessionStore, limits *api.RateLimits) *StreamController {
+	return &StreamController{
+		OrderBookStore: store,
+		Candles:        candles,
+		Tickers:        tickers,
+		PriceFeed:      priceFeed,
+		Sessions:       sessions,
+		RateLimits:     limits,
+	}
+}
+
+// streamConn is the state of one /stream connection, only touched by its read loop
+type streamConn struct {
+	conn          *api.WsClient
+	session       *auth.Session
+	subscriptions map[string]func()
+}
+
+// HandleStreamWebSocket multiplexes every public and private feed over one socket. Clients send
+//
+//	{"op":"subscribe","channel":"book:SMASH/WETH","depth":20,"group":"0.01","id":1}
+//	{"op":"unsubscribe","channel":"book:SMASH/WETH","id":2}
+//	{"op":"auth","token":"<SIWE session token>","id":3}
+//	{"op":"list","id":4}
+//
+// and get {"event":"ack",...} or {"event":"error",...} echoing id, then channel data as
+// {"channel":"book:SMASH/WETH","data":{...}} in the same format as the dedicated endpoints.
+// Channels: book:<pair>, l3:<pair>, trades:<pair>, trades:*, candles:<pair>:<interval>, ticker,
+// prices, prices:<pair> and user (requires auth, or ?token= on the upgrade).
+func (ctrl *StreamController) HandleStreamWebSocket(ctx echo.Context) error {
+	var session *auth.Session
+	if token := api.UpgradeToken(ctx); token != "" {
+		s, err := ctrl.Sessions.Get(token)
+		if err != nil {
+			return ctx.JSON(http.StatusUnauthorized, map[string]string{"Error": err.Error()})
+		}
+		session = s
+	}
+
+	clientIP := ctx.RealIP()
+	ws, err := api.Upgrader.Upgrade(ctx.Response(), ctx.Request(), nil)
+	if err != nil {
+		return err
+	}
+	stream := &streamConn{
+		conn:          api.NewWsClient(ws),
+		session:       session,
+		subscriptions: make(map[string]func()),
+	}
+
+	go func() {
+		defer func() {
+			for _, unsubscribe := range stream.subscriptions {
+				unsubscribe()
+			}
+			stream.conn.Close()
+		}()
+		for {
+			_, raw, err := ws.ReadMessage()
+			if err != nil {
+				break
+			}
+			var req StreamRequest
+			if err := json.Unmarshal(raw, &req); err != nil {
+				stream.reply(req, fmt.Errorf("invalid message: %v", err))
+				continue
+			}
+			msgType := api.WsMsgOther
+			if req.Op == "auth" {
+				msgType = api.WsMsgAuth
+			}
+			if ok, retry := ctrl.RateLimits.AllowWsMessage(clientIP, msgType); !ok {
+				stream.conn.WriteMessage(websocket.TextMessage, api.WsRateLimitedReply(msgType, retry))
+				continue
+			}
+			ctrl.handle(stream, req)
+		}
+	}()
+
+	return nil
+}
+
+func (ctrl *StreamController) handle(stream *streamConn, req StreamRequest) {
+	switch req.Op {
+	case "subscribe":
+		if _, exists := stream.subscriptions[req.Channel]; exists {
+			stream.reply(req, fmt.Errorf("already subscribed to %s", req.Channel))
+			return
+		}
+		if len(stream.subscriptions) >= maxStreamSubscriptions {
+			stream.reply(req, fmt.Errorf("at most %d subscriptions per connection", maxStreamSubscriptions))
+			return
+		}
+		channel, unsubscribe, snapshot, err := ctrl.subscribe(stream, req)
+		if err != nil {
+			stream.reply(req, err)
+			return
+		}
+		if _, exists := stream.subscriptions[channel]; exists {
+			unsubscribe()
+			stream.reply(req, fmt.Errorf("already subscribed to %s", channel))
+			return
+		}
+		stream.subscriptions[channel] = unsubscribe
+		req.Channel = channel
+		stream.reply(req, nil)
+		if snapshot != nil {
+			snapshot()
+		}
+
+	case "unsubscribe":
+		channel := canonicalChannel(req.Channel)
+		unsubscribe, exists := stream.subscriptions[channel]
+		if !exists {
+			stream.reply(req, fmt.Errorf("not subscribed to %s", req.Channel))
+			return
+		}
+		unsubscribe()
+		delete(stream.subscriptions, channel)
+		req.Channel = channel
+		stream.reply(req, nil)
+
+	case "auth":
+		s, err := ctrl.Sessions.Get(req.Token)
+		if err != nil {
+			stream.reply(req, err)
+			return
+		}
+		if stream.session != nil && stream.session.Address != s.Address {
+			stream.reply(req, fmt.Errorf("connection is already signed in as %s", stream.session.Address.Hex()))
+			return
+		}
+		stream.session = s
+		stream.reply(req, nil)
+
+	case "list":
+		channels := make([]string, 0, len(stream.subscriptions))
+		for channel := range stream.subscriptions {
+			channels = append(channels, channel)
+		}
+		stream.conn.WriteJSON(map[string]any{"event": "subscriptions", "id": req.Id, "channels": channels})
+
+	default:
+		stream.reply(req, fmt.Errorf("unknown op %q", req.Op))
+	}
+}
+
+// subscribe attaches a channel of the connection to its feed. It returns the canonical channel name,
+// the function detaching it again and, for channels with an initial state, a function sending it.
+func (ctrl *StreamController) subscribe(stream *streamConn, req StreamRequest) (string, func(), func(), error) {
+	kind, arg, _ := strings.Cut(req.Channel, ":")
+	switch kind {
+	case "book", "l3":
+		pairId, err := ctrl.pair(arg)
+		if err != nil {
+			return "", nil, nil, err
+		}
+		book := ctrl.OrderBookStore.Books[pairId]
+		channel := kind + ":" + pairId
+		sink := stream.conn.Channel(channel)
+		if kind == "l3" {
+			var viewer common.Address
+			if stream.session != nil {
+				viewer = stream.session.Address
+			}
+			book.AddL3Subscriber(sink, viewer)
+			return channel, func() { book.RemoveL3Subscriber(sink) }, func() {
+				sink.WriteMessage(websocket.TextMessage, l3SnapshotMessage(book, viewer))
+			}, nil
+		}
+		view, err := orderbook.ParseDepthView(req.Group, req.Depth)
+		if err != nil {
+			return "", nil, nil, err
+		}
+		book.AddSubscriber(sink, view)
+		return channel, func() { book.RemoveSubscriber(sink) }, func() {
+			sink.WriteMessage(websocket.TextMessage, snapshotMessage(book, view))
+		}, nil
+
+	case "trades":
+		pairId := trade.AllPairs
+		if arg != trade.AllPairs {
+			p, err := ctrl.pair(arg)
+			if err != nil {
+				return "", nil, nil, err
+			}
+			pairId = p
+		}
+		channel := "trades:" + pairId
+		sink := stream.conn.Channel(channel)
+		trades := ctrl.OrderBookStore.Trades
+		trades.AddSubscriber(pairId, sink)
+		var snapshot func()
+		if pairId != trade.AllPairs {
+			snapshot = func() {
+				encoded, _ := json.Marshal(map[string]any{
+					"event": "TradeSnapshot",
+					"pair":  pairId,
+					"data":  trades.List(pairId, 0, defaultTradeLimit),
+				})
+				sink.WriteMessage(websocket.TextMessage, encoded)
+			}
+		}
+		return channel, func() { trades.RemoveSubscriber(pairId, sink) }, snapshot, nil
+
+	case "candles":
+		cut := strings.LastIndex(arg, ":")
+		if cut < 0 {
+			return "", nil, nil, fmt.Errorf("candles channel must be candles:<pair>:<interval>")
+		}
+		pairId, err := ctrl.pair(arg[:cut])
+		if err != nil {
+			return "", nil, nil, err
+		}
+		interval, err := candle.ParseInterval(arg[cut+1:])
+		if err != nil {
+			return "", nil, nil, err
+		}
+		channel := fmt.Sprintf("candles:%s:%s", pairId, interval)
+		sink := stream.conn.Channel(channel)
+		ctrl.Candles.AddSubscriber(pairId, interval, sink)
+		return channel, func() { ctrl.Candles.RemoveSubscriber(pairId, interval, sink) }, func() {
+			encoded, _ := json.Marshal(map[string]any{
+				"event":    "CandleSnapshot",
+				"pair":     pairId,
+				"interval": interval,
+				"data":     ctrl.Candles.Range(pairId, interval, time.Time{}, time.Time{}, defaultCandleLimit),
+			})
+			sink.WriteMessage(websocket.TextMessage, encoded)
+		}, nil
+
+	case "ticker":
+		sink := stream.conn.Channel("ticker")
+		ctrl.Tickers.AddSubscriber(sink)
+		return "ticker", func() { ctrl.Tickers.RemoveSubscriber(sink) }, func() {
+			encoded, _ := json.Marshal(map[string]any{"event": "TickerSnapshot", "data": ctrl.Tickers.Tickers()})
+			sink.WriteMessage(websocket.TextMessage, encoded)
+		}, nil
+
+	case "prices":
+		pairId := ""
+		channel := "prices"
+		if arg != "" {
+			p, err := ctrl.pair(arg)
+			if err != nil {
+				return "", nil, nil, err
+			}
+			pairId = p
+			channel = "prices:" + pairId
+		}
+		sink := stream.conn.Channel(channel)
+		ctrl.PriceFeed.AddSubscriber(sink, pairId)
+		return channel, func() { ctrl.PriceFeed.RemoveSubscriber(sink) }, func() {
+			summaries := ctrl.OrderBookStore.GetPriceSummaries()
+			if pairId != "" {
+				base, quote, _ := strings.Cut(pairId, "/")
+				summary, err := ctrl.OrderBookStore.GetPriceSummary(base, quote)
+				if err != nil {
+					return
+				}
+				summaries = []*orderbook.PriceSummary{summary}
+			}
+			encoded, _ := json.Marshal(map[string]any{"event": "PriceSnapshot", "data": summaries})
+			sink.WriteMessage(websocket.TextMessage, encoded)
+		}, nil
+
+	case "user":
+		if stream.session == nil {
+			return "", nil, nil, fmt.Errorf("user channel requires auth")
+		}
+		address := stream.session.Address
+		sink := stream.conn.Channel("user")
+		api.AddSubscriber(address, sink)
+		return "user", func() { api.RemoveSubscriber(address, sink) }, nil, nil
+	}
+	return "", nil, nil, fmt.Errorf("unknown channel %q", req.Channel)
+}
+
+// pair resolves "A/B" in either order to the id of an existing book
+func (ctrl *StreamController) pair(arg string) (string, error) {
+	in, out, ok := strings.Cut(arg, "/")
+	if !ok {
+		return "", fmt.Errorf("pair must look like BASE/QUOTE, got %q", arg)
+	}
+	left, right := orderbook.GetPairKey(in, out)
+	pairId := left + "/" + right
+	if _, exists := ctrl.OrderBookStore.Books[pairId]; !exists {
+		return "", fmt.Errorf("order book for %s not found", pairId)
+	}
+	return pairId, nil
+}
+
+// canonicalChannel orders the pair of a channel name the way subscribe does
+func canonicalChannel(channel string) string {
+	kind, arg, ok := strings.Cut(channel, ":")
+	if !ok {
+		return channel
+	}
+	suffix := ""
+	if kind == "candles" {
+		if cut := strings.LastIndex(arg, ":"); cut >= 0 {
+			arg, suffix = arg[:cut], arg[cut:]
+		}
+	}
+	in, out, ok := strings.Cut(arg, "/")
+	if !ok {
+		return channel
+	}
+	left, right := orderbook.GetPairKey(in, out)
+	return kind + ":" + left + "/" + right + suffix
+}
+
+// reply acknowledges req, or reports err for it
+func (stream *streamConn) reply(req StreamRequest, err error) {
+	if err != nil {
+		stream.conn.WriteJSON(map[string]any{
+			"event":   "error",
+			"id":      req.Id,
+			"op":      req.Op,
+			"channel": req.Channel,
+			"error":   err.Error(),
+		})
+		return
+	}
+	stream.conn.WriteJSON(map[string]any{
+		"event":   "ack",
+		"id":      req.Id,
+		"op":      req.Op,
+		"channel": req.Channel,
+	})
+}
@@ -0,0 +1,73 @@
+package controller
+
+import (
+	"dexbe/internal/domains/orderbook"
+	"dexbe/internal/domains/ticker"
+	"dexbe/internal/infra/api"
+	"encoding/json"
+	"net/http"
+
+	"github.com/gorilla/websocket"
+	"github.com/labstack/echo/v4"
+)
+
+type TickerController struct {
+	Tickers    *ticker.TickerService
+	RateLimits *api.RateLimits
+}
+
+func NewTickerController(tickers *ticker.TickerService, limits *api.RateLimits) *TickerController {
+	return &TickerController{
+		Tickers:    tickers,
+		RateLimits: limits,
+	}
+}
+
+// GetTickers returns the 24h ticker of every book, or of one book when ?in and ?out are given
+func (ctrl *TickerController) GetTickers(ctx echo.Context) error {
+	in, out := ctx.QueryParam("in"), ctx.QueryParam("out")
+	if in == "" && out == "" {
+		return ctx.JSON(http.StatusOK, map[string]any{"tickers": ctrl.Tickers.Tickers()})
+	}
+
+	left, right := orderbook.GetPairKey(in, out)
+	t := ctrl.Tickers.Get(left + "/" + right)
+	if t == nil {
+		return ctx.JSON(http.StatusNotFound, map[string]string{"Error": "order book for " + left + "/" + right + " not found"})
+	}
+	return ctx.JSON(http.StatusOK, map[string]any{"tickers": []*ticker.Ticker{t}})
+}
+
+// HandleTickerWebSocket sends every ticker, then each ticker again whenever it changes
+func (ctrl *TickerController) HandleTickerWebSocket(ctx echo.Context) error {
+	clientIP := ctx.RealIP()
+	ws, err := api.Upgrader.Upgrade(ctx.Response(), ctx.Request(), nil)
+	if err != nil {
+		return err
+	}
+	conn := api.NewWsClient(ws)
+	ctrl.Tickers.AddSubscriber(conn)
+	snapshot, _ := json.Marshal(map[string]any{
+		"event": "TickerSnapshot",
+		"data":  ctrl.Tickers.Tickers(),
+	})
+	conn.WriteMessage(websocket.TextMessage, snapshot)
+
+	go func() {
+		defer func() {
+			ctrl.Tickers.RemoveSubscriber(conn)
+			conn.Close()
+		}()
+		for {
+			_, _, err := ws.ReadMessage()
+			if err != nil {
+				break
+			}
+			if ok, retry := ctrl.RateLimits.AllowWsMessage(clientIP, api.WsMsgOther); !ok {
+				conn.WriteMessage(websocket.TextMessage, api.WsRateLimitedReply(api.WsMsgOther, retry))
+			}
+		}
+	}()
+
+	return nil
+}
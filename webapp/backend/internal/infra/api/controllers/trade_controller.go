@@ -0,0 +1,99 @@
+package controller
+
+import (
+	"dexbe/internal/domains/orderbook"
+	"dexbe/internal/infra/api"
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/websocket"
+	"github.com/labstack/echo/v4"
+)
+
+const (
+	defaultTradeLimit = 50
+	maxTradeLimit     = 500
+)
+
+type TradeController struct {
+	OrderBookStore *orderbook.OrderBookStore
+	RateLimits     *api.RateLimits
+}
+
+func NewTradeController(store *orderbook.OrderBookStore, limits *api.RateLimits) *TradeController {
+	return &TradeController{
+		OrderBookStore: store,
+		RateLimits:     limits,
+	}
+}
+
+// GetTrades returns the newest trades of a pair; ?before=<id> pages back through older trades
+func (ctrl *TradeController) GetTrades(ctx echo.Context) error {
+	left, right := orderbook.GetPairKey(ctx.Param("in"), ctx.Param("out"))
+	pairId := left + "/" + right
+
+	limit := defaultTradeLimit
+	if raw := ctx.QueryParam("limit"); raw != "" {
+		parsed, err := strconv.Atoi(raw)
+		if err != nil || parsed < 1 {
+			return ctx.JSON(http.StatusBadRequest, map[string]string{"Error": "limit must be a positive integer"})
+		}
+		limit = min(parsed, maxTradeLimit)
+	}
+
+	var before uint64
+	if raw := ctx.QueryParam("before"); raw != "" {
+		parsed, err := strconv.ParseUint(raw, 10, 64)
+		if err != nil {
+			return ctx.JSON(http.StatusBadRequest, map[string]string{"Error": "before must be a trade id"})
+		}
+		before = parsed
+	}
+
+	return ctx.JSON(http.StatusOK, map[string]any{
+		"pair":   pairId,
+		"trades": ctrl.OrderBookStore.Trades.List(pairId, before, limit),
+	})
+}
+
+// HandleTradeWebSocket sends the latest trades of a pair, then streams each new trade as it is confirmed
+func (ctrl *TradeController) HandleTradeWebSocket(ctx echo.Context) error {
+	left, right := orderbook.GetPairKey(ctx.Param("in"), ctx.Param("out"))
+	pairId := left + "/" + right
+	if _, ok := ctrl.OrderBookStore.Books[pairId]; !ok {
+		return ctx.NoContent(http.StatusBadRequest)
+	}
+
+	clientIP := ctx.RealIP()
+	ws, err := api.Upgrader.Upgrade(ctx.Response(), ctx.Request(), nil)
+	if err != nil {
+		return err
+	}
+	conn := api.NewWsClient(ws)
+	ctrl.OrderBookStore.Trades.AddSubscriber(pairId, conn)
+	snapshot, _ := json.Marshal(map[string]any{
+		"event": "TradeSnapshot",
+		"pair":  pairId,
+		"data":  ctrl.OrderBookStore.Trades.List(pairId, 0, defaultTradeLimit),
+	})
+	conn.WriteMessage(websocket.TextMessage, snapshot)
+
+	go func() {
+		defer func() {
+			ctrl.OrderBookStore.Trades.RemoveSubscriber(pairId, conn)
+			conn.Close()
+		}()
+		for {
+			_, _, err := ws.ReadMessage()
+			if err != nil {
+				break
+			}
+			if ok, retry := ctrl.RateLimits.AllowWsMessage(clientIP, api.WsMsgOther); !ok {
+				conn.WriteMessage(websocket.TextMessage, api.WsRateLimitedReply(api.WsMsgOther, retry))
+			}
+		}
+	}()
+
+	return nil
+}
@@ -0,0 +1,354 @@
+package api
+
+import (
+	"dexbe/internal/domains/auth"
+	"encoding/json"
+	"fmt"
+	"log"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/labstack/echo/v4"
+	"golang.org/x/time/rate"
+)
+
+// Route groups and websocket message types that can be given their own limits
+const (
+	LimitGroupOrder  = "order"
+	LimitGroupNonce  = "nonce"
+	LimitGroupPermit = "permit"
+	LimitGroupAuth   = "auth"
+	LimitGroupWs     = "ws"
+	LimitGroupAdmin  = "admin"
+	LimitGroupMarket = "market"
+
+	LimitKeyIP      = "ip"
+	LimitKeyAddress = "address"
+)
+
+const bucketIdleTimeout = 10 * time.Minute
+
+// RateLimit is a token bucket refilled at Rate tokens per second holding at most Burst tokens
+type RateLimit struct {
+	Rate  float64
+	Burst int
+}
+
+func (l RateLimit) String() string {
+	return fmt.Sprintf("%g/%d", l.Rate, l.Burst)
+}
+
+type bucket struct {
+	limiter  *rate.Limiter
+	lastSeen time.Time
+}
+
+// RateLimiter holds one token bucket per key (client IP, signer address, ...)
+type RateLimiter struct {
+	limit     RateLimit
+	buckets   map[string]*bucket
+	lastPrune time.Time
+	mu        sync.Mutex
+}
+
+func NewRateLimiter(limit RateLimit) *RateLimiter {
+	return &RateLimiter{
+		limit:     limit,
+		buckets:   make(map[string]*bucket),
+		lastPrune: time.Now(),
+	}
+}
+
+// Allow takes a token for key, or reports how long the caller should wait before retrying
+func (limiter *RateLimiter) Allow(key string) (bool, time.Duration) {
+	now := time.Now()
+
+	limiter.mu.Lock()
+	defer limiter.mu.Unlock()
+
+	if now.Sub(limiter.lastPrune) > bucketIdleTimeout {
+		for k, b := range limiter.buckets {
+			if now.Sub(b.lastSeen) > bucketIdleTimeout {
+				delete(limiter.buckets, k)
+			}
+		}
+		limiter.lastPrune = now
+	}
+
+	b, exists := limiter.buckets[key]
+	if !exists {
+		b = &bucket{limiter: rate.NewLimiter(rate.Limit(limiter.limit.Rate), limiter.limit.Burst)}
+		limiter.buckets[key] = b
+	}
+	b.lastSeen = now
+
+	reservation := b.limiter.ReserveN(now, 1)
+	if !reservation.OK() {
+		return false, time.Second
+	}
+	if delay := reservation.DelayFrom(now); delay > 0 {
+		reservation.CancelAt(now)
+		return false, delay
+	}
+	return true, 0
+}
+
+// RateLimitPolicy limits one route group or websocket message type by client IP and by signer address
+type RateLimitPolicy struct {
+	IP      *RateLimiter
+	Address *RateLimiter
+}
+
+// RateLimits is the set of policies, keyed by route group or "ws:<message type>"
+type RateLimits struct {
+	policies map[string]*RateLimitPolicy
+	sessions *auth.SessionStore // resolves bearer tokens for the address buckets
+}
+
+// DefaultRateLimits returns limits sized for interactive use; order and nonce routes do signature
+// recovery or on-chain work and are the tightest
+func DefaultRateLimits() map[string]map[string]RateLimit {
+	return map[string]map[string]RateLimit{
+		LimitGroupOrder:    {LimitKeyIP: {Rate: 10, Burst: 20}, LimitKeyAddress: {Rate: 5, Burst: 10}},
+		LimitGroupNonce:    {LimitKeyIP: {Rate: 10, Burst: 20}, LimitKeyAddress: {Rate: 5, Burst: 10}},
+		LimitGroupPermit:   {LimitKeyIP: {Rate: 2, Burst: 5}, LimitKeyAddress: {Rate: 1, Burst: 3}},
+		LimitGroupAuth:     {LimitKeyIP: {Rate: 2, Burst: 10}},
+		LimitGroupAdmin:    {LimitKeyIP: {Rate: 5, Burst: 10}},
+		LimitGroupMarket:   {LimitKeyIP: {Rate: 20, Burst: 40}},
+		LimitGroupWs:       {LimitKeyIP: {Rate: 1, Burst: 10}},
+		"ws:" + WsMsgAuth:  {LimitKeyIP: {Rate: 1, Burst: 5}},
+		"ws:" + WsMsgOther: {LimitKeyIP: {Rate: 5, Burst: 20}},
+	}
+}
+
+func NewRateLimits(config map[string]map[string]RateLimit, sessions *auth.SessionStore) *RateLimits {
+	limits := &RateLimits{policies: make(map[string]*RateLimitPolicy), sessions: sessions}
+	for group, keys := range config {
+		policy := &RateLimitPolicy{}
+		if l, ok := keys[LimitKeyIP]; ok && l.Rate > 0 {
+			policy.IP = NewRateLimiter(l)
+		}
+		if l, ok := keys[LimitKeyAddress]; ok && l.Rate > 0 {
+			policy.Address = NewRateLimiter(l)
+		}
+		limits.policies[group] = policy
+		log.Printf("Rate limit %s: ip=%v address=%v", group, keys[LimitKeyIP], keys[LimitKeyAddress])
+	}
+	return limits
+}
+
+// ParseRateLimits overrides defaults with a spec like "order.ip=10/20,order.address=5/10,ws:auth.ip=1/5",
+// where each value is refill rate per second / burst. A rate of 0 disables that limit.
+func ParseRateLimits(spec string, defaults map[string]map[string]RateLimit) (map[string]map[string]RateLimit, error) {
+	result := make(map[string]map[string]RateLimit, len(defaults))
+	for group, keys := range defaults {
+		result[group] = make(map[string]RateLimit, len(keys))
+		for key, l := range keys {
+			result[group][key] = l
+		}
+	}
+	for _, entry := range strings.Split(spec, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		name, value, ok := strings.Cut(entry, "=")
+		if !ok {
+			return nil, fmt.Errorf("rate limit %q: expected group.key=rate/burst", entry)
+		}
+		dot := strings.LastIndex(name, ".")
+		if dot <= 0 {
+			return nil, fmt.Errorf("rate limit %q: expected group.key=rate/burst", entry)
+		}
+		group, key := name[:dot], name[dot+1:]
+		if key != LimitKeyIP && key != LimitKeyAddress {
+			return nil, fmt.Errorf("rate limit %q: key must be %s or %s", entry, LimitKeyIP, LimitKeyAddress)
+		}
+		rateStr, burstStr, ok := strings.Cut(value, "/")
+		if !ok {
+			return nil, fmt.Errorf("rate limit %q: expected rate/burst", entry)
+		}
+		r, err := strconv.ParseFloat(rateStr, 64)
+		if err != nil || r < 0 {
+			return nil, fmt.Errorf("rate limit %q: invalid rate", entry)
+		}
+		burst, err := strconv.Atoi(burstStr)
+		if err != nil || burst < 1 {
+			return nil, fmt.Errorf("rate limit %q: invalid burst", entry)
+		}
+		if result[group] == nil {
+			result[group] = make(map[string]RateLimit)
+		}
+		result[group][key] = RateLimit{Rate: r, Burst: burst}
+	}
+	return result, nil
+}
+
+// RateLimitError reports which of a group's buckets ran out and how long until it has a token again
+type RateLimitError struct {
+	Group string
+	Key   string
+	Retry time.Duration
+}
+
+func (err *RateLimitError) Error() string {
+	return fmt.Sprintf("rate limit exceeded for %s by %s, retry after %dms", err.Group, err.Key, err.Retry.Milliseconds())
+}
+
+// Middleware limits a route group by client IP and, when the policy has one and the caller has a
+// session, by session address
+func (limits *RateLimits) Middleware(group string) echo.MiddlewareFunc {
+	return limits.middleware(group, true)
+}
+
+// IPMiddleware limits a route group by client IP only, for routes whose handler takes the address
+// bucket itself once it has verified who signed the request
+func (limits *RateLimits) IPMiddleware(group string) echo.MiddlewareFunc {
+	return limits.middleware(group, false)
+}
+
+func (limits *RateLimits) middleware(group string, bySession bool) echo.MiddlewareFunc {
+	policy := limits.policies[group]
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(ctx echo.Context) error {
+			if policy == nil {
+				return next(ctx)
+			}
+			var signer *common.Address
+			if bySession && policy.Address != nil {
+				if addr, ok := limits.sessionAddress(ctx); ok {
+					signer = &addr
+				}
+			}
+			if ok, key, retry := limits.Allow(group, ctx.RealIP(), signer); !ok {
+				return TooManyRequests(ctx, &RateLimitError{Group: group, Key: key, Retry: retry})
+			}
+			return next(ctx)
+		}
+	}
+}
+
+// Allow takes a token from group's buckets for clientIP and, when known, the signer. When denied it
+// returns the key that ran out and how long until a token is available.
+func (limits *RateLimits) Allow(group, clientIP string, signer *common.Address) (bool, string, time.Duration) {
+	policy := limits.policies[group]
+	if policy == nil {
+		return true, "", 0
+	}
+	if policy.IP != nil {
+		if ok, retry := policy.IP.Allow(clientIP); !ok {
+			return false, LimitKeyIP, retry
+		}
+	}
+	if policy.Address != nil && signer != nil {
+		if ok, retry := policy.Address.Allow(signer.Hex()); !ok {
+			return false, LimitKeyAddress, retry
+		}
+	}
+	return true, "", 0
+}
+
+// AllowAddress takes a token from group's address bucket for an address the caller has already
+// verified, such as the recovered signer of an order, or says why it was denied
+func (limits *RateLimits) AllowAddress(group string, addr common.Address) *RateLimitError {
+	if limits == nil {
+		return nil
+	}
+	policy := limits.policies[group]
+	if policy == nil || policy.Address == nil {
+		return nil
+	}
+	if ok, retry := policy.Address.Allow(addr.Hex()); !ok {
+		log.Printf("**Rate Limited**: %s by %s, retry after %v", group, addr.Hex(), retry)
+		return &RateLimitError{Group: group, Key: LimitKeyAddress, Retry: retry}
+	}
+	return nil
+}
+
+// AllowWsMessage limits an inbound websocket message of msgType from clientIP.
+// Message types without their own policy share the "ws:other" policy.
+func (limits *RateLimits) AllowWsMessage(clientIP, msgType string) (bool, time.Duration) {
+	policy, exists := limits.policies["ws:"+msgType]
+	if !exists {
+		policy = limits.policies["ws:"+WsMsgOther]
+	}
+	if policy == nil || policy.IP == nil {
+		return true, 0
+	}
+	return policy.IP.Allow(clientIP)
+}
+
+// WsRateLimitedReply is sent to a websocket client whose message was dropped for exceeding its limit
+func WsRateLimitedReply(msgType string, retry time.Duration) []byte {
+	encoded, _ := json.Marshal(map[string]any{
+		"event": "Error",
+		"data": map[string]any{
+			"code":         http.StatusTooManyRequests,
+			"message":      "rate limit exceeded",
+			"type":         msgType,
+			"retryAfterMs": retry.Milliseconds(),
+		},
+	})
+	return encoded
+}
+
+// TooManyRequests answers 429 with a Retry-After header for a request denied by err
+func TooManyRequests(ctx echo.Context, err *RateLimitError) error {
+	seconds := int(math.Ceil(err.Retry.Seconds()))
+	if seconds < 1 {
+		seconds = 1
+	}
+	ctx.Response().Header().Set("Retry-After", strconv.Itoa(seconds))
+	log.Printf("**Rate Limited**: %s %s by %s (%s), retry after %ds", ctx.Request().Method, ctx.Path(), err.Key, ctx.RealIP(), seconds)
+	return ctx.JSON(http.StatusTooManyRequests, map[string]any{
+		"Error":        "rate limit exceeded",
+		"Group":        err.Group,
+		"Key":          err.Key,
+		"RetryAfterMs": err.Retry.Milliseconds(),
+	})
+}
+
+// sessionAddress is the address of the caller's SIWE session, either already on the context or
+// named by its bearer token. Nothing the request body claims is trusted: without a session the
+// request is only limited by IP, unless its handler verifies a signer and calls AllowAddress.
+func (limits *RateLimits) sessionAddress(ctx echo.Context) (common.Address, bool) {
+	if session := SessionFromContext(ctx); session != nil {
+		return session.Address, true
+	}
+	if limits.sessions == nil {
+		return common.Address{}, false
+	}
+	token := UpgradeToken(ctx)
+	if token == "" {
+		return common.Address{}, false
+	}
+	session, err := limits.sessions.Get(token)
+	if err != nil {
+		return common.Address{}, false
+	}
+	return session.Address, true
+}
+
+// IPExtractor picks the client IP that rate limits and logs go by. With no trusted proxies it is
+// the peer address of the connection, so clients cannot pick their own bucket by sending
+// X-Forwarded-For. Otherwise X-Forwarded-For is only followed through the trusted CIDR ranges.
+func IPExtractor(trustedProxies []string) (echo.IPExtractor, error) {
+	if len(trustedProxies) == 0 {
+		return echo.ExtractIPDirect(), nil
+	}
+	options := []echo.TrustOption{echo.TrustLoopback(false), echo.TrustLinkLocal(false), echo.TrustPrivateNet(false)}
+	for _, proxy := range trustedProxies {
+		_, ipNet, err := net.ParseCIDR(proxy)
+		if err != nil {
+			return nil, fmt.Errorf("trusted proxy %q is not a CIDR range", proxy)
+		}
+		options = append(options, echo.TrustIPRange(ipNet))
+	}
+	return echo.ExtractIPFromXFFHeader(options...), nil
+}
@@ -0,0 +1,14 @@
+package router
+
+import (
+	"dexbe/internal/infra/api/controllers"
+	"github.com/labstack/echo/v4"
+)
+
+func RegisterAdminRoutes(e *echo.Echo, adminController *controller.AdminController, requireAdmin ...echo.MiddlewareFunc) {
+	admins := e.Group("/admin", requireAdmin...)
+	admins.GET("/matching", adminController.GetMatchingSettings)
+	admins.POST("/matching", adminController.UpdateMatchingSettings)
+	admins.GET("/admins", adminController.GetAdmins)
+	admins.GET("/audit", adminController.GetAuditTrail)
+}
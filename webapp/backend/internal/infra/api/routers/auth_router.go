@@ -0,0 +1,13 @@
+package router
+
+import (
+	"dexbe/internal/infra/api/controllers"
+	"github.com/labstack/echo/v4"
+)
+
+func RegisterAuthRoutes(e *echo.Echo, authController *controller.AuthController, groupMiddleware ...echo.MiddlewareFunc) {
+	auth := e.Group("/auth", groupMiddleware...)
+	auth.POST("/challenge", authController.GetChallenge)
+	auth.POST("/login", authController.Login)
+	auth.POST("/logout", authController.Logout)
+}
@@ -0,0 +1,13 @@
+package router
+
+import (
+	"dexbe/internal/infra/api/controllers"
+
+	"github.com/labstack/echo/v4"
+)
+
+func RegisterCandleRoutes(e *echo.Echo, candleController *controller.CandleController, groupMiddleware ...echo.MiddlewareFunc) {
+	candles := e.Group("/candles", groupMiddleware...)
+	candles.GET("/ws/:in/:out", candleController.HandleCandleWebSocket)
+	candles.GET("/:in/:out", candleController.GetCandles)
+}
@@ -0,0 +1,14 @@
+package router
+
+import (
+	"dexbe/internal/infra/api/controllers"
+
+	"github.com/labstack/echo/v4"
+)
+
+// RegisterHealthRoutes exposes the probes. /healthz never leaves the process and is not limited,
+// like /metrics; /readyz calls the RPC node, so it takes readyMiddleware.
+func RegisterHealthRoutes(e *echo.Echo, healthController *controller.HealthController, readyMiddleware ...echo.MiddlewareFunc) {
+	e.GET("/healthz", healthController.Live)
+	e.GET("/readyz", healthController.Ready, readyMiddleware...)
+}
@@ -0,0 +1,14 @@
+package router
+
+import (
+	"dexbe/internal/infra/api/controllers"
+
+	"github.com/labstack/echo/v4"
+)
+
+func RegisterMarketRoutes(e *echo.Echo, marketController *controller.MarketController, groupMiddleware ...echo.MiddlewareFunc) {
+	market := e.Group("/market", groupMiddleware...)
+	market.GET("/price", marketController.GetPrices)
+	market.GET("/price/:in/:out", marketController.GetPrice)
+	market.GET("/ws", marketController.HandlePriceWebSocket)
+}
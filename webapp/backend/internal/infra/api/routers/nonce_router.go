@@ -5,7 +5,7 @@ import (
 	"github.com/labstack/echo/v4"
 )
 
-func RegisterNoncewRoutes(e *echo.Echo, nonceController *controller.NonceController) {
-	orders := e.Group("/nonce")
+func RegisterNoncewRoutes(e *echo.Echo, nonceController *controller.NonceController, groupMiddleware ...echo.MiddlewareFunc) {
+	orders := e.Group("/nonce", groupMiddleware...)
 	orders.POST("", nonceController.GetNextNonce)
 }
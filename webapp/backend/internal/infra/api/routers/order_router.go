@@ -1,15 +1,16 @@
 package router
 
 import (
+	"dexbe/internal/infra/api"
 	"dexbe/internal/infra/api/controllers"
 	"github.com/labstack/echo/v4"
 )
 
-func RegisterOrderRoutes(e *echo.Echo, orderController *controller.OrderController) {
-	orders := e.Group("/order")
+func RegisterOrderRoutes(e *echo.Echo, orderController *controller.OrderController, requireSession echo.MiddlewareFunc, groupMiddleware ...echo.MiddlewareFunc) {
+	orders := e.Group("/order", groupMiddleware...)
 	orders.POST("/limit", orderController.SendOrder)
 	orders.DELETE("", orderController.CancelOrder)
-	orders.GET("/:address", orderController.GetAllOrdersByAddress)
-	orders.GET("/history/:address", orderController.GetPastHistory)
+	orders.GET("/:address", orderController.GetAllOrdersByAddress, requireSession, api.RequireSessionAddress("address"))
+	orders.GET("/history/:address", orderController.GetPastHistory, requireSession, api.RequireSessionAddress("address"))
 	orders.GET("/:in/:out", orderController.GetOrderBookSummary)
 }
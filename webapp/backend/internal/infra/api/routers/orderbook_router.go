@@ -6,7 +6,9 @@ import (
 	"github.com/labstack/echo/v4"
 )
 
-func RegisterOrderBookRoutes(e *echo.Echo, orderBookController *controller.OrderBookController) {
-	orderbooks := e.Group("/orderbook")
+func RegisterOrderBookRoutes(e *echo.Echo, orderBookController *controller.OrderBookController, groupMiddleware ...echo.MiddlewareFunc) {
+	orderbooks := e.Group("/orderbook", groupMiddleware...)
 	orderbooks.GET("/ws/:in/:out", orderBookController.HandleOrderbookWebSocket)
+	orderbooks.GET("/l3/ws/:in/:out", orderBookController.HandleL3WebSocket)
+	orderbooks.GET("/:in/:out/snapshot", orderBookController.GetDepthSnapshot)
 }
@@ -0,0 +1,11 @@
+package router
+
+import (
+	"dexbe/internal/infra/api/controllers"
+	"github.com/labstack/echo/v4"
+)
+
+func RegisterPermitRoutes(e *echo.Echo, permitController *controller.PermitController, requireSession echo.MiddlewareFunc, groupMiddleware ...echo.MiddlewareFunc) {
+	permits := e.Group("/permit", groupMiddleware...)
+	permits.POST("", permitController.SubmitPermit, requireSession)
+}
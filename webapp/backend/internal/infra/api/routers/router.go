@@ -1,14 +1,26 @@
 package router
 
 import (
+	"dexbe/internal/infra/api"
 	"dexbe/internal/infra/api/controllers"
+	"dexbe/internal/infra/metrics"
 	"github.com/labstack/echo/v4"
 )
 
-func RegisterAllRoutes(e *echo.Echo, globalController *controller.GlobalController, orderController *controller.OrderController, orderBookController *controller.OrderBookController, nonceController *controller.NonceController, tokenController *controller.TokenController) {
-	RegisterOrderRoutes(e, orderController)
-	RegisterOrderBookRoutes(e, orderBookController)
-	RegisterNoncewRoutes(e, nonceController)
-	RegisterTokenRoutes(e, tokenController)
-	e.GET("/ws", globalController.HandleGlobalWebSocket)
+func RegisterAllRoutes(e *echo.Echo, limits *api.RateLimits, requireSession echo.MiddlewareFunc, requireAdmin echo.MiddlewareFunc, globalController *controller.GlobalController, authController *controller.AuthController, orderController *controller.OrderController, orderBookController *controller.OrderBookController, nonceController *controller.NonceController, tokenController *controller.TokenController, permitController *controller.PermitController, adminController *controller.AdminController, tradeController *controller.TradeController, candleController *controller.CandleController, tickerController *controller.TickerController, marketController *controller.MarketController, streamController *controller.StreamController, healthController *controller.HealthController) {
+	RegisterAuthRoutes(e, authController, limits.Middleware(api.LimitGroupAuth))
+	RegisterOrderRoutes(e, orderController, requireSession, limits.IPMiddleware(api.LimitGroupOrder))
+	RegisterOrderBookRoutes(e, orderBookController, limits.Middleware(api.LimitGroupWs))
+	RegisterNoncewRoutes(e, nonceController, limits.IPMiddleware(api.LimitGroupNonce))
+	RegisterTokenRoutes(e, tokenController, limits.Middleware(api.LimitGroupAdmin), requireSession, requireAdmin)
+	RegisterPermitRoutes(e, permitController, requireSession, limits.IPMiddleware(api.LimitGroupPermit))
+	RegisterTradeRoutes(e, tradeController, limits.Middleware(api.LimitGroupMarket))
+	RegisterCandleRoutes(e, candleController, limits.Middleware(api.LimitGroupMarket))
+	RegisterTickerRoutes(e, tickerController, limits.Middleware(api.LimitGroupMarket))
+	RegisterMarketRoutes(e, marketController, limits.Middleware(api.LimitGroupMarket))
+	RegisterStreamRoutes(e, streamController, limits.Middleware(api.LimitGroupWs))
+	RegisterAdminRoutes(e, adminController, limits.Middleware(api.LimitGroupAdmin), requireSession, requireAdmin)
+	e.GET("/ws", globalController.HandleGlobalWebSocket, limits.Middleware(api.LimitGroupWs))
+	e.GET("/metrics", echo.WrapHandler(metrics.Handler()))
+	RegisterHealthRoutes(e, healthController, limits.Middleware(api.LimitGroupMarket))
 }
@@ -0,0 +1,11 @@
+package router
+
+import (
+	"dexbe/internal/infra/api/controllers"
+
+	"github.com/labstack/echo/v4"
+)
+
+func RegisterStreamRoutes(e *echo.Echo, streamController *controller.StreamController, groupMiddleware ...echo.MiddlewareFunc) {
+	e.GET("/stream", streamController.HandleStreamWebSocket, groupMiddleware...)
+}
@@ -0,0 +1,13 @@
+package router
+
+import (
+	"dexbe/internal/infra/api/controllers"
+
+	"github.com/labstack/echo/v4"
+)
+
+func RegisterTickerRoutes(e *echo.Echo, tickerController *controller.TickerController, groupMiddleware ...echo.MiddlewareFunc) {
+	tickers := e.Group("/ticker", groupMiddleware...)
+	tickers.GET("", tickerController.GetTickers)
+	tickers.GET("/ws", tickerController.HandleTickerWebSocket)
+}
@@ -6,7 +6,7 @@ import (
 	"github.com/labstack/echo/v4"
 )
 
-func RegisterTokenRoutes(e *echo.Echo, tokenController *controller.TokenController) {
+func RegisterTokenRoutes(e *echo.Echo, tokenController *controller.TokenController, requireAdmin ...echo.MiddlewareFunc) {
 	orderbooks := e.Group("/token")
-	orderbooks.POST("/add", tokenController.AddToken)
+	orderbooks.POST("/add", tokenController.AddToken, requireAdmin...)
 }
@@ -0,0 +1,13 @@
+package router
+
+import (
+	"dexbe/internal/infra/api/controllers"
+
+	"github.com/labstack/echo/v4"
+)
+
+func RegisterTradeRoutes(e *echo.Echo, tradeController *controller.TradeController, groupMiddleware ...echo.MiddlewareFunc) {
+	trades := e.Group("/trades", groupMiddleware...)
+	trades.GET("/ws/:in/:out", tradeController.HandleTradeWebSocket)
+	trades.GET("/:in/:out", tradeController.GetTrades)
+}
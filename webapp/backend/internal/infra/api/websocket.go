@@ -1,15 +1,31 @@
 package api
 
 import (
+	"dexbe/internal/infra/metrics"
 	"encoding/json"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/gorilla/websocket"
 	"net/http"
+	"strconv"
+	"sync"
+	"time"
 )
 
+// GlobalQueueLength returns how many user events are waiting to be numbered and sent
+func GlobalQueueLength() int {
+	return len(GlobalCh)
+}
+
 var ( // Hacky solution, but works for project use!
-	GlobalCh    chan Message                                = make(chan Message, 100)
-	Subscribers map[common.Address]map[*websocket.Conn]bool = map[common.Address]map[*websocket.Conn]bool{}
+	GlobalCh      chan Message                             = make(chan Message, 1024)
+	Subscribers   map[common.Address]map[WsSubscriber]bool = map[common.Address]map[WsSubscriber]bool{}
+	subscribersMu sync.RWMutex
+)
+
+// Inbound websocket message types, used to pick a rate limit policy
+const (
+	WsMsgAuth  = "auth"
+	WsMsgOther = "other"
 )
 
 var Upgrader = websocket.Upgrader{
@@ -18,53 +34,246 @@ var Upgrader = websocket.Upgrader{
 	},
 }
 
+// WsSubscriber is what feeds stream websocket messages to: a whole connection, or one channel of a
+// multiplexed connection
+type WsSubscriber interface {
+	WriteMessage(messageType int, data []byte) error
+	Close() error
+}
+
+// queuedSubscriber is a subscriber with a bounded send queue that disconnects when it overflows
+type queuedSubscriber interface {
+	QueueFree() int
+}
+
 type Message struct {
 	RecipientID common.Address
-	Payload     []byte
+	Event       string
+	Data        json.RawMessage
+}
+
+// UserReplayBuffer is how many past events of each address are kept for reconnecting clients
+const UserReplayBuffer = 512
+
+// StreamEpoch names this run's numbering of user events. Seq ids restart with the process, so a
+// client resuming with an id from another epoch gets a snapshot instead.
+var StreamEpoch = strconv.FormatInt(time.Now().UnixNano(), 36)
+
+// userStreamIdleTimeout is how long the events of an address without subscribers are kept. A client
+// resuming after its stream was evicted gets a Resync.
+const userStreamIdleTimeout = 30 * time.Minute
+
+// userStream numbers one address's events and keeps the latest for replay, guarded by subscribersMu
+type userStream struct {
+	seq      uint64
+	replay   [][]byte // encoded events seq-len(replay)+1 .. seq
+	lastSeen time.Time
+}
+
+var userStreams = map[common.Address]*userStream{}
+
+func AddSubscriber(addr common.Address, conn WsSubscriber) {
+	subscribersMu.Lock()
+	defer subscribersMu.Unlock()
+	addSubscriberLocked(addr, conn)
 }
 
-func AddSubscriber(addr common.Address, conn *websocket.Conn) {
+func addSubscriberLocked(addr common.Address, conn WsSubscriber) {
 	if Subscribers[addr] == nil {
-		Subscribers[addr] = make(map[*websocket.Conn]bool)
+		Subscribers[addr] = make(map[WsSubscriber]bool)
+	}
+	if !Subscribers[addr][conn] {
+		metrics.Subscribers.WithLabelValues("user").Inc()
 	}
 	Subscribers[addr][conn] = true
 }
 
-func RemoveSubscriber(addr common.Address, conn *websocket.Conn) {
-	delete(Subscribers[addr], conn)
+func RemoveSubscriber(addr common.Address, conn WsSubscriber) {
+	subscribersMu.Lock()
+	defer subscribersMu.Unlock()
+	removeSubscriberLocked(addr, conn)
 }
 
-func NotifyUpdate(event string, target common.Address, data any) {
-	msg := map[string]any{
-		"event": event,
-		"data":  data,
+func removeSubscriberLocked(addr common.Address, conn WsSubscriber) {
+	if stream, exists := userStreams[addr]; exists {
+		// The idle timeout of the replay buffer counts from the last subscriber leaving
+		stream.lastSeen = time.Now()
+	}
+	if Subscribers[addr][conn] {
+		metrics.Subscribers.WithLabelValues("user").Dec()
+		delete(Subscribers[addr], conn)
+	}
+	if len(Subscribers[addr]) == 0 {
+		delete(Subscribers, addr)
+	}
+}
+
+// UserSeq returns the seq of the latest event sent to addr, 0 if there was none this epoch
+func UserSeq(addr common.Address) uint64 {
+	subscribersMu.RLock()
+	defer subscribersMu.RUnlock()
+	if stream, exists := userStreams[addr]; exists {
+		return stream.seq
+	}
+	return 0
+}
+
+// ResumeSubscriber subscribes conn to addr's events from the one after since: intro, if any, is sent
+// first, then the buffered events after since, then live events, with nothing missed or reordered in
+// between. It returns false without subscribing when events after since have already left the
+// replay buffer, since is ahead of the stream, the replay and intro would not fit in conn's send
+// queue, or a write fails.
+func ResumeSubscriber(addr common.Address, conn WsSubscriber, since uint64, intro []byte) bool {
+	subscribersMu.Lock()
+	defer subscribersMu.Unlock()
+
+	stream := userStreams[addr]
+	if stream == nil {
+		stream = &userStream{}
+	}
+	if since > stream.seq || stream.seq-since > uint64(len(stream.replay)) {
+		return false
+	}
+	// Replaying more than the queue holds would only disconnect the client as a slow consumer
+	if queued, ok := conn.(queuedSubscriber); ok && stream.seq-since >= uint64(queued.QueueFree()) {
+		return false
+	}
+	if intro != nil {
+		if err := conn.WriteMessage(websocket.TextMessage, intro); err != nil {
+			return false
+		}
 	}
-	encoded, _ := json.Marshal(msg)
+	for _, payload := range stream.replay[uint64(len(stream.replay))-(stream.seq-since):] {
+		if err := conn.WriteMessage(websocket.TextMessage, payload); err != nil {
+			return false
+		}
+	}
+	addSubscriberLocked(addr, conn)
+	return true
+}
+
+// pruneUserStreamsLocked drops the replay buffers of addresses that have had no subscriber and no
+// event for userStreamIdleTimeout
+func pruneUserStreamsLocked(now time.Time) {
+	for addr, stream := range userStreams {
+		if len(Subscribers[addr]) == 0 && now.Sub(stream.lastSeen) > userStreamIdleTimeout {
+			delete(userStreams, addr)
+		}
+	}
+}
+
+// resyncAttempts bounds how often a snapshot is retaken when the replay buffer overflows meanwhile
+const resyncAttempts = 3
+
+// SubscribeUser subscribes conn to addr's events for a client that last saw since in epoch (nil
+// since for a fresh client). It starts with a "Subscribed" reporting the seq the stream continues
+// after, followed by any missed events. If they are no longer buffered, it starts with a "Resync"
+// carrying openOrders() as of a seq instead, followed by the events after it. It returns false
+// when the buffer overflowed on every attempt.
+func SubscribeUser(addr common.Address, conn WsSubscriber, epoch string, since *uint64, openOrders func() []map[string]string) bool {
+	subscribed := func(seq uint64) []byte {
+		reply, _ := json.Marshal(map[string]any{"event": "Subscribed", "data": map[string]any{
+			"address": addr.Hex(),
+			"epoch":   StreamEpoch,
+			"seq":     seq,
+		}})
+		return reply
+	}
+
+	if since == nil {
+		seq := UserSeq(addr)
+		if ResumeSubscriber(addr, conn, seq, subscribed(seq)) {
+			return true
+		}
+	} else if epoch == StreamEpoch && ResumeSubscriber(addr, conn, *since, subscribed(*since)) {
+		return true
+	}
+
+	// The gap can't be replayed: snapshot the open orders, then replay from the seq taken before the
+	// snapshot. Events in between may already show in it; they carry full order state, so applying
+	// them again is harmless.
+	for attempt := 0; attempt < resyncAttempts; attempt++ {
+		seq := UserSeq(addr)
+		resync, _ := json.Marshal(map[string]any{"event": "Resync", "data": map[string]any{
+			"address": addr.Hex(),
+			"epoch":   StreamEpoch,
+			"seq":     seq,
+			"orders":  openOrders(),
+		}})
+		if ResumeSubscriber(addr, conn, seq, resync) {
+			return true
+		}
+	}
+	return false
+}
+
+func NotifyUpdate(event string, target common.Address, data any) {
+	encoded, _ := json.Marshal(data)
 	toSend := Message{
 		RecipientID: target,
-		Payload:     encoded,
+		Event:       event,
+		Data:        encoded,
 	}
 
-	select {
-	case GlobalCh <- toSend:
-	default:
-	}
+	// The broadcaster only queues onto per-connection writers, so this waits out bursts at most
+	GlobalCh <- toSend
 }
 
+// StartBroadcast numbers each user event, keeps it for replay and sends it to the address's
+// subscribers. Sequencing and delivery happen under one lock with ResumeSubscriber, and writes
+// only queue onto the connection, so holding it is cheap. Once a minute it drops the replay buffers
+// of addresses idle for userStreamIdleTimeout.
 func StartBroadcast() {
 	go func() {
-		for msg := range GlobalCh {
-			for addr := range Subscribers {
-				if msg.RecipientID == addr {
-					for conn := range Subscribers[addr] {
-						err := conn.WriteMessage(websocket.TextMessage, msg.Payload)
-						if err != nil {
-							RemoveSubscriber(addr, conn)
-							conn.Close()
-						}
-					}
+		prune := time.NewTicker(time.Minute)
+		defer prune.Stop()
+		for {
+			select {
+			case msg, ok := <-GlobalCh:
+				if !ok {
+					return
 				}
+				broadcast(msg)
+			case now := <-prune.C:
+				subscribersMu.Lock()
+				pruneUserStreamsLocked(now)
+				subscribersMu.Unlock()
 			}
 		}
 	}()
 }
+
+func broadcast(msg Message) {
+	subscribersMu.Lock()
+	stream := userStreams[msg.RecipientID]
+	if stream == nil {
+		stream = &userStream{}
+		userStreams[msg.RecipientID] = stream
+	}
+	stream.seq++
+	stream.lastSeen = time.Now()
+	payload, _ := json.Marshal(map[string]any{
+		"event": msg.Event,
+		"seq":   stream.seq,
+		"data":  msg.Data,
+	})
+	stream.replay = append(stream.replay, payload)
+	if len(stream.replay) > UserReplayBuffer {
+		stream.replay = stream.replay[len(stream.replay)-UserReplayBuffer:]
+	}
+
+	var failed []WsSubscriber
+	for conn := range Subscribers[msg.RecipientID] {
+		if err := conn.WriteMessage(websocket.TextMessage, payload); err != nil {
+			failed = append(failed, conn)
+		}
+	}
+	for _, conn := range failed {
+		removeSubscriberLocked(msg.RecipientID, conn)
+	}
+	subscribersMu.Unlock()
+
+	for _, conn := range failed {
+		conn.Close()
+	}
+}
@@ -0,0 +1,272 @@
+package api
+
+import (
+	"context"
+	"dexbe/internal/infra/metrics"
+	"encoding/json"
+	"errors"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+var (
+	// WsSendQueue is how many outbound messages a connection may have pending before it is
+	// considered a slow consumer and disconnected
+	WsSendQueue = 256
+	// WsWriteWait bounds a single write, including pings and the close frame
+	WsWriteWait = 10 * time.Second
+	// WsPongWait is how long a connection may stay silent, pongs included, before it is dropped
+	WsPongWait = 60 * time.Second
+	// WsPingPeriod must be shorter than WsPongWait so a healthy client always answers in time
+	WsPingPeriod = WsPongWait * 9 / 10
+	// WsMaxMessageSize bounds inbound messages
+	WsMaxMessageSize int64 = 64 * 1024
+)
+
+// ConfigureWebsocket replaces the connection settings above. It must run before the first
+// connection is accepted.
+func ConfigureWebsocket(sendQueue int, writeWait, pongWait time.Duration, maxMessageSize int64) {
+	WsSendQueue = sendQueue
+	WsWriteWait = writeWait
+	WsPongWait = pongWait
+	WsPingPeriod = pongWait * 9 / 10
+	WsMaxMessageSize = maxMessageSize
+}
+
+// clients holds every open connection so shutdown can close them with a close frame
+var (
+	clients   = map[*WsClient]bool{}
+	clientsMu sync.Mutex
+	clientsWg sync.WaitGroup
+)
+
+var (
+	ErrWsSlowConsumer = errors.New("websocket send queue full, client disconnected")
+	ErrWsClosed       = errors.New("websocket connection closed")
+)
+
+type wsFrame struct {
+	messageType int
+	data        []byte
+}
+
+// WsClient owns the write side of a websocket connection. Messages are queued and written by one
+// goroutine per connection, so feeds never block on a slow client and never write concurrently; a
+// client whose queue fills up is disconnected instead of having messages dropped. The writer also
+// pings every WsPingPeriod and the read deadline is pushed out by each pong, so dead peers are
+// noticed by the handler's read loop.
+//
+// Only the handler's read loop may call Conn.ReadMessage; everything else goes through the client.
+type WsClient struct {
+	Conn *websocket.Conn
+
+	send      chan wsFrame
+	done      chan struct{}
+	mu        sync.Mutex // guards closed and closeCode against enqueues
+	closed    bool
+	closeCode int
+	closeText string
+}
+
+func NewWsClient(conn *websocket.Conn) *WsClient {
+	client := &WsClient{
+		Conn: conn,
+		send: make(chan wsFrame, WsSendQueue),
+		done: make(chan struct{}),
+	}
+	conn.SetReadLimit(WsMaxMessageSize)
+	conn.SetReadDeadline(time.Now().Add(WsPongWait))
+	conn.SetPongHandler(func(string) error {
+		return conn.SetReadDeadline(time.Now().Add(WsPongWait))
+	})
+	metrics.WsConnections.Inc()
+	clientsMu.Lock()
+	clients[client] = true
+	clientsWg.Add(1)
+	clientsMu.Unlock()
+	go client.writeLoop()
+	return client
+}
+
+// WriteMessage queues a message for the connection. It never blocks: if the queue is full the
+// client is disconnected and ErrWsSlowConsumer returned, so the caller drops the subscriber.
+func (client *WsClient) WriteMessage(messageType int, data []byte) error {
+	client.mu.Lock()
+	defer client.mu.Unlock()
+	if client.closed {
+		return ErrWsClosed
+	}
+	select {
+	case client.send <- wsFrame{messageType: messageType, data: data}:
+		return nil
+	default:
+		metrics.DroppedMessages.WithLabelValues("websocket").Inc()
+		log.Printf("**Websocket**: %s not keeping up with %d queued messages, disconnecting", client.Conn.RemoteAddr(), WsSendQueue)
+		client.closeLocked(websocket.CloseTryAgainLater, "slow consumer")
+		return ErrWsSlowConsumer
+	}
+}
+
+// QueueFree is how many more messages can be queued before the client counts as a slow consumer
+func (client *WsClient) QueueFree() int {
+	return cap(client.send) - len(client.send)
+}
+
+func (client *WsClient) WriteJSON(v any) error {
+	encoded, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	return client.WriteMessage(websocket.TextMessage, encoded)
+}
+
+// Close flushes what is already queued, sends a close frame and closes the connection. It is safe
+// to call more than once and from any goroutine.
+func (client *WsClient) Close() error {
+	client.mu.Lock()
+	defer client.mu.Unlock()
+	client.closeLocked(websocket.CloseNormalClosure, "")
+	return nil
+}
+
+// CloseAllClients sends every open connection what is queued for it and a going-away close frame,
+// then waits until they are all closed or ctx ends
+func CloseAllClients(ctx context.Context) error {
+	clientsMu.Lock()
+	open := make([]*WsClient, 0, len(clients))
+	for client := range clients {
+		open = append(open, client)
+	}
+	clientsMu.Unlock()
+	for _, client := range open {
+		client.mu.Lock()
+		client.closeLocked(websocket.CloseGoingAway, "server shutting down")
+		client.mu.Unlock()
+	}
+
+	closed := make(chan struct{})
+	go func() {
+		clientsWg.Wait()
+		close(closed)
+	}()
+	select {
+	case <-closed:
+		log.Printf("**Websocket**: closed %d connections", len(open))
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (client *WsClient) closeLocked(code int, text string) {
+	if client.closed {
+		return
+	}
+	client.closed = true
+	client.closeCode, client.closeText = code, text
+	close(client.done)
+}
+
+// Done is closed once the client starts shutting down
+func (client *WsClient) Done() <-chan struct{} {
+	return client.done
+}
+
+func (client *WsClient) writeLoop() {
+	ping := time.NewTicker(WsPingPeriod)
+	defer func() {
+		ping.Stop()
+		client.Conn.Close()
+		metrics.WsConnections.Dec()
+		clientsMu.Lock()
+		delete(clients, client)
+		clientsMu.Unlock()
+		clientsWg.Done()
+	}()
+
+	write := func(frame wsFrame) bool {
+		client.Conn.SetWriteDeadline(time.Now().Add(WsWriteWait))
+		if err := client.Conn.WriteMessage(frame.messageType, frame.data); err != nil {
+			client.fail()
+			return false
+		}
+		return true
+	}
+
+	for {
+		select {
+		case frame := <-client.send:
+			if !write(frame) {
+				return
+			}
+		case <-ping.C:
+			if err := client.Conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(WsWriteWait)); err != nil {
+				client.fail()
+				return
+			}
+		case <-client.done:
+			client.mu.Lock()
+			code, text := client.closeCode, client.closeText
+			client.mu.Unlock()
+			// A slow consumer gets its close frame right away; anyone else gets what was queued first
+			if code == websocket.CloseNormalClosure || code == websocket.CloseGoingAway {
+				for flushing := true; flushing; {
+					select {
+					case frame := <-client.send:
+						if !write(frame) {
+							return
+						}
+					default:
+						flushing = false
+					}
+				}
+			}
+			client.Conn.WriteControl(websocket.CloseMessage, websocket.FormatCloseMessage(code, text), time.Now().Add(WsWriteWait))
+			return
+		}
+	}
+}
+
+// fail marks the client closed after a write error, without a close frame since the peer is gone
+func (client *WsClient) fail() {
+	client.mu.Lock()
+	defer client.mu.Unlock()
+	if !client.closed {
+		client.closeLocked(websocket.CloseAbnormalClosure, "")
+	}
+}
+
+// Channel returns a subscriber for one channel of a multiplexed connection. Every message a feed
+// writes to it reaches the client as {"channel": name, "data": <message>}.
+func (client *WsClient) Channel(name string) WsSubscriber {
+	encodedName, _ := json.Marshal(name)
+	return &wsChannel{
+		client: client,
+		prefix: append(append([]byte(`{"channel":`), encodedName...), []byte(`,"data":`)...),
+	}
+}
+
+type wsChannel struct {
+	client *WsClient
+	prefix []byte
+}
+
+func (c *wsChannel) WriteMessage(messageType int, data []byte) error {
+	envelope := make([]byte, 0, len(c.prefix)+len(data)+1)
+	envelope = append(envelope, c.prefix...)
+	envelope = append(envelope, data...)
+	envelope = append(envelope, '}')
+	return c.client.WriteMessage(messageType, envelope)
+}
+
+func (c *wsChannel) QueueFree() int {
+	return c.client.QueueFree()
+}
+
+// Close closes the whole connection: a failed write means the socket is gone for every channel
+func (c *wsChannel) Close() error {
+	return c.client.Close()
+}
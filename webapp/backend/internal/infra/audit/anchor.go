@@ -0,0 +1,110 @@
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// The hash chain alone only shows that records were not changed by someone who left the hashes
+// alone: whoever can write the file can rewrite it and recompute every hash. Anchors close that
+// gap. Each is the operator's signature over the head of the chain, which a rewrite cannot
+// reproduce, so changing an anchored record breaks every anchor after it. Records after the last
+// anchor are only covered by the chain, or by a head the verifier was given.
+
+// AnchorData is the data of a KindAnchor record
+type AnchorData struct {
+	Seq       uint64         `json:"seq"`
+	Hash      string         `json:"hash"`
+	Signer    common.Address `json:"signer"`
+	Signature hexutil.Bytes  `json:"signature"` // EIP-191 personal signature over AnchorText
+}
+
+// AnchorText is what the operator signs for the record seq with hash
+func AnchorText(seq uint64, hash string) []byte {
+	return []byte(fmt.Sprintf("engine audit log head %d %s", seq, hash))
+}
+
+// TextSigner signs EIP-191 personal messages with V as 0 or 1. eth.Signer is one.
+type TextSigner interface {
+	Address() common.Address
+	SignText(text []byte) ([]byte, error)
+}
+
+// Anchor signs the current head and appends the signature as a KindAnchor record. A head that is
+// already anchored is left alone.
+func (auditLog *Log) Anchor(signer TextSigner) error {
+	auditLog.mu.Lock()
+	seq, hash, anchored := auditLog.seq, auditLog.lastHash, auditLog.anchoredSeq
+	auditLog.mu.Unlock()
+	if seq == 0 || seq == anchored {
+		return nil
+	}
+	signature, err := signer.SignText(AnchorText(seq, hash))
+	if err != nil {
+		return fmt.Errorf("failed to sign audit log head %d: %w", seq, err)
+	}
+	anchorSeq, err := auditLog.append(KindAnchor, AnchorData{Seq: seq, Hash: hash, Signer: signer.Address(), Signature: signature})
+	if err != nil {
+		return err
+	}
+	auditLog.mu.Lock()
+	auditLog.anchoredSeq = max(auditLog.anchoredSeq, anchorSeq)
+	auditLog.mu.Unlock()
+	return nil
+}
+
+// StartAnchoring anchors the head every interval until ctx ends
+func (auditLog *Log) StartAnchoring(ctx context.Context, interval time.Duration, signer TextSigner) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if err := auditLog.Anchor(signer); err != nil {
+					log.Printf("**Audit Error**: %v", err)
+				}
+			}
+		}
+	}()
+}
+
+// verifyAnchor checks that an anchor signs one of the unanchored records before it, and that the
+// signature is signer's (anyone's, for a zero signer)
+func verifyAnchor(entry *Entry, unanchored map[uint64]string, signer common.Address) error {
+	var anchor AnchorData
+	if err := json.Unmarshal(entry.Data, &anchor); err != nil {
+		return fmt.Errorf("unreadable anchor: %w", err)
+	}
+	hash, exists := unanchored[anchor.Seq]
+	if !exists {
+		return fmt.Errorf("anchor names seq %d, which is not a record since the previous anchor", anchor.Seq)
+	}
+	if hash != anchor.Hash {
+		return fmt.Errorf("anchor signs hash %s for seq %d, the record has %s", anchor.Hash, anchor.Seq, hash)
+	}
+	if len(anchor.Signature) != crypto.SignatureLength {
+		return fmt.Errorf("anchor signature is %d bytes", len(anchor.Signature))
+	}
+	pub, err := crypto.SigToPub(accounts.TextHash(AnchorText(anchor.Seq, anchor.Hash)), anchor.Signature)
+	if err != nil {
+		return fmt.Errorf("anchor signature does not recover: %w", err)
+	}
+	if recovered := crypto.PubkeyToAddress(*pub); recovered != anchor.Signer {
+		return fmt.Errorf("anchor signed by %s, claims %s", recovered.Hex(), anchor.Signer.Hex())
+	}
+	if signer != (common.Address{}) && anchor.Signer != signer {
+		return fmt.Errorf("anchor signed by %s, expected %s", anchor.Signer.Hex(), signer.Hex())
+	}
+	return nil
+}
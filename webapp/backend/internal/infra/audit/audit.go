@@ -0,0 +1,294 @@
+package audit
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"dexbe/internal/infra/metrics"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// Entry kinds
+const (
+	KindOrderAccepted        = "order.accepted"
+	KindOrderStatus          = "order.status"
+	KindMatch                = "match"
+	KindMatchSkipped         = "match.skipped"
+	KindRing                 = "ring"
+	KindRingSkipped          = "ring.skipped"
+	KindSettlement           = "settlement"
+	KindConditionalStored    = "conditional.stored"
+	KindConditionalTriggered = "conditional.triggered"
+	KindConditionalRemoved   = "conditional.removed"
+	KindAdmin                = "admin"
+	KindAnchor               = "anchor"
+)
+
+// genesisHash is the PrevHash of the first record
+var genesisHash = hex.EncodeToString(make([]byte, sha256.Size))
+
+// Entry is one engine decision. Hash covers every other field, PrevHash included, so changing,
+// dropping or reordering a record breaks every hash after it.
+type Entry struct {
+	Seq      uint64          `json:"seq"`
+	Time     time.Time       `json:"time"`
+	Kind     string          `json:"kind"`
+	Data     json.RawMessage `json:"data"`
+	PrevHash string          `json:"prevHash"`
+	Hash     string          `json:"hash"`
+}
+
+func (entry *Entry) computeHash() (string, error) {
+	unsigned := *entry
+	unsigned.Hash = ""
+	encoded, err := json.Marshal(unsigned)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(encoded)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// Log appends hash-chained records as JSON lines to a file that is only ever appended to
+type Log struct {
+	file        *os.File
+	size        int64 // bytes of complete records, what a failed write is cut back to
+	seq         uint64
+	lastHash    string
+	anchoredSeq uint64 // seq of the latest anchor record
+	mu          sync.Mutex
+}
+
+var engineLog *Log
+
+// Open verifies the chain already in path, creating the file if needed, and makes it the log that
+// Record appends to. A final line left incomplete by a crash mid-write is cut off first; any other
+// break in the chain is refused rather than extended.
+func Open(path string) (*Log, error) {
+	if err := dropTornTail(path); err != nil {
+		return nil, fmt.Errorf("failed to repair audit log %s: %w", path, err)
+	}
+	var verified Verified
+	if existing, err := os.Open(path); err == nil {
+		verified, err = Verify(existing, VerifyOptions{})
+		existing.Close()
+		if err != nil {
+			return nil, fmt.Errorf("audit log %s does not verify: %w", path, err)
+		}
+	} else if !errors.Is(err, os.ErrNotExist) {
+		return nil, err
+	}
+
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open audit log %s: %w", path, err)
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return nil, err
+	}
+	engineLog = &Log{file: file, size: info.Size(), lastHash: genesisHash}
+	if verified.Last != nil {
+		engineLog.seq, engineLog.lastHash = verified.Last.Seq, verified.Last.Hash
+	}
+	if verified.LastAnchor != nil {
+		engineLog.anchoredSeq = verified.LastAnchor.Seq
+	}
+	log.Printf("Engine audit log %s at seq %d", path, engineLog.seq)
+	return engineLog, nil
+}
+
+// dropTornTail cuts an unterminated final line off path. Append writes a record and its newline
+// in one call, so only a crash mid-write leaves one.
+func dropTornTail(path string) error {
+	file, err := os.OpenFile(path, os.O_RDWR, 0)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	info, err := file.Stat()
+	if err != nil {
+		return err
+	}
+	size := info.Size()
+	end := size
+	chunk := make([]byte, 4096)
+	for end > 0 {
+		start := max(end-int64(len(chunk)), 0)
+		n, err := file.ReadAt(chunk[:end-start], start)
+		if err != nil && !errors.Is(err, io.EOF) {
+			return err
+		}
+		if i := bytes.LastIndexByte(chunk[:n], '\n'); i >= 0 {
+			end = start + int64(i) + 1
+			break
+		}
+		end = start
+	}
+	if end == size {
+		return nil
+	}
+	log.Printf("**Audit Warning**: %s ends with a torn record of %d bytes, cutting it off", path, size-end)
+	if err := file.Truncate(end); err != nil {
+		return err
+	}
+	return file.Sync()
+}
+
+// Record appends a decision of kind with data to the engine audit log. It does nothing until Open.
+func Record(kind string, data any) {
+	if engineLog != nil {
+		engineLog.Append(kind, data)
+	}
+}
+
+// Append adds a record of kind with data. A record that cannot be written is counted in
+// metrics.AuditWriteFailures and reported, and the log stays as it was before it.
+func (auditLog *Log) Append(kind string, data any) error {
+	_, err := auditLog.append(kind, data)
+	return err
+}
+
+func (auditLog *Log) append(kind string, data any) (uint64, error) {
+	fail := func(err error) (uint64, error) {
+		metrics.AuditWriteFailures.Inc()
+		log.Printf("**Audit Error**: %s record lost: %v", kind, err)
+		return 0, err
+	}
+	encoded, err := json.Marshal(data)
+	if err != nil {
+		return fail(fmt.Errorf("failed to encode: %w", err))
+	}
+
+	auditLog.mu.Lock()
+	defer auditLog.mu.Unlock()
+
+	entry := Entry{
+		Seq:      auditLog.seq + 1,
+		Time:     time.Now().UTC(),
+		Kind:     kind,
+		Data:     encoded,
+		PrevHash: auditLog.lastHash,
+	}
+	if entry.Hash, err = entry.computeHash(); err != nil {
+		return fail(fmt.Errorf("failed to hash: %w", err))
+	}
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return fail(fmt.Errorf("failed to encode: %w", err))
+	}
+	if _, err := auditLog.file.Write(append(line, '\n')); err != nil {
+		// Cut a partial write back off so the next record does not follow a torn line
+		if truncErr := auditLog.file.Truncate(auditLog.size); truncErr != nil {
+			err = errors.Join(err, truncErr)
+		}
+		return fail(fmt.Errorf("failed to write: %w", err))
+	}
+	auditLog.size += int64(len(line)) + 1
+	auditLog.seq, auditLog.lastHash = entry.Seq, entry.Hash
+	return entry.Seq, nil
+}
+
+func (auditLog *Log) Close() error {
+	auditLog.mu.Lock()
+	defer auditLog.mu.Unlock()
+	if err := auditLog.file.Sync(); err != nil {
+		auditLog.file.Close()
+		return err
+	}
+	return auditLog.file.Close()
+}
+
+// VerifyOptions adds checks that need something from outside the log
+type VerifyOptions struct {
+	Signer common.Address // every anchor must be signed by it; zero accepts any valid signature
+	Head   *Head          // a head recorded elsewhere, which the log must still contain
+}
+
+// Head names one record of the chain
+type Head struct {
+	Seq  uint64
+	Hash string
+}
+
+// ParseHead reads a head written as seq:hash
+func ParseHead(value string) (*Head, error) {
+	seqStr, hash, ok := strings.Cut(value, ":")
+	seq, err := strconv.ParseUint(seqStr, 10, 64)
+	if !ok || err != nil || seq == 0 || len(hash) != 2*sha256.Size {
+		return nil, fmt.Errorf("head %q: expected seq:hash", value)
+	}
+	return &Head{Seq: seq, Hash: strings.ToLower(hash)}, nil
+}
+
+// Verified is what Verify read before it stopped
+type Verified struct {
+	Last       *Entry // nil for an empty log
+	LastAnchor *Entry // nil when no record is anchored
+}
+
+// Verify walks a log from its first record and checks that seqs follow each other, that every
+// record points at the hash of the one before, that every hash matches its record and that every
+// anchor carries a valid signature over a head since the previous anchor.
+func Verify(r io.Reader, opts VerifyOptions) (Verified, error) {
+	var verified Verified
+	expectedPrev := genesisHash
+	unanchored := map[uint64]string{} // hashes of the records after the latest anchor
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		var entry Entry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			return verified, fmt.Errorf("line %d: unreadable record: %w", line, err)
+		}
+		if entry.Seq != uint64(line) {
+			return verified, fmt.Errorf("line %d: seq %d, expected %d", line, entry.Seq, line)
+		}
+		if entry.PrevHash != expectedPrev {
+			return verified, fmt.Errorf("seq %d: prevHash %s does not match previous hash %s", entry.Seq, entry.PrevHash, expectedPrev)
+		}
+		hash, err := entry.computeHash()
+		if err != nil {
+			return verified, fmt.Errorf("seq %d: %w", entry.Seq, err)
+		}
+		if hash != entry.Hash {
+			return verified, fmt.Errorf("seq %d: hash %s does not match contents (%s)", entry.Seq, entry.Hash, hash)
+		}
+		if opts.Head != nil && entry.Seq == opts.Head.Seq && entry.Hash != opts.Head.Hash {
+			return verified, fmt.Errorf("seq %d: hash %s, expected head %s", entry.Seq, entry.Hash, opts.Head.Hash)
+		}
+		if entry.Kind == KindAnchor {
+			if err := verifyAnchor(&entry, unanchored, opts.Signer); err != nil {
+				return verified, fmt.Errorf("seq %d: %w", entry.Seq, err)
+			}
+			clear(unanchored)
+			verified.LastAnchor = &entry
+		}
+		unanchored[entry.Seq] = entry.Hash
+		expectedPrev = entry.Hash
+		verified.Last = &entry
+	}
+	if err := scanner.Err(); err != nil {
+		return verified, err
+	}
+	if opts.Head != nil && (verified.Last == nil || verified.Last.Seq < opts.Head.Seq) {
+		return verified, fmt.Errorf("log ends before expected head seq %d", opts.Head.Seq)
+	}
+	return verified, nil
+}
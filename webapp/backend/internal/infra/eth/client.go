@@ -2,45 +2,45 @@ package eth
 
 import (
 	"context"
-	"crypto/ecdsa"
 	"fmt"
 	"log"
 	"math/big"
 
 	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
-	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/ethclient"
 )
 
 type EthClient struct {
 	Client       *ethclient.Client
-	privateKey   *ecdsa.PrivateKey
 	AuthTransact *bind.TransactOpts
 	AuthCall     *bind.CallOpts
 }
 
-func GetEthClient(chainId, url, privateKeyHex string) *EthClient {
+func GetEthClient(chainId, url string, signer Signer) *EthClient {
 	chainID, _ := stringToBigInt(chainId)
 	client, err := ethclient.Dial(url)
 	if err != nil {
 		log.Fatal(err)
 	}
-	privateKey, err := crypto.HexToECDSA(privateKeyHex[2:])
-	if err != nil {
-		log.Fatalf("ERROR HexToECDSA: %+v", err)
-	}
-	auth, err := bind.NewKeyedTransactorWithChainID(privateKey, chainID)
-	if err != nil {
-		log.Fatal(err)
+	auth := &bind.TransactOpts{
+		From: signer.Address(),
+		Signer: func(address common.Address, tx *types.Transaction) (*types.Transaction, error) {
+			if address != signer.Address() {
+				return nil, bind.ErrNotAuthorized
+			}
+			return signer.SignTx(tx, chainID)
+		},
+		Context: context.Background(),
 	}
+	log.Printf("Operator account %s", signer.Address().Hex())
 	callOpts := &bind.CallOpts{
 		Pending: false,
 		Context: context.Background(),
 	}
 	ethClient := &EthClient{
 		Client:       client,
-		privateKey:   privateKey,
 		AuthTransact: auth,
 		AuthCall:     callOpts,
 	}
@@ -71,3 +71,15 @@ func (c *EthClient) CheckTxReceipt(ctx context.Context, tx *types.Transaction, i
 	}
 
 }
+
+// WaitTxSuccess blocks until tx is mined and returns an error if it failed or ctx expired
+func (c *EthClient) WaitTxSuccess(ctx context.Context, tx *types.Transaction) error {
+	receipt, err := bind.WaitMined(ctx, c.Client, tx)
+	if err != nil {
+		return fmt.Errorf("transaction %s not mined: %w", tx.Hash().Hex(), err)
+	}
+	if receipt.Status != 1 {
+		return fmt.Errorf("transaction %s failed on chain (status %d)", tx.Hash().Hex(), receipt.Status)
+	}
+	return nil
+}
@@ -30,6 +30,15 @@ func NewExchangeContract(ethClient *eth.EthClient, contractAddr string) *Exchang
 	}
 }
 
+func (contract *ExchangeContract) Owner() (common.Address, error) {
+	return contract.Exchange.Owner(contract.Client.AuthCall)
+}
+
+// FilledAmtIn is how much of an order's amtIn the exchange has settled so far
+func (contract *ExchangeContract) FilledAmtIn(createdBy common.Address, nonce *big.Int) (*big.Int, error) {
+	return contract.Exchange.FilledOrdersAmtIn(contract.Client.AuthCall, createdBy, nonce)
+}
+
 // splitSignature splits a 65-byte signature into v, r, s components
 func splitSignature(sig []byte) (v uint8, r [32]byte, s [32]byte, err error) {
 	if len(sig) != 65 {
@@ -41,3 +41,7 @@ func (contract *RegistryContract) AddToken(name, symbol, address string) {
 		log.Printf("TOKENREGISTRY CONTRACT ADD TOKEN ERROR: %+v", err)
 	}
 }
+
+func (contract *RegistryContract) Owner() (common.Address, error) {
+	return contract.Registry.Owner(contract.Client.AuthCall)
+}
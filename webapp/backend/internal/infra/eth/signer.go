@@ -0,0 +1,109 @@
+package eth
+
+import (
+	"crypto/ecdsa"
+	"fmt"
+	"math/big"
+	"os"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/accounts/external"
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// Signer signs the operator's transactions. The engine only ever sees the operator address, so
+// the key can live in a keystore file or behind an external signer instead of in this process.
+type Signer interface {
+	Address() common.Address
+	SignTx(tx *types.Transaction, chainID *big.Int) (*types.Transaction, error)
+	// SignText signs text as an EIP-191 personal message, with V as 0 or 1
+	SignText(text []byte) ([]byte, error)
+}
+
+// KeySigner holds a raw private key in memory. It is meant for local development nodes.
+type KeySigner struct {
+	key     *ecdsa.PrivateKey
+	address common.Address
+}
+
+func NewKeySigner(privateKeyHex string) (*KeySigner, error) {
+	key, err := crypto.HexToECDSA(strings.TrimPrefix(privateKeyHex, "0x"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid private key: %w", err)
+	}
+	return &KeySigner{key: key, address: crypto.PubkeyToAddress(key.PublicKey)}, nil
+}
+
+func (signer *KeySigner) Address() common.Address {
+	return signer.address
+}
+
+func (signer *KeySigner) SignTx(tx *types.Transaction, chainID *big.Int) (*types.Transaction, error) {
+	return types.SignTx(tx, types.LatestSignerForChainID(chainID), signer.key)
+}
+
+func (signer *KeySigner) SignText(text []byte) ([]byte, error) {
+	return crypto.Sign(accounts.TextHash(text), signer.key)
+}
+
+// NewKeystoreSigner decrypts a go-ethereum keystore file (as written by geth account new or clef)
+// with the passphrase in passphraseFile. Trailing newlines in the passphrase file are ignored.
+func NewKeystoreSigner(keystorePath, passphraseFile string) (*KeySigner, error) {
+	keyJSON, err := os.ReadFile(keystorePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read keystore: %w", err)
+	}
+	passphrase, err := os.ReadFile(passphraseFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read passphrase file: %w", err)
+	}
+	key, err := keystore.DecryptKey(keyJSON, strings.TrimRight(string(passphrase), "\r\n"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt keystore %s: %w", keystorePath, err)
+	}
+	return &KeySigner{key: key.PrivateKey, address: key.Address}, nil
+}
+
+// ExternalSigner asks a clef-compatible signer, reached over IPC or HTTP, to sign every
+// transaction. The key never enters this process.
+type ExternalSigner struct {
+	remote  *external.ExternalSigner
+	account accounts.Account
+}
+
+// NewExternalSigner connects to endpoint, an IPC path or http(s) URL, and signs as address. A zero
+// address picks the signer's first account.
+func NewExternalSigner(endpoint string, address common.Address) (*ExternalSigner, error) {
+	remote, err := external.NewExternalSigner(endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("failed to reach external signer at %s: %w", endpoint, err)
+	}
+	available := remote.Accounts()
+	if len(available) == 0 {
+		return nil, fmt.Errorf("external signer at %s exposes no accounts", endpoint)
+	}
+	account := available[0]
+	if address != (common.Address{}) {
+		account = accounts.Account{Address: address}
+		if !remote.Contains(account) {
+			return nil, fmt.Errorf("external signer at %s does not hold %s", endpoint, address.Hex())
+		}
+	}
+	return &ExternalSigner{remote: remote, account: account}, nil
+}
+
+func (signer *ExternalSigner) Address() common.Address {
+	return signer.account.Address
+}
+
+func (signer *ExternalSigner) SignTx(tx *types.Transaction, chainID *big.Int) (*types.Transaction, error) {
+	return signer.remote.SignTx(signer.account, tx, chainID)
+}
+
+func (signer *ExternalSigner) SignText(text []byte) ([]byte, error) {
+	return signer.remote.SignText(signer.account, text)
+}
@@ -3,9 +3,12 @@ package token
 import (
 	token "dexbe/abi/token"
 	"dexbe/internal/infra/eth"
+	"fmt"
 	"log"
+	"math/big"
 
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
 )
 
 type TokenContract struct {
@@ -39,3 +42,65 @@ func (contract *TokenContract) GetTokenDetails() map[string]any {
 	details["symbol"] = symbol
 	return details
 }
+
+func (contract *TokenContract) BalanceOf(owner common.Address) (*big.Int, error) {
+	balance, err := contract.Token.BalanceOf(contract.Client.AuthCall, owner)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query balanceOf %s: %w", owner.Hex(), err)
+	}
+	return balance, nil
+}
+
+func (contract *TokenContract) Allowance(owner, spender common.Address) (*big.Int, error) {
+	allowance, err := contract.Token.Allowance(contract.Client.AuthCall, owner, spender)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query allowance %s -> %s: %w", owner.Hex(), spender.Hex(), err)
+	}
+	return allowance, nil
+}
+
+func (contract *TokenContract) Nonces(owner common.Address) (*big.Int, error) {
+	nonce, err := contract.Token.Nonces(contract.Client.AuthCall, owner)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query permit nonce for %s: %w", owner.Hex(), err)
+	}
+	return nonce, nil
+}
+
+// Permit submits a signed EIP-2612 approval on behalf of owner, paid for by the operator
+func (contract *TokenContract) Permit(owner, spender common.Address, value, deadline *big.Int, sig []byte) (*types.Transaction, error) {
+	v, r, s, err := splitSignature(sig)
+	if err != nil {
+		return nil, err
+	}
+	tx, err := contract.Token.Permit(contract.Client.AuthTransact, owner, spender, value, deadline, v, r, s)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send permit transaction: %w", err)
+	}
+	log.Printf("Permit transaction submitted. Owner: %s, Hash: %s", owner.Hex()[:10], tx.Hash().Hex())
+	return tx, nil
+}
+
+// splitSignature splits a 65-byte signature into v, r, s components
+func splitSignature(sig []byte) (v uint8, r [32]byte, s [32]byte, err error) {
+	if len(sig) != 65 {
+		err = fmt.Errorf("invalid signature length: expected 65, got %d", len(sig))
+		return
+	}
+
+	copy(r[:], sig[0:32])
+	copy(s[:], sig[32:64])
+	v = sig[64]
+
+	// Adjust v if necessary (some libraries return 0/1 instead of 27/28)
+	if v < 27 {
+		v += 27
+	}
+
+	if v != 27 && v != 28 {
+		err = fmt.Errorf("invalid signature v value: %d", v)
+		return
+	}
+
+	return
+}
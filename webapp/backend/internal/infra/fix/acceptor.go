@@ -0,0 +1,535 @@
+package fix
+
+import (
+	"bufio"
+	"context"
+	"crypto/subtle"
+	"dexbe/internal/domains/order"
+	"dexbe/internal/domains/orderbook"
+	"dexbe/internal/infra/api"
+	"dexbe/internal/infra/api/controllers"
+	"errors"
+	"fmt"
+	"log"
+	"math/big"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// SessionConfig binds a counterparty's SenderCompID to the only address it may trade for. Orders
+// and cancels still carry that address's own signatures; the binding keeps a session from acting
+// for anyone else and decides whose execution reports it receives.
+type SessionConfig struct {
+	CompID   string
+	Signer   common.Address
+	Password string // checked against Password (554) on Logon
+}
+
+// ParseSessions reads a spec like "ACME=0xabc...:secret,BETA=0xdef...:other" into session configs
+// keyed by SenderCompID. Every session needs a password.
+func ParseSessions(spec string) (map[string]SessionConfig, error) {
+	sessions := make(map[string]SessionConfig)
+	for _, entry := range strings.Split(spec, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		compID, value, ok := strings.Cut(entry, "=")
+		if !ok || compID == "" {
+			return nil, fmt.Errorf("FIX session %q: expected COMPID=address:password", entry)
+		}
+		addr, password, _ := strings.Cut(value, ":")
+		if !common.IsHexAddress(addr) {
+			return nil, fmt.Errorf("FIX session %q: invalid signer address", entry)
+		}
+		if password == "" {
+			return nil, fmt.Errorf("FIX session %q: password required", entry)
+		}
+		if _, exists := sessions[compID]; exists {
+			return nil, fmt.Errorf("FIX session %q: duplicate SenderCompID", entry)
+		}
+		sessions[compID] = SessionConfig{CompID: compID, Signer: common.HexToAddress(addr), Password: password}
+	}
+	return sessions, nil
+}
+
+// OrderEntry admits signed orders and cancels the same way the REST API does
+type OrderEntry interface {
+	SubmitOrder(o *order.Order, source string) error
+	SubmitCancel(createdBy common.Address, nonce, limitPrice *big.Int, symbolIn, symbolOut string, signature []byte) error
+}
+
+// Acceptor is a FIX 4.4 order entry gateway. NewOrderSingle, OrderCancelRequest and
+// OrderStatusRequest map onto the order book; ExecutionReports follow from the signer's order
+// events.
+//
+// NewOrderSingle must carry the signed order in the custom tags 5002-5007 (symbolIn, symbolOut,
+// amtIn, amtOut, nonce, signature; 5001 createdBy is optional and must match the session's
+// signer). Symbol (55) is the book as BASE/QUOTE and Side (54) must agree with the signed tokens:
+// Sell gives the base, Buy gives the quote. Quantities are in base token units and prices in
+// quote per base; OrderQty (38) and Price (44) are informational. OrderCancelRequest must carry the
+// signed cancel in 5008. OrderID (37) is the order's nonce.
+//
+// FIX 4.4 has no transport encryption and the Logon password travels in the clear, so the
+// listener must only be reachable through a TLS terminating proxy or a private network.
+type Acceptor struct {
+	CompID         string
+	OrderBookStore *orderbook.OrderBookStore
+	Entry          OrderEntry
+	configs        map[string]SessionConfig
+	states         map[string]*sessionState
+	mu             sync.Mutex
+	execSeq        uint64
+	listener       net.Listener
+	live           map[*session]bool
+	serving        sync.WaitGroup
+}
+
+func NewAcceptor(compID string, configs map[string]SessionConfig, store *orderbook.OrderBookStore, entry OrderEntry) *Acceptor {
+	return &Acceptor{
+		CompID:         compID,
+		OrderBookStore: store,
+		Entry:          entry,
+		configs:        configs,
+		states:         make(map[string]*sessionState),
+		live:           make(map[*session]bool),
+	}
+}
+
+// ListenAndServe accepts FIX connections on addr until the listener fails or Shutdown is called
+func (acceptor *Acceptor) ListenAndServe(addr string) error {
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return err
+	}
+	acceptor.mu.Lock()
+	acceptor.listener = listener
+	acceptor.mu.Unlock()
+	log.Printf("FIX acceptor %s listening on %s for %d sessions", acceptor.CompID, addr, len(acceptor.configs))
+	for {
+		conn, err := listener.Accept()
+		if errors.Is(err, net.ErrClosed) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		acceptor.serving.Add(1)
+		go func() {
+			defer acceptor.serving.Done()
+			acceptor.serve(conn)
+		}()
+	}
+}
+
+// Shutdown stops accepting connections and logs out every session, waiting for their Logouts to
+// be written until ctx ends, after which the remaining connections are dropped
+func (acceptor *Acceptor) Shutdown(ctx context.Context) error {
+	acceptor.mu.Lock()
+	if acceptor.listener != nil {
+		acceptor.listener.Close()
+	}
+	sessions := make([]*session, 0, len(acceptor.live))
+	for s := range acceptor.live {
+		sessions = append(sessions, s)
+	}
+	acceptor.mu.Unlock()
+	for _, s := range sessions {
+		s.logout("server shutting down")
+	}
+
+	done := make(chan struct{})
+	go func() {
+		acceptor.serving.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		for _, s := range sessions {
+			s.conn.Close()
+		}
+		return ctx.Err()
+	}
+}
+
+// nextExecID returns an ExecID unique across restarts
+func (acceptor *Acceptor) nextExecID() string {
+	acceptor.mu.Lock()
+	defer acceptor.mu.Unlock()
+	acceptor.execSeq++
+	return api.StreamEpoch + "-" + strconv.FormatUint(acceptor.execSeq, 10)
+}
+
+func (acceptor *Acceptor) serve(conn net.Conn) {
+	defer conn.Close()
+	r := bufio.NewReader(conn)
+	conn.SetReadDeadline(time.Now().Add(logonTimeout))
+	logon, err := ReadMessage(r)
+	if err != nil || logon.Type() != MsgLogon {
+		log.Printf("**FIX**: %s did not log on: %v", conn.RemoteAddr(), err)
+		return
+	}
+
+	s, err := acceptor.logon(conn, logon)
+	if err != nil {
+		log.Printf("**FIX**: logon from %s refused: %v", conn.RemoteAddr(), err)
+		reply := NewMessage(MsgLogout).
+			Set(TagSenderCompID, acceptor.CompID).
+			Set(TagTargetCompID, logon.Get(TagSenderCompID)).
+			Set(TagMsgSeqNum, "1").
+			Set(TagSendingTime, sendingTime(time.Now())).
+			Set(TagText, err.Error())
+		conn.SetWriteDeadline(time.Now().Add(writeWait))
+		conn.Write(reply.Encode())
+		return
+	}
+	acceptor.mu.Lock()
+	acceptor.live[s] = true
+	acceptor.mu.Unlock()
+	defer func() {
+		acceptor.mu.Lock()
+		delete(acceptor.live, s)
+		acceptor.mu.Unlock()
+		api.RemoveSubscriber(s.config.Signer, s.sink())
+		s.close()
+		s.state.mu.Lock()
+		s.state.active = false
+		s.state.mu.Unlock()
+		log.Printf("**FIX**: %s logged out", s.config.CompID)
+	}()
+
+	go s.writeLoop()
+	acceptor.start(s, logon)
+	s.readLoop(r)
+}
+
+// logon checks a Logon against the session configs and claims the session's state
+func (acceptor *Acceptor) logon(conn net.Conn, logon *Message) (*session, error) {
+	config, exists := acceptor.configs[logon.Get(TagSenderCompID)]
+	if !exists {
+		return nil, fmt.Errorf("unknown SenderCompID %q", logon.Get(TagSenderCompID))
+	}
+	if logon.Get(TagTargetCompID) != acceptor.CompID {
+		return nil, fmt.Errorf("TargetCompID must be %s", acceptor.CompID)
+	}
+	if subtle.ConstantTimeCompare([]byte(logon.Get(TagPassword)), []byte(config.Password)) != 1 {
+		return nil, fmt.Errorf("invalid password")
+	}
+	if encrypt := logon.Get(TagEncryptMethod); encrypt != "" && encrypt != "0" {
+		return nil, fmt.Errorf("EncryptMethod must be 0")
+	}
+	heartBtInt, err := logon.GetInt(TagHeartBtInt)
+	if err != nil || heartBtInt < 1 {
+		return nil, fmt.Errorf("HeartBtInt must be a positive number of seconds")
+	}
+	seq, err := logon.GetInt(TagMsgSeqNum)
+	if err != nil {
+		return nil, fmt.Errorf("missing MsgSeqNum")
+	}
+
+	acceptor.mu.Lock()
+	state, exists := acceptor.states[config.CompID]
+	if !exists {
+		state = newSessionState()
+		acceptor.states[config.CompID] = state
+	}
+	acceptor.mu.Unlock()
+
+	state.mu.Lock()
+	defer state.mu.Unlock()
+	if state.active {
+		return nil, fmt.Errorf("%s is already logged on", config.CompID)
+	}
+	if logon.Get(TagResetSeqNumFlag) == "Y" {
+		state.reset()
+	}
+	if seq < state.nextIn {
+		return nil, fmt.Errorf("MsgSeqNum too low, expecting %d but received %d", state.nextIn, seq)
+	}
+	state.active = true
+
+	now := time.Now()
+	s := &session{
+		acceptor:     acceptor,
+		conn:         conn,
+		config:       config,
+		state:        state,
+		heartBtInt:   time.Duration(heartBtInt) * time.Second,
+		send:         make(chan outbound, sendQueue),
+		done:         make(chan struct{}),
+		lastReceived: now,
+		lastSent:     now,
+	}
+	s.events = &eventSink{session: s}
+	return s, nil
+}
+
+// start answers the Logon, catches up on any gap and subscribes the session to its signer's events
+func (acceptor *Acceptor) start(s *session, logon *Message) {
+	reply := NewMessage(MsgLogon).
+		Set(TagEncryptMethod, "0").
+		Set(TagHeartBtInt, logon.Get(TagHeartBtInt))
+	if logon.Get(TagResetSeqNumFlag) == "Y" {
+		reply.Set(TagResetSeqNumFlag, "Y")
+	}
+	s.enqueue(reply, false)
+
+	seq, _ := logon.GetInt(TagMsgSeqNum)
+	s.state.mu.Lock()
+	expected := s.state.nextIn
+	if seq == expected {
+		s.state.nextIn = seq + 1
+	}
+	s.state.mu.Unlock()
+	if seq > expected {
+		s.requestResend(expected, seq)
+	}
+
+	log.Printf("**FIX**: %s logged on for %s", s.config.CompID, s.config.Signer.Hex())
+	s.state.mu.Lock()
+	since, seeded := s.state.eventSeq, s.state.eventsSeeded
+	s.state.eventsSeeded = true
+	s.state.mu.Unlock()
+	if !seeded {
+		since = api.UserSeq(s.config.Signer)
+	}
+	if !api.ResumeSubscriber(s.config.Signer, s.sink(), since, nil) {
+		log.Printf("**FIX**: order events after #%d for %s are gone, reporting from now on", since, s.config.CompID)
+		api.AddSubscriber(s.config.Signer, s.sink())
+	}
+}
+
+func (acceptor *Acceptor) handleApplication(s *session, msg *Message) {
+	switch msg.Type() {
+	case MsgNewOrderSingle:
+		acceptor.newOrderSingle(s, msg)
+	case MsgOrderCancelRequest:
+		acceptor.orderCancelRequest(s, msg)
+	case MsgOrderStatusRequest:
+		acceptor.orderStatusRequest(s, msg)
+	default:
+		s.enqueue(NewMessage(MsgBusinessMessageReject).
+			Set(TagRefSeqNum, msg.Get(TagMsgSeqNum)).
+			Set(TagRefMsgType, msg.Type()).
+			SetInt(TagBusinessRejReason, 3).
+			Set(TagText, "unsupported message type"), false)
+	}
+}
+
+// requireFields rejects msg unless it has every tag, returning whether it did
+func requireFields(s *session, msg *Message, tags ...int) bool {
+	for _, tag := range tags {
+		if msg.Get(tag) == "" {
+			s.reject(msg, 1, fmt.Sprintf("required tag %d missing", tag), tag)
+			return false
+		}
+	}
+	return true
+}
+
+func (acceptor *Acceptor) newOrderSingle(s *session, msg *Message) {
+	if !requireFields(s, msg, TagClOrdID, TagSymbol, TagSide, TagOrdType,
+		TagSignedSymbolIn, TagSignedSymbolOut, TagSignedAmtIn, TagSignedAmtOut, TagSignedNonce, TagOrderSignature) {
+		return
+	}
+	clOrdID := msg.Get(TagClOrdID)
+	rejectOrder := func(text string) {
+		report := NewMessage(MsgExecutionReport).
+			Set(TagOrderID, "NONE").
+			Set(TagClOrdID, clOrdID).
+			Set(TagExecID, acceptor.nextExecID()).
+			Set(TagExecType, "8").
+			Set(TagOrdStatus, "8").
+			SetInt(TagOrdRejReason, 99).
+			Set(TagSymbol, msg.Get(TagSymbol)).
+			Set(TagSide, msg.Get(TagSide)).
+			Set(TagLeavesQty, "0").
+			Set(TagCumQty, "0").
+			Set(TagAvgPx, "0").
+			Set(TagTransactTime, sendingTime(time.Now())).
+			Set(TagText, text)
+		s.enqueue(report, false)
+	}
+
+	if createdBy := msg.Get(TagSignedCreatedBy); createdBy != "" && common.HexToAddress(createdBy) != s.config.Signer {
+		rejectOrder("signed createdBy does not match the session's signer")
+		return
+	}
+	if msg.Get(TagOrdType) != "2" {
+		rejectOrder("only limit orders (OrdType 2) are supported")
+		return
+	}
+	symbolIn, symbolOut := msg.Get(TagSignedSymbolIn), msg.Get(TagSignedSymbolOut)
+	base, quote := orderbook.GetPairKey(symbolIn, symbolOut)
+	if msg.Get(TagSymbol) != base+"/"+quote {
+		rejectOrder(fmt.Sprintf("Symbol must be %s/%s for the signed tokens", base, quote))
+		return
+	}
+	if side := msg.Get(TagSide); (side == "2") != (symbolIn == base) || (side != "1" && side != "2") {
+		rejectOrder(fmt.Sprintf("Side %s does not match giving %s", side, symbolIn))
+		return
+	}
+	if _, err := hexutil.Decode(msg.Get(TagOrderSignature)); err != nil {
+		rejectOrder("invalid signature")
+		return
+	}
+	nonce, ok := new(big.Int).SetString(msg.Get(TagSignedNonce), 10)
+	if !ok {
+		rejectOrder("invalid nonce")
+		return
+	}
+
+	s.state.mu.Lock()
+	if _, taken := s.state.clOrd[clOrdID]; taken {
+		s.state.mu.Unlock()
+		rejectOrder("duplicate ClOrdID")
+		return
+	}
+	if _, taken := s.state.orders[nonce.String()]; taken {
+		s.state.mu.Unlock()
+		rejectOrder("nonce already used")
+		return
+	}
+	s.state.clOrd[clOrdID] = nonce.String()
+	s.state.orders[nonce.String()] = &orderState{clOrdID: clOrdID, filled: big.NewInt(0)}
+	s.state.mu.Unlock()
+
+	o := order.NewOrder(s.config.Signer.Hex(), symbolIn, symbolOut, msg.Get(TagSignedAmtIn), msg.Get(TagSignedAmtOut),
+		nonce.String(), msg.Get(TagOrderSignature), "", "", int(order.Matching), nil, "")
+	if err := acceptor.Entry.SubmitOrder(o, controller.OrderSourceFIX); err != nil {
+		s.state.mu.Lock()
+		delete(s.state.clOrd, clOrdID)
+		delete(s.state.orders, nonce.String())
+		s.state.mu.Unlock()
+		rejectOrder(err.Error())
+		return
+	}
+	log.Printf("**FIX**: %s entered order %s as %s/%s", s.config.CompID, clOrdID, s.config.Signer.Hex(), nonce.String())
+}
+
+// lookupNonce resolves the order a request refers to by OrigClOrdID, ClOrdID or OrderID
+func (s *session) lookupNonce(msg *Message, clOrdTag int) (string, bool) {
+	s.state.mu.Lock()
+	defer s.state.mu.Unlock()
+	if nonce, exists := s.state.clOrd[msg.Get(clOrdTag)]; exists {
+		return nonce, true
+	}
+	if orderID := msg.Get(TagOrderID); orderID != "" {
+		if _, ok := new(big.Int).SetString(orderID, 10); ok {
+			return orderID, true
+		}
+	}
+	return "", false
+}
+
+// openOrder finds the signer's resting order with nonce
+func (acceptor *Acceptor) openOrder(signer common.Address, nonce string) *order.Order {
+	for _, o := range acceptor.OrderBookStore.GetOrdersByCreator(signer) {
+		if o.Nonce.String() == nonce {
+			return o
+		}
+	}
+	return nil
+}
+
+func (acceptor *Acceptor) orderCancelRequest(s *session, msg *Message) {
+	if !requireFields(s, msg, TagClOrdID, TagOrigClOrdID, TagCancelSignature) {
+		return
+	}
+	cancelReject := func(orderID, ordStatus string, reason int, text string) {
+		s.enqueue(NewMessage(MsgOrderCancelReject).
+			Set(TagOrderID, orderID).
+			Set(TagClOrdID, msg.Get(TagClOrdID)).
+			Set(TagOrigClOrdID, msg.Get(TagOrigClOrdID)).
+			Set(TagOrdStatus, ordStatus).
+			Set(TagCxlRejResponseTo, "1").
+			SetInt(TagCxlRejReason, reason).
+			Set(TagText, text), false)
+	}
+
+	nonce, found := s.lookupNonce(msg, TagOrigClOrdID)
+	if !found {
+		cancelReject("NONE", "8", 1, "unknown order")
+		return
+	}
+	o := acceptor.openOrder(s.config.Signer, nonce)
+	if o == nil {
+		cancelReject(nonce, "8", 1, "order is not open")
+		return
+	}
+	signature, err := hexutil.Decode(msg.Get(TagCancelSignature))
+	if err != nil {
+		cancelReject(nonce, ordStatus(o.ToStringMap()), 99, "invalid cancel signature")
+		return
+	}
+
+	s.state.mu.Lock()
+	state := s.state.orderState(nonce)
+	state.cancelClOrdID = msg.Get(TagClOrdID)
+	s.state.mu.Unlock()
+
+	if err := acceptor.Entry.SubmitCancel(s.config.Signer, o.Nonce, o.LimitPrice, o.SymbolIn, o.SymbolOut, signature); err != nil {
+		s.state.mu.Lock()
+		state.cancelClOrdID = ""
+		s.state.mu.Unlock()
+		cancelReject(nonce, ordStatus(o.ToStringMap()), 99, err.Error())
+	}
+}
+
+func (acceptor *Acceptor) orderStatusRequest(s *session, msg *Message) {
+	if !requireFields(s, msg, TagClOrdID) {
+		return
+	}
+	unknown := NewMessage(MsgExecutionReport).
+		Set(TagOrderID, "NONE").
+		Set(TagClOrdID, msg.Get(TagClOrdID)).
+		Set(TagExecID, acceptor.nextExecID()).
+		Set(TagExecType, "I").
+		Set(TagOrdStatus, "8").
+		SetInt(TagOrdRejReason, 5).
+		Set(TagSymbol, msg.Get(TagSymbol)).
+		Set(TagSide, msg.Get(TagSide)).
+		Set(TagLeavesQty, "0").
+		Set(TagCumQty, "0").
+		Set(TagAvgPx, "0").
+		Set(TagText, "unknown order")
+
+	nonce, found := s.lookupNonce(msg, TagClOrdID)
+	if !found {
+		s.enqueue(unknown, false)
+		return
+	}
+	var data map[string]string
+	if o := acceptor.openOrder(s.config.Signer, nonce); o != nil {
+		data = o.ToStringMap()
+	} else if n, ok := new(big.Int).SetString(nonce, 10); ok {
+		if history := acceptor.OrderBookStore.GetOrderHistory(s.config.Signer, n); len(history) > 0 {
+			data = history[len(history)-1].ToStringMap()
+		}
+	}
+	if data == nil {
+		unknown.Set(TagOrderID, nonce)
+		s.enqueue(unknown, false)
+		return
+	}
+
+	// Looked up without starting a state, which would never be finished for an order already done
+	clOrdID := nonce
+	s.state.mu.Lock()
+	if state, exists := s.state.orders[nonce]; exists {
+		clOrdID = state.clOrdID
+	}
+	s.state.mu.Unlock()
+
+	report := executionReport(data, clOrdID, "I", ordStatus(data))
+	report.Set(TagExecID, acceptor.nextExecID())
+	s.enqueue(report, false)
+}
@@ -0,0 +1,273 @@
+package fix
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+)
+
+const (
+	BeginString = "FIX.4.4"
+	soh         = '\x01'
+
+	// maxBodyLength bounds a single inbound message
+	maxBodyLength = 64 * 1024
+)
+
+// Standard tags used by the gateway
+const (
+	TagAvgPx             = 6
+	TagBeginSeqNo        = 7
+	TagBeginString       = 8
+	TagBodyLength        = 9
+	TagCheckSum          = 10
+	TagClOrdID           = 11
+	TagCumQty            = 14
+	TagEndSeqNo          = 16
+	TagExecID            = 17
+	TagMsgSeqNum         = 34
+	TagMsgType           = 35
+	TagNewSeqNo          = 36
+	TagOrderID           = 37
+	TagOrderQty          = 38
+	TagOrdStatus         = 39
+	TagOrdType           = 40
+	TagOrigClOrdID       = 41
+	TagPossDupFlag       = 43
+	TagPrice             = 44
+	TagRefSeqNum         = 45
+	TagSenderCompID      = 49
+	TagSendingTime       = 52
+	TagSide              = 54
+	TagSymbol            = 55
+	TagTargetCompID      = 56
+	TagText              = 58
+	TagTransactTime      = 60
+	TagLastPx            = 31
+	TagLastQty           = 32
+	TagEncryptMethod     = 98
+	TagCxlRejReason      = 102
+	TagOrdRejReason      = 103
+	TagHeartBtInt        = 108
+	TagTestReqID         = 112
+	TagOrigSendingTime   = 122
+	TagGapFillFlag       = 123
+	TagResetSeqNumFlag   = 141
+	TagExecType          = 150
+	TagLeavesQty         = 151
+	TagRefTagID          = 371
+	TagRefMsgType        = 372
+	TagSessionRejReason  = 373
+	TagCxlRejResponseTo  = 434
+	TagUsername          = 553
+	TagPassword          = 554
+	TagBusinessRejReason = 380
+)
+
+// Custom tags carrying the EIP-712 payload the signer already signed. The standard fields of an
+// order are derived from these, never the other way round, so what is booked is exactly what was
+// signed.
+const (
+	TagSignedCreatedBy   = 5001
+	TagSignedSymbolIn    = 5002
+	TagSignedSymbolOut   = 5003
+	TagSignedAmtIn       = 5004
+	TagSignedAmtOut      = 5005
+	TagSignedNonce       = 5006
+	TagOrderSignature    = 5007
+	TagCancelSignature   = 5008
+	TagSignedLimitPrice  = 5009 // on reports: the book's 1e18-scaled limit price
+	TagOrderTransactions = 5010 // on reports: settlement tx hashes, comma separated
+)
+
+// Message types
+const (
+	MsgHeartbeat             = "0"
+	MsgTestRequest           = "1"
+	MsgResendRequest         = "2"
+	MsgReject                = "3"
+	MsgSequenceReset         = "4"
+	MsgLogout                = "5"
+	MsgExecutionReport       = "8"
+	MsgOrderCancelReject     = "9"
+	MsgLogon                 = "A"
+	MsgNewOrderSingle        = "D"
+	MsgOrderCancelRequest    = "F"
+	MsgOrderStatusRequest    = "H"
+	MsgBusinessMessageReject = "j"
+)
+
+// sendingTimeFormat is UTCTimestamp with milliseconds
+const sendingTimeFormat = "20060102-15:04:05.000"
+
+var ErrGarbled = errors.New("garbled FIX message")
+
+type field struct {
+	tag   int
+	value string
+}
+
+// Message is a FIX message as an ordered list of fields, without BeginString, BodyLength and
+// CheckSum, which are added on encoding
+type Message struct {
+	fields []field
+}
+
+func NewMessage(msgType string) *Message {
+	m := &Message{}
+	m.Set(TagMsgType, msgType)
+	return m
+}
+
+func (m *Message) Type() string {
+	return m.Get(TagMsgType)
+}
+
+// Set replaces the first occurrence of tag, or appends it
+func (m *Message) Set(tag int, value string) *Message {
+	for i := range m.fields {
+		if m.fields[i].tag == tag {
+			m.fields[i].value = value
+			return m
+		}
+	}
+	m.fields = append(m.fields, field{tag: tag, value: value})
+	return m
+}
+
+func (m *Message) SetInt(tag int, value int) *Message {
+	return m.Set(tag, strconv.Itoa(value))
+}
+
+func (m *Message) Has(tag int) bool {
+	for _, f := range m.fields {
+		if f.tag == tag {
+			return true
+		}
+	}
+	return false
+}
+
+func (m *Message) Get(tag int) string {
+	for _, f := range m.fields {
+		if f.tag == tag {
+			return f.value
+		}
+	}
+	return ""
+}
+
+func (m *Message) GetInt(tag int) (int, error) {
+	return strconv.Atoi(m.Get(tag))
+}
+
+// Clone copies the message, so a stored message can be restamped for a resend
+func (m *Message) Clone() *Message {
+	return &Message{fields: append([]field(nil), m.fields...)}
+}
+
+// Encode renders the message with the header fields in standard order and a fresh BodyLength and
+// CheckSum
+func (m *Message) Encode() []byte {
+	var body bytes.Buffer
+	writeField := func(buf *bytes.Buffer, tag int, value string) {
+		buf.WriteString(strconv.Itoa(tag))
+		buf.WriteByte('=')
+		buf.WriteString(value)
+		buf.WriteByte(soh)
+	}
+	// MsgType first, then the rest of the standard header, then the body
+	header := []int{TagMsgType, TagSenderCompID, TagTargetCompID, TagMsgSeqNum, TagPossDupFlag, TagSendingTime, TagOrigSendingTime}
+	isHeader := make(map[int]bool, len(header))
+	for _, tag := range header {
+		isHeader[tag] = true
+		if m.Has(tag) {
+			writeField(&body, tag, m.Get(tag))
+		}
+	}
+	for _, f := range m.fields {
+		if !isHeader[f.tag] {
+			writeField(&body, f.tag, f.value)
+		}
+	}
+
+	var out bytes.Buffer
+	writeField(&out, TagBeginString, BeginString)
+	writeField(&out, TagBodyLength, strconv.Itoa(body.Len()))
+	out.Write(body.Bytes())
+	writeField(&out, TagCheckSum, fmt.Sprintf("%03d", checksum(out.Bytes())))
+	return out.Bytes()
+}
+
+func (m *Message) String() string {
+	return string(bytes.ReplaceAll(m.Encode(), []byte{soh}, []byte{'|'}))
+}
+
+func checksum(data []byte) int {
+	sum := 0
+	for _, b := range data {
+		sum += int(b)
+	}
+	return sum % 256
+}
+
+// ReadMessage reads one message from r. A message whose framing is intact but whose BeginString,
+// BodyLength or CheckSum is wrong returns ErrGarbled, and per the session protocol is ignored
+// rather than ending the session; any other error means the stream is unusable.
+func ReadMessage(r *bufio.Reader) (*Message, error) {
+	begin, err := r.ReadString(soh)
+	if err != nil {
+		return nil, err
+	}
+	if begin != "8="+BeginString+string(soh) {
+		return nil, fmt.Errorf("%w: unexpected BeginString %q", ErrGarbled, begin)
+	}
+	lengthField, err := r.ReadString(soh)
+	if err != nil {
+		return nil, err
+	}
+	if len(lengthField) < 3 || lengthField[:2] != "9=" {
+		return nil, fmt.Errorf("%w: BodyLength must follow BeginString", ErrGarbled)
+	}
+	bodyLength, err := strconv.Atoi(lengthField[2 : len(lengthField)-1])
+	if err != nil || bodyLength <= 0 || bodyLength > maxBodyLength {
+		return nil, fmt.Errorf("%w: invalid BodyLength %q", ErrGarbled, lengthField)
+	}
+	body := make([]byte, bodyLength)
+	if _, err := io.ReadFull(r, body); err != nil {
+		return nil, err
+	}
+	trailer, err := r.ReadString(soh)
+	if err != nil {
+		return nil, err
+	}
+	if len(trailer) != 7 || trailer[:3] != "10=" {
+		return nil, fmt.Errorf("%w: CheckSum must follow the body", ErrGarbled)
+	}
+	want := checksum([]byte(begin + lengthField + string(body)))
+	if got, err := strconv.Atoi(trailer[3:6]); err != nil || got != want {
+		return nil, fmt.Errorf("%w: CheckSum %s, want %03d", ErrGarbled, trailer[3:6], want)
+	}
+
+	m := &Message{}
+	for _, raw := range bytes.Split(bytes.TrimSuffix(body, []byte{soh}), []byte{soh}) {
+		tagStr, value, ok := bytes.Cut(raw, []byte{'='})
+		tag, err := strconv.Atoi(string(tagStr))
+		if !ok || err != nil {
+			return nil, fmt.Errorf("%w: bad field %q", ErrGarbled, raw)
+		}
+		m.fields = append(m.fields, field{tag: tag, value: string(value)})
+	}
+	if m.Type() == "" {
+		return nil, fmt.Errorf("%w: missing MsgType", ErrGarbled)
+	}
+	return m, nil
+}
+
+func sendingTime(t time.Time) string {
+	return t.UTC().Format(sendingTimeFormat)
+}
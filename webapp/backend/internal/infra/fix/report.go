@@ -0,0 +1,244 @@
+package fix
+
+import (
+	"dexbe/internal/domains/orderbook"
+	"encoding/json"
+	"log"
+	"math/big"
+	"time"
+)
+
+// orderState is what a session remembers about one of its signer's orders
+type orderState struct {
+	clOrdID       string
+	cancelClOrdID string   // ClOrdID of a pending OrderCancelRequest
+	filled        *big.Int // cumulative FilledAmtIn already reported
+	done          bool
+}
+
+// orderState returns the state of the order with nonce, starting one for orders entered elsewhere
+// (such as REST), which are reported with their nonce as ClOrdID. Callers hold state.mu.
+func (state *sessionState) orderState(nonce string) *orderState {
+	o, exists := state.orders[nonce]
+	if !exists {
+		o = &orderState{clOrdID: nonce, filled: big.NewInt(0)}
+		state.orders[nonce] = o
+		state.clOrd[nonce] = nonce
+	}
+	return o
+}
+
+// finish marks the order with nonce terminal and forgets the oldest terminal order once more than
+// finishedWindow are held. Callers hold state.mu.
+func (state *sessionState) finish(nonce string, o *orderState) {
+	o.done = true
+	state.finished = append(state.finished, nonce)
+	if len(state.finished) <= finishedWindow {
+		return
+	}
+	oldest := state.finished[0]
+	state.finished = state.finished[1:]
+	if old, exists := state.orders[oldest]; exists && old.done {
+		delete(state.clOrd, old.clOrdID)
+		delete(state.orders, oldest)
+	}
+}
+
+// eventSink receives the signer's order events from the websocket fan-out and turns them into
+// ExecutionReports. WriteMessage is called with the fan-out lock held, so it only queues.
+type eventSink struct {
+	session *session
+}
+
+func (s *session) sink() *eventSink {
+	return s.events
+}
+
+func (sink *eventSink) WriteMessage(messageType int, data []byte) error {
+	s := sink.session
+	var event struct {
+		Event string          `json:"event"`
+		Seq   uint64          `json:"seq"`
+		Data  json.RawMessage `json:"data"`
+	}
+	if err := json.Unmarshal(data, &event); err != nil {
+		log.Printf("**FIX**: unreadable order event for %s: %v", s.config.CompID, err)
+		return nil
+	}
+	var fields map[string]string
+	if err := json.Unmarshal(event.Data, &fields); err != nil {
+		// OrderRemove carries {"nonce": <number>}
+		var removal struct {
+			Nonce *big.Int `json:"nonce"`
+		}
+		if err := json.Unmarshal(event.Data, &removal); err != nil || removal.Nonce == nil {
+			log.Printf("**FIX**: unreadable %s event for %s: %v", event.Event, s.config.CompID, err)
+			return nil
+		}
+		fields = map[string]string{"Nonce": removal.Nonce.String()}
+	}
+
+	s.state.mu.Lock()
+	s.state.eventSeq = event.Seq
+	s.state.mu.Unlock()
+	for _, report := range s.reportsFor(event.Event, fields) {
+		report.Set(TagExecID, s.acceptor.nextExecID())
+		s.enqueue(report, false)
+	}
+	return nil
+}
+
+func (sink *eventSink) Close() error {
+	sink.session.close()
+	return nil
+}
+
+// reportsFor turns one order event into the ExecutionReports it implies, updating what the
+// session has reported for that order
+func (s *session) reportsFor(event string, data map[string]string) []*Message {
+	nonce := data["Nonce"]
+	if nonce == "" {
+		return nil
+	}
+	s.state.mu.Lock()
+	defer s.state.mu.Unlock()
+	state := s.state.orderState(nonce)
+
+	switch event {
+	case "OrderAdd":
+		return []*Message{executionReport(data, state.clOrdID, "0", ordStatus(data))}
+
+	case "OrderRemove":
+		if state.done {
+			return nil
+		}
+		s.state.finish(nonce, state)
+		report := NewMessage(MsgExecutionReport).
+			Set(TagOrderID, nonce).
+			Set(TagExecType, "4").
+			Set(TagOrdStatus, "4").
+			Set(TagLeavesQty, "0").
+			Set(TagCumQty, state.filled.String()).
+			Set(TagAvgPx, "0").
+			Set(TagTransactTime, sendingTime(time.Now()))
+		if state.cancelClOrdID != "" {
+			report.Set(TagClOrdID, state.cancelClOrdID).Set(TagOrigClOrdID, state.clOrdID)
+		} else {
+			report.Set(TagClOrdID, state.clOrdID)
+		}
+		if o := s.acceptor.OrderBookStore.GetOrderHistory(s.config.Signer, bigFromString(nonce)); len(o) > 0 {
+			full := executionReport(o[len(o)-1].ToStringMap(), report.Get(TagClOrdID), "4", "4")
+			full.Set(TagLeavesQty, "0")
+			if state.cancelClOrdID != "" {
+				full.Set(TagOrigClOrdID, state.clOrdID)
+			}
+			return []*Message{full}
+		}
+		return []*Message{report}
+
+	case "TransactionChange":
+		filled := bigFromString(data["FilledAmtIn"])
+		status := ordStatus(data)
+		var reports []*Message
+		if filled.Cmp(state.filled) > 0 {
+			report := executionReport(data, state.clOrdID, "F", status)
+			lastIn := new(big.Int).Sub(filled, state.filled)
+			report.Set(TagLastQty, baseQty(data, lastIn).String())
+			report.Set(TagLastPx, report.Get(TagPrice))
+			state.filled = filled
+			reports = append(reports, report)
+		}
+		if (status == "2" || status == "4") && !state.done {
+			s.state.finish(nonce, state)
+			if status == "4" {
+				reports = append(reports, executionReport(data, state.clOrdID, "4", status))
+			}
+		}
+		return reports
+	}
+	return nil
+}
+
+// executionReport describes an order from its ToStringMap form
+func executionReport(data map[string]string, clOrdID, execType, status string) *Message {
+	base, quote := orderbook.GetPairKey(data["SymbolIn"], data["SymbolOut"])
+	side := "1"
+	if data["SymbolIn"] == base {
+		side = "2"
+	}
+	orderQty := baseQty(data, bigFromString(data["AmtIn"]))
+	cumQty := baseQty(data, bigFromString(data["FilledAmtIn"]))
+	leavesQty := new(big.Int).Sub(orderQty, cumQty)
+	if status == "2" || status == "4" || leavesQty.Sign() < 0 {
+		leavesQty.SetInt64(0)
+	}
+	price := "0"
+	if limit := bigFromString(data["LimitPrice"]); limit.Sign() > 0 {
+		price = orderbook.FormatPrice(limit)
+	}
+
+	report := NewMessage(MsgExecutionReport).
+		Set(TagOrderID, data["Nonce"]).
+		Set(TagClOrdID, clOrdID).
+		Set(TagExecType, execType).
+		Set(TagOrdStatus, status).
+		Set(TagSymbol, base+"/"+quote).
+		Set(TagSide, side).
+		Set(TagOrdType, "2").
+		Set(TagOrderQty, orderQty.String()).
+		Set(TagPrice, price).
+		Set(TagLeavesQty, leavesQty.String()).
+		Set(TagCumQty, cumQty.String()).
+		// Fill prices are not tracked per order; orders never fill worse than their limit
+		Set(TagAvgPx, price).
+		Set(TagTransactTime, sendingTime(time.Now())).
+		Set(TagSignedLimitPrice, data["LimitPrice"])
+	if txs := data["TransactionHashes"]; txs != "" {
+		report.Set(TagOrderTransactions, txs)
+	}
+	return report
+}
+
+// baseQty converts an amount of the order's SymbolIn into base token units: sells give the base,
+// buys give the quote at the order's signed rate
+func baseQty(data map[string]string, amountIn *big.Int) *big.Int {
+	base, _ := orderbook.GetPairKey(data["SymbolIn"], data["SymbolOut"])
+	if data["SymbolIn"] == base {
+		return new(big.Int).Set(amountIn)
+	}
+	amtIn := bigFromString(data["AmtIn"])
+	if amtIn.Sign() == 0 {
+		return big.NewInt(0)
+	}
+	qty := new(big.Int).Mul(amountIn, bigFromString(data["AmtOut"]))
+	return qty.Quo(qty, amtIn)
+}
+
+// ordStatus maps an order's ToStringMap form to OrdStatus (39)
+func ordStatus(data map[string]string) string {
+	if data["CancelSignature"] != "" {
+		return "4"
+	}
+	filled := bigFromString(data["FilledAmtIn"])
+	switch data["Status"] {
+	case "Completed":
+		return "2"
+	case "Cancelled":
+		return "4"
+	}
+	if filled.Sign() > 0 {
+		if filled.Cmp(bigFromString(data["AmtIn"])) >= 0 {
+			return "2"
+		}
+		return "1"
+	}
+	return "0"
+}
+
+func bigFromString(s string) *big.Int {
+	n, ok := new(big.Int).SetString(s, 10)
+	if !ok {
+		return big.NewInt(0)
+	}
+	return n
+}
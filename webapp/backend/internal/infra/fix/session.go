@@ -0,0 +1,403 @@
+package fix
+
+import (
+	"bufio"
+	"dexbe/internal/infra/metrics"
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"strconv"
+	"sync"
+	"time"
+)
+
+const (
+	// logonTimeout is how long a new connection has to send its Logon
+	logonTimeout = 10 * time.Second
+	// sendQueue bounds the outbound messages of a session; a counterparty that falls further behind
+	// is disconnected and can recover the rest with a ResendRequest
+	sendQueue = 1024
+	// resendWindow is how many sent application messages each session keeps for ResendRequests
+	resendWindow = 5000
+	// finishedWindow is how many filled or cancelled orders each session remembers, so their
+	// ClOrdIDs are still refused as duplicates and still resolve in status requests
+	finishedWindow = 10000
+	writeWait      = 10 * time.Second
+)
+
+// sessionState is what outlives a connection: sequence numbers, messages kept for resends and the
+// orders entered under the session, keyed by SenderCompID
+type sessionState struct {
+	mu      sync.Mutex
+	nextIn  int
+	nextOut int
+	sent    map[int]*Message
+	active  bool
+	orders  map[string]*orderState // by nonce
+	clOrd   map[string]string      // ClOrdID to nonce
+
+	// finished holds the nonces of orders that reached a terminal state, oldest first; beyond
+	// finishedWindow the oldest is forgotten
+	finished []string
+
+	// eventSeq is the last of the signer's order events turned into reports, so a reconnecting
+	// session catches up on what happened while it was away
+	eventSeq     uint64
+	eventsSeeded bool
+}
+
+func newSessionState() *sessionState {
+	return &sessionState{
+		nextIn:  1,
+		nextOut: 1,
+		sent:    make(map[int]*Message),
+		orders:  make(map[string]*orderState),
+		clOrd:   make(map[string]string),
+	}
+}
+
+// reset starts both sequences over, as asked by a Logon with ResetSeqNumFlag
+func (state *sessionState) reset() {
+	state.nextIn, state.nextOut = 1, 1
+	state.sent = make(map[int]*Message)
+}
+
+type outbound struct {
+	msg    *Message
+	resend bool // already stamped with its original MsgSeqNum
+}
+
+// session is one connection of a logged-on counterparty
+type session struct {
+	acceptor   *Acceptor
+	conn       net.Conn
+	config     SessionConfig
+	state      *sessionState
+	heartBtInt time.Duration
+
+	send      chan outbound
+	done      chan struct{}
+	closeOnce sync.Once
+	events    *eventSink
+
+	mu           sync.Mutex
+	lastReceived time.Time
+	lastSent     time.Time
+	testReqSent  bool
+	resendTo     int // highest MsgSeqNum already asked for in a ResendRequest
+}
+
+func (s *session) close() {
+	s.closeOnce.Do(func() { close(s.done) })
+}
+
+// enqueue hands msg to the writer. It never blocks; a full queue disconnects the session.
+func (s *session) enqueue(msg *Message, resend bool) {
+	select {
+	case <-s.done:
+		return
+	default:
+	}
+	select {
+	case s.send <- outbound{msg: msg, resend: resend}:
+	default:
+		metrics.DroppedMessages.WithLabelValues("fix").Inc()
+		log.Printf("**FIX**: %s not keeping up with %d queued messages, disconnecting", s.config.CompID, sendQueue)
+		s.close()
+	}
+}
+
+func (s *session) writeLoop() {
+	heartbeat := time.NewTicker(time.Second)
+	defer func() {
+		heartbeat.Stop()
+		s.conn.Close()
+	}()
+
+	write := func(out outbound) error {
+		msg := out.msg
+		if !out.resend {
+			s.state.mu.Lock()
+			seq := s.state.nextOut
+			s.state.nextOut++
+			msg.SetInt(TagMsgSeqNum, seq)
+			msg.Set(TagSenderCompID, s.acceptor.CompID)
+			msg.Set(TagTargetCompID, s.config.CompID)
+			msg.Set(TagSendingTime, sendingTime(time.Now()))
+			if !isAdmin(msg.Type()) {
+				s.state.sent[seq] = msg.Clone()
+				delete(s.state.sent, seq-resendWindow)
+			}
+			s.state.mu.Unlock()
+		}
+		s.conn.SetWriteDeadline(time.Now().Add(writeWait))
+		if _, err := s.conn.Write(msg.Encode()); err != nil {
+			return err
+		}
+		s.mu.Lock()
+		s.lastSent = time.Now()
+		s.mu.Unlock()
+		return nil
+	}
+
+	for {
+		select {
+		case out := <-s.send:
+			if err := write(out); err != nil {
+				log.Printf("**FIX**: write to %s failed: %v", s.config.CompID, err)
+				s.close()
+				return
+			}
+			if out.msg.Type() == MsgLogout {
+				s.close()
+				return
+			}
+		case now := <-heartbeat.C:
+			if err := s.checkHeartbeats(now, write); err != nil {
+				log.Printf("**FIX**: %s: %v", s.config.CompID, err)
+				s.close()
+				return
+			}
+		case <-s.done:
+			return
+		}
+	}
+}
+
+// checkHeartbeats sends a Heartbeat after HeartBtInt of silence on our side, and a TestRequest
+// after HeartBtInt plus a grace period of silence on theirs. A TestRequest left unanswered for
+// another HeartBtInt ends the session.
+func (s *session) checkHeartbeats(now time.Time, write func(outbound) error) error {
+	s.mu.Lock()
+	idleOut := now.Sub(s.lastSent)
+	idleIn := now.Sub(s.lastReceived)
+	testReqSent := s.testReqSent
+	s.mu.Unlock()
+
+	grace := s.heartBtInt / 5
+	switch {
+	case testReqSent && idleIn > 2*s.heartBtInt+grace:
+		return errors.New("no response to TestRequest")
+	case !testReqSent && idleIn > s.heartBtInt+grace:
+		s.mu.Lock()
+		s.testReqSent = true
+		s.mu.Unlock()
+		return write(outbound{msg: NewMessage(MsgTestRequest).Set(TagTestReqID, strconv.FormatInt(now.Unix(), 10))})
+	case idleOut >= s.heartBtInt:
+		return write(outbound{msg: NewMessage(MsgHeartbeat)})
+	}
+	return nil
+}
+
+func isAdmin(msgType string) bool {
+	switch msgType {
+	case MsgHeartbeat, MsgTestRequest, MsgResendRequest, MsgReject, MsgSequenceReset, MsgLogout, MsgLogon:
+		return true
+	}
+	return false
+}
+
+// readLoop handles the session after Logon until the connection ends
+func (s *session) readLoop(r *bufio.Reader) {
+	for {
+		s.conn.SetReadDeadline(time.Now().Add(3 * s.heartBtInt))
+		msg, err := ReadMessage(r)
+		if errors.Is(err, ErrGarbled) {
+			log.Printf("**FIX**: ignoring message from %s: %v", s.config.CompID, err)
+			continue
+		}
+		if err != nil {
+			s.close()
+			return
+		}
+		s.mu.Lock()
+		s.lastReceived = time.Now()
+		s.testReqSent = false
+		s.mu.Unlock()
+
+		if !s.handle(msg) {
+			// Give the writer a moment to get the Logout out
+			select {
+			case <-s.done:
+			case <-time.After(writeWait):
+				s.close()
+			}
+			return
+		}
+	}
+}
+
+// handle applies the session protocol to msg and passes application messages on. It returns false
+// once the session is over.
+func (s *session) handle(msg *Message) bool {
+	if msg.Get(TagSenderCompID) != s.config.CompID || msg.Get(TagTargetCompID) != s.acceptor.CompID {
+		s.logout("CompID problem")
+		return false
+	}
+	seq, err := msg.GetInt(TagMsgSeqNum)
+	if err != nil {
+		s.logout("missing or invalid MsgSeqNum")
+		return false
+	}
+
+	// A SequenceReset in reset mode applies whatever its MsgSeqNum
+	if msg.Type() == MsgSequenceReset && msg.Get(TagGapFillFlag) != "Y" {
+		s.applySequenceReset(msg)
+		return true
+	}
+
+	s.state.mu.Lock()
+	expected := s.state.nextIn
+	s.state.mu.Unlock()
+
+	switch {
+	case seq < expected:
+		if msg.Get(TagPossDupFlag) == "Y" {
+			return true
+		}
+		s.logout(fmt.Sprintf("MsgSeqNum too low, expecting %d but received %d", expected, seq))
+		return false
+	case seq > expected:
+		// Answer resends even during a gap, otherwise wait for the gap to be filled, which brings
+		// this message again
+		if msg.Type() == MsgResendRequest {
+			s.resend(msg)
+		}
+		if msg.Type() == MsgLogout {
+			s.enqueue(NewMessage(MsgLogout), false)
+			return false
+		}
+		s.requestResend(expected, seq)
+		return true
+	}
+
+	s.state.mu.Lock()
+	s.state.nextIn = seq + 1
+	s.state.mu.Unlock()
+
+	switch msg.Type() {
+	case MsgHeartbeat:
+	case MsgReject:
+		log.Printf("**FIX**: %s rejected our message %s: %s", s.config.CompID, msg.Get(TagRefSeqNum), msg.Get(TagText))
+	case MsgTestRequest:
+		s.enqueue(NewMessage(MsgHeartbeat).Set(TagTestReqID, msg.Get(TagTestReqID)), false)
+	case MsgResendRequest:
+		s.resend(msg)
+	case MsgSequenceReset:
+		s.applySequenceReset(msg)
+	case MsgLogout:
+		s.enqueue(NewMessage(MsgLogout), false)
+		return false
+	case MsgLogon:
+		s.reject(msg, 0, "already logged on", TagMsgType)
+	default:
+		s.acceptor.handleApplication(s, msg)
+	}
+	return true
+}
+
+func (s *session) applySequenceReset(msg *Message) {
+	newSeq, err := msg.GetInt(TagNewSeqNo)
+	if err != nil {
+		s.reject(msg, 1, "NewSeqNo required", TagNewSeqNo)
+		return
+	}
+	s.state.mu.Lock()
+	defer s.state.mu.Unlock()
+	if newSeq < s.state.nextIn {
+		log.Printf("**FIX**: %s tried to reset MsgSeqNum back to %d, keeping %d", s.config.CompID, newSeq, s.state.nextIn)
+		return
+	}
+	s.state.nextIn = newSeq
+}
+
+// requestResend asks for everything from expected on, once per gap
+func (s *session) requestResend(expected, received int) {
+	s.mu.Lock()
+	already := s.resendTo >= received
+	if !already {
+		s.resendTo = received
+	}
+	s.mu.Unlock()
+	if already {
+		return
+	}
+	s.enqueue(NewMessage(MsgResendRequest).SetInt(TagBeginSeqNo, expected).SetInt(TagEndSeqNo, 0), false)
+}
+
+// resend replays the application messages still held for the requested range with PossDupFlag set,
+// and covers admin messages and anything no longer held with a gap fill
+func (s *session) resend(msg *Message) {
+	begin, err := msg.GetInt(TagBeginSeqNo)
+	if err != nil || begin < 1 {
+		s.reject(msg, 5, "invalid BeginSeqNo", TagBeginSeqNo)
+		return
+	}
+	end, err := msg.GetInt(TagEndSeqNo)
+	if err != nil {
+		s.reject(msg, 5, "invalid EndSeqNo", TagEndSeqNo)
+		return
+	}
+
+	s.state.mu.Lock()
+	last := s.state.nextOut - 1
+	if end == 0 || end > last {
+		end = last
+	}
+	var replay []*Message
+	gapStart := 0
+	flushGap := func(next int) {
+		if gapStart == 0 {
+			return
+		}
+		replay = append(replay, NewMessage(MsgSequenceReset).
+			SetInt(TagMsgSeqNum, gapStart).
+			Set(TagPossDupFlag, "Y").
+			Set(TagGapFillFlag, "Y").
+			SetInt(TagNewSeqNo, next))
+		gapStart = 0
+	}
+	for seq := begin; seq <= end; seq++ {
+		stored, held := s.state.sent[seq]
+		if !held {
+			if gapStart == 0 {
+				gapStart = seq
+			}
+			continue
+		}
+		flushGap(seq)
+		dup := stored.Clone()
+		dup.Set(TagOrigSendingTime, dup.Get(TagSendingTime))
+		dup.Set(TagPossDupFlag, "Y")
+		replay = append(replay, dup)
+	}
+	flushGap(end + 1)
+	s.state.mu.Unlock()
+
+	now := sendingTime(time.Now())
+	for _, m := range replay {
+		m.Set(TagSenderCompID, s.acceptor.CompID)
+		m.Set(TagTargetCompID, s.config.CompID)
+		m.Set(TagSendingTime, now)
+		s.enqueue(m, true)
+	}
+}
+
+// reject sends a session-level Reject for msg
+func (s *session) reject(msg *Message, reason int, text string, refTag int) {
+	reply := NewMessage(MsgReject).
+		Set(TagRefSeqNum, msg.Get(TagMsgSeqNum)).
+		Set(TagRefMsgType, msg.Type()).
+		SetInt(TagSessionRejReason, reason).
+		Set(TagText, text)
+	if refTag != 0 {
+		reply.SetInt(TagRefTagID, refTag)
+	}
+	s.enqueue(reply, false)
+}
+
+func (s *session) logout(text string) {
+	log.Printf("**FIX**: logging out %s: %s", s.config.CompID, text)
+	s.enqueue(NewMessage(MsgLogout).Set(TagText, text), false)
+}
@@ -0,0 +1,111 @@
+package metrics
+
+import (
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "dex"
+
+// Registry holds every metric of the process, served by Handler
+var Registry = prometheus.NewRegistry()
+
+// Order admission, by entry point ("rest", "fix", "grpc") and, for rejections, the check that failed
+var (
+	OrdersAccepted = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "orders_accepted_total",
+		Help:      "Orders admitted to a book.",
+	}, []string{"source"})
+
+	OrdersRejected = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "orders_rejected_total",
+		Help:      "Orders refused at admission.",
+	}, []string{"source", "reason"})
+)
+
+// Matching and settlement. kind is "match" for a direct match in one book and "ring" for a ring
+// across books.
+var (
+	Matches = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "matches_total",
+		Help:      "Direct matches submitted for settlement, by pair.",
+	}, []string{"pair"})
+
+	RingsFound = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "rings_found_total",
+		Help:      "Rings found across books, by number of orders.",
+	}, []string{"size"})
+
+	SettlementLatency = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "settlement_seconds",
+		Help:      "Time from a match being found to its transaction receipt.",
+		Buckets:   []float64{0.05, 0.1, 0.25, 0.5, 1, 2, 4, 8, 15, 30, 60, 120},
+	}, []string{"kind", "result"})
+
+	FailedTransactions = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "failed_transactions_total",
+		Help:      "Settlement transactions that could not be sent or reverted.",
+	}, []string{"kind", "stage"})
+)
+
+// Fan-out to websocket and stream subscribers
+var (
+	WsConnections = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "ws_connections",
+		Help:      "Open websocket connections.",
+	})
+
+	Subscribers = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "subscribers",
+		Help:      "Subscriptions per feed, across websocket, /stream channels, FIX and gRPC.",
+	}, []string{"feed"})
+
+	DroppedMessages = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "dropped_messages_total",
+		Help:      "Messages dropped for a subscriber that fell too far behind, which is then disconnected.",
+	}, []string{"transport"})
+)
+
+// AuditWriteFailures counts engine decisions that could not be added to the audit log
+var AuditWriteFailures = prometheus.NewCounter(prometheus.CounterOpts{
+	Namespace: namespace,
+	Name:      "audit_write_failures_total",
+	Help:      "Engine audit records that could not be written and are missing from the log.",
+})
+
+func init() {
+	Registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		OrdersAccepted, OrdersRejected,
+		Matches, RingsFound, SettlementLatency, FailedTransactions,
+		WsConnections, Subscribers, DroppedMessages,
+		AuditWriteFailures,
+	)
+}
+
+// RegisterQueue reports the backlog of an internal channel, such as the user event queue
+func RegisterQueue(name string, length func() int) {
+	Registry.MustRegister(prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+		Namespace:   namespace,
+		Name:        "queue_length",
+		Help:        "Messages waiting in an internal queue.",
+		ConstLabels: prometheus.Labels{"queue": name},
+	}, func() float64 { return float64(length()) }))
+}
+
+func Handler() http.Handler {
+	return promhttp.HandlerFor(Registry, promhttp.HandlerOpts{Registry: Registry})
+}
@@ -0,0 +1,114 @@
+package rpc
+
+import (
+	"dexbe/internal/domains/order"
+	"dexbe/internal/domains/orderbook"
+	"dexbe/internal/domains/token"
+	"dexbe/internal/domains/trade"
+	"dexbe/proto/dexpb"
+	"encoding/hex"
+	"encoding/json"
+	"strings"
+	"time"
+
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+var orderStatuses = map[string]dexpb.OrderStatus{
+	"Matching":            dexpb.OrderStatus_ORDER_STATUS_MATCHING,
+	"PendingConfirmation": dexpb.OrderStatus_ORDER_STATUS_PENDING_CONFIRMATION,
+	"Completed":           dexpb.OrderStatus_ORDER_STATUS_COMPLETED,
+	"Cancelled":           dexpb.OrderStatus_ORDER_STATUS_CANCELLED,
+	"PartialFill":         dexpb.OrderStatus_ORDER_STATUS_PARTIAL_FILL,
+}
+
+func orderToProto(o *order.Order) *dexpb.Order {
+	return orderFromStringMap(o.ToStringMap())
+}
+
+// orderFromStringMap reads an order in its ToStringMap form, which is also how order events carry
+// it, so orders look the same whether queried or streamed
+func orderFromStringMap(m map[string]string) *dexpb.Order {
+	o := &dexpb.Order{
+		CreatedBy:    m["CreatedBy"],
+		SymbolIn:     m["SymbolIn"],
+		SymbolOut:    m["SymbolOut"],
+		AmtIn:        m["AmtIn"],
+		AmtOut:       m["AmtOut"],
+		Nonce:        m["Nonce"],
+		LimitPrice:   m["LimitPrice"],
+		TriggerPrice: m["TriggerPrice"],
+		FilledAmtIn:  m["FilledAmtIn"],
+		Status:       orderStatuses[m["Status"]],
+	}
+	o.Signature, _ = hex.DecodeString(m["Signature"])
+	o.CancelSignature, _ = hex.DecodeString(m["CancelSignature"])
+	if txs := m["TransactionHashes"]; txs != "" {
+		o.TransactionHashes = strings.Split(txs, ",")
+	}
+	if createdAt, err := time.Parse(time.RFC3339Nano, m["CreatedAt"]); err == nil && !createdAt.IsZero() {
+		o.CreatedAt = timestamppb.New(createdAt)
+	}
+	if nested := m["ConditionalOrder"]; nested != "" {
+		var conditional map[string]string
+		if json.Unmarshal([]byte(nested), &conditional) == nil {
+			o.ConditionalOrder = orderFromStringMap(conditional)
+		}
+	}
+	return o
+}
+
+func ordersToProto(orders []*order.Order) []*dexpb.Order {
+	result := make([]*dexpb.Order, 0, len(orders))
+	for _, o := range orders {
+		result = append(result, orderToProto(o))
+	}
+	return result
+}
+
+func tokenToProto(t *token.Token) *dexpb.Token {
+	return &dexpb.Token{Name: t.Name, Symbol: t.Symbol, Address: t.Address.Hex()}
+}
+
+func tradeToProto(t *trade.Trade) *dexpb.Trade {
+	result := &dexpb.Trade{
+		Id:        t.ID,
+		Pair:      t.Pair,
+		Base:      t.Base,
+		Quote:     t.Quote,
+		Price:     t.Price.String(),
+		BaseQty:   t.BaseQty.String(),
+		QuoteQty:  t.QuoteQty.String(),
+		Maker:     t.Maker.Hex(),
+		Taker:     t.Taker.Hex(),
+		RingSize:  uint32(t.RingSize),
+		TxHash:    t.TxHash,
+		Timestamp: timestamppb.New(t.Timestamp),
+	}
+	switch t.AggressorSide {
+	case trade.Buy:
+		result.AggressorSide = dexpb.Side_SIDE_BUY
+	case trade.Sell:
+		result.AggressorSide = dexpb.Side_SIDE_SELL
+	}
+	if t.MakerNonce != nil {
+		result.MakerNonce = t.MakerNonce.String()
+	}
+	if t.TakerNonce != nil {
+		result.TakerNonce = t.TakerNonce.String()
+	}
+	return result
+}
+
+func levelsToProto(levels []orderbook.DepthLevel) []*dexpb.DepthLevel {
+	result := make([]*dexpb.DepthLevel, 0, len(levels))
+	for _, level := range levels {
+		result = append(result, &dexpb.DepthLevel{
+			PriceRaw:   level.PriceRaw,
+			Price:      level.Price,
+			Quantity:   level.Quantity,
+			OrderCount: uint32(level.OrderCount),
+		})
+	}
+	return result
+}
@@ -0,0 +1,156 @@
+package rpc
+
+import (
+	"context"
+	"dexbe/internal/domains/order"
+	"dexbe/internal/domains/orderbook"
+	"dexbe/internal/domains/registry"
+	"dexbe/internal/infra/api"
+	"dexbe/internal/infra/api/controllers"
+	"dexbe/proto/dexpb"
+	"errors"
+	"math/big"
+	"sort"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// orderError maps the errors of order admission and cancellation onto status codes
+func orderError(err error) error {
+	var fundingErr *registry.FundingError
+	var limited *api.RateLimitError
+	switch {
+	case errors.As(err, &fundingErr):
+		return status.Error(codes.FailedPrecondition, err.Error())
+	case errors.As(err, &limited):
+		return status.Error(codes.ResourceExhausted, err.Error())
+	case errors.Is(err, controller.ErrInvalidCancelSignature):
+		return status.Error(codes.PermissionDenied, err.Error())
+	case errors.Is(err, orderbook.ErrOrderNotFound):
+		return status.Error(codes.NotFound, err.Error())
+	case errors.Is(err, orderbook.ErrShuttingDown):
+		return status.Error(codes.Unavailable, err.Error())
+	}
+	return status.Error(codes.InvalidArgument, err.Error())
+}
+
+func parseAmount(name, value string) (*big.Int, error) {
+	amount, ok := new(big.Int).SetString(value, 10)
+	if !ok {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid %s", name)
+	}
+	return amount, nil
+}
+
+func (srv *Server) SubmitOrder(ctx context.Context, req *dexpb.SubmitOrderRequest) (*dexpb.SubmitOrderResponse, error) {
+	signed := req.GetOrder()
+	if signed == nil {
+		return nil, status.Error(codes.InvalidArgument, "order is required")
+	}
+	if !common.IsHexAddress(signed.CreatedBy) {
+		return nil, status.Error(codes.InvalidArgument, "invalid createdBy")
+	}
+	for name, value := range map[string]string{"amtIn": signed.AmtIn, "amtOut": signed.AmtOut, "nonce": signed.Nonce} {
+		if _, err := parseAmount(name, value); err != nil {
+			return nil, err
+		}
+	}
+	o := order.NewOrder(signed.CreatedBy, signed.SymbolIn, signed.SymbolOut, signed.AmtIn, signed.AmtOut, signed.Nonce,
+		hexutil.Encode(signed.Signature), "", "", int(order.Matching), nil, "")
+	if err := srv.Orders.SubmitOrder(o, controller.OrderSourceGRPC); err != nil {
+		return nil, orderError(err)
+	}
+	return &dexpb.SubmitOrderResponse{Order: orderToProto(o)}, nil
+}
+
+func (srv *Server) CancelOrder(ctx context.Context, req *dexpb.CancelOrderRequest) (*dexpb.CancelOrderResponse, error) {
+	if !common.IsHexAddress(req.CreatedBy) {
+		return nil, status.Error(codes.InvalidArgument, "invalid createdBy")
+	}
+	nonce, err := parseAmount("nonce", req.Nonce)
+	if err != nil {
+		return nil, err
+	}
+	limitPrice, err := parseAmount("limitPrice", req.LimitPrice)
+	if err != nil {
+		return nil, err
+	}
+	if err := srv.Orders.SubmitCancel(common.HexToAddress(req.CreatedBy), nonce, limitPrice, req.SymbolIn, req.SymbolOut, req.Signature); err != nil {
+		return nil, orderError(err)
+	}
+	return &dexpb.CancelOrderResponse{}, nil
+}
+
+func (srv *Server) GetOpenOrders(ctx context.Context, req *dexpb.GetOpenOrdersRequest) (*dexpb.GetOpenOrdersResponse, error) {
+	session, err := srv.session(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if (req.SymbolIn == "") != (req.SymbolOut == "") {
+		return nil, status.Error(codes.InvalidArgument, "symbolIn and symbolOut go together")
+	}
+	if req.SymbolIn == "" {
+		return &dexpb.GetOpenOrdersResponse{Orders: ordersToProto(srv.OrderBookStore.GetOrdersByCreator(session.Address))}, nil
+	}
+	orders, err := srv.OrderBookStore.GetOrdersByCreatorInBook(session.Address, req.SymbolIn, req.SymbolOut)
+	if err != nil {
+		return nil, status.Error(codes.NotFound, err.Error())
+	}
+	return &dexpb.GetOpenOrdersResponse{Orders: ordersToProto(orders)}, nil
+}
+
+func (srv *Server) GetOrderHistory(ctx context.Context, req *dexpb.GetOrderHistoryRequest) (*dexpb.GetOrderHistoryResponse, error) {
+	session, err := srv.session(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var records []order.Order
+	if req.Nonce != "" {
+		nonce, err := parseAmount("nonce", req.Nonce)
+		if err != nil {
+			return nil, err
+		}
+		records = srv.OrderBookStore.GetOrderHistory(session.Address, nonce)
+	} else {
+		history := srv.OrderBookStore.GetAllOrderHistoryForAddress(session.Address)
+		keys := make([]string, 0, len(history))
+		for key := range history {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			records = append(records, history[key]...)
+		}
+	}
+	orders := make([]*dexpb.Order, 0, len(records))
+	for i := range records {
+		orders = append(orders, orderToProto(&records[i]))
+	}
+	return &dexpb.GetOrderHistoryResponse{Orders: orders}, nil
+}
+
+func (srv *Server) ListTokens(ctx context.Context, req *dexpb.ListTokensRequest) (*dexpb.ListTokensResponse, error) {
+	symbols := srv.TokenRegistry.GetAllSymbols()
+	sort.Strings(symbols)
+	tokens := make([]*dexpb.Token, 0, len(symbols))
+	for _, symbol := range symbols {
+		if t := srv.TokenRegistry.Get(symbol); t != nil {
+			tokens = append(tokens, tokenToProto(t))
+		}
+	}
+	return &dexpb.ListTokensResponse{Tokens: tokens}, nil
+}
+
+func (srv *Server) ListPairs(ctx context.Context, req *dexpb.ListPairsRequest) (*dexpb.ListPairsResponse, error) {
+	pairIDs := srv.OrderBookStore.Pairs()
+	pairs := make([]*dexpb.Pair, 0, len(pairIDs))
+	for _, pairID := range pairIDs {
+		base, quote, _ := strings.Cut(pairID, "/")
+		pairs = append(pairs, &dexpb.Pair{Id: pairID, Base: base, Quote: quote})
+	}
+	return &dexpb.ListPairsResponse{Pairs: pairs}, nil
+}
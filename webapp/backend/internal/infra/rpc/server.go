@@ -0,0 +1,169 @@
+package rpc
+
+import (
+	"context"
+	"dexbe/internal/domains/auth"
+	"dexbe/internal/domains/orderbook"
+	"dexbe/internal/domains/registry"
+	"dexbe/internal/infra/api"
+	"dexbe/internal/infra/api/controllers"
+	"dexbe/proto/dexpb"
+	"errors"
+	"log"
+	"net"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// methodGroups puts each RPC under the rate limit group of its REST or websocket counterpart
+var methodGroups = map[string]string{
+	dexpb.Dex_SubmitOrder_FullMethodName:       api.LimitGroupOrder,
+	dexpb.Dex_CancelOrder_FullMethodName:       api.LimitGroupOrder,
+	dexpb.Dex_GetOpenOrders_FullMethodName:     api.LimitGroupOrder,
+	dexpb.Dex_GetOrderHistory_FullMethodName:   api.LimitGroupOrder,
+	dexpb.Dex_ListTokens_FullMethodName:        api.LimitGroupMarket,
+	dexpb.Dex_ListPairs_FullMethodName:         api.LimitGroupMarket,
+	dexpb.Dex_StreamBook_FullMethodName:        api.LimitGroupWs,
+	dexpb.Dex_StreamTrades_FullMethodName:      api.LimitGroupWs,
+	dexpb.Dex_StreamOrderEvents_FullMethodName: api.LimitGroupWs,
+}
+
+// signerLimited methods take the address bucket in the order controller once the signature is
+// verified, so allow only limits them by IP
+var signerLimited = map[string]bool{
+	dexpb.Dex_SubmitOrder_FullMethodName: true,
+	dexpb.Dex_CancelOrder_FullMethodName: true,
+}
+
+// Server implements the Dex gRPC service on top of the controllers and stores the echo API uses,
+// so orders are admitted and feeds are fanned out by the same code whichever API a client speaks
+type Server struct {
+	dexpb.UnimplementedDexServer
+	Orders         *controller.OrderController
+	OrderBookStore *orderbook.OrderBookStore
+	TokenRegistry  *registry.Registry
+	Sessions       *auth.SessionStore
+	RateLimits     *api.RateLimits
+	grpcServer     *grpc.Server
+}
+
+// NewServer builds the Dex service. Session tokens travel in call metadata, so with nil creds the
+// server is plaintext and must only be reachable through a TLS terminating proxy or a private
+// network.
+func NewServer(orders *controller.OrderController, store *orderbook.OrderBookStore, registry *registry.Registry, sessions *auth.SessionStore, limits *api.RateLimits, creds credentials.TransportCredentials) *Server {
+	srv := &Server{
+		Orders:         orders,
+		OrderBookStore: store,
+		TokenRegistry:  registry,
+		Sessions:       sessions,
+		RateLimits:     limits,
+	}
+	options := []grpc.ServerOption{
+		grpc.ChainUnaryInterceptor(srv.limitUnary),
+		grpc.ChainStreamInterceptor(srv.limitStream),
+	}
+	if creds != nil {
+		options = append(options, grpc.Creds(creds))
+	}
+	srv.grpcServer = grpc.NewServer(options...)
+	dexpb.RegisterDexServer(srv.grpcServer, srv)
+	return srv
+}
+
+// ListenAndServe serves the Dex service on addr until the listener fails or Shutdown is called
+func (srv *Server) ListenAndServe(addr string) error {
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return err
+	}
+	log.Printf("gRPC server listening on %s", addr)
+	if err := srv.grpcServer.Serve(listener); !errors.Is(err, grpc.ErrServerStopped) {
+		return err
+	}
+	return nil
+}
+
+// Shutdown stops taking calls, ends the feed streams with Unavailable and lets unary calls in
+// flight finish. Whatever is still running when ctx ends is cut off.
+func (srv *Server) Shutdown(ctx context.Context) error {
+	closeStreams()
+	stopped := make(chan struct{})
+	go func() {
+		srv.grpcServer.GracefulStop()
+		close(stopped)
+	}()
+	select {
+	case <-stopped:
+		return nil
+	case <-ctx.Done():
+		srv.grpcServer.Stop()
+		return ctx.Err()
+	}
+}
+
+// session returns the SIWE session passed as "authorization: Bearer <token>" metadata
+func (srv *Server) session(ctx context.Context) (*auth.Session, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	for _, header := range md.Get("authorization") {
+		if token, ok := strings.CutPrefix(header, "Bearer "); ok {
+			session, err := srv.Sessions.Get(strings.TrimSpace(token))
+			if err != nil {
+				return nil, status.Error(codes.Unauthenticated, err.Error())
+			}
+			return session, nil
+		}
+	}
+	return nil, status.Error(codes.Unauthenticated, auth.ErrInvalidSession.Error())
+}
+
+func clientIP(ctx context.Context) string {
+	if p, ok := peer.FromContext(ctx); ok {
+		if host, _, err := net.SplitHostPort(p.Addr.String()); err == nil {
+			return host
+		}
+		return p.Addr.String()
+	}
+	return ""
+}
+
+// allow applies the method's rate limit group by peer IP and, when the call has a session, by the
+// session address. The createdBy a request claims is not verified yet, so it never picks a bucket.
+func (srv *Server) allow(ctx context.Context, method string) error {
+	group, limited := methodGroups[method]
+	if !limited {
+		return nil
+	}
+	var signer *common.Address
+	if !signerLimited[method] {
+		if session, err := srv.session(ctx); err == nil {
+			signer = &session.Address
+		}
+	}
+	ip := clientIP(ctx)
+	if ok, key, retry := srv.RateLimits.Allow(group, ip, signer); !ok {
+		log.Printf("**Rate Limited**: gRPC %s by %s (%s), retry after %v", method, key, ip, retry)
+		return status.Errorf(codes.ResourceExhausted, "rate limit exceeded for %s by %s, retry after %dms", group, key, retry.Milliseconds())
+	}
+	return nil
+}
+
+func (srv *Server) limitUnary(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	if err := srv.allow(ctx, info.FullMethod); err != nil {
+		return nil, err
+	}
+	return handler(ctx, req)
+}
+
+func (srv *Server) limitStream(s any, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	if err := srv.allow(stream.Context(), info.FullMethod); err != nil {
+		return err
+	}
+	return handler(s, stream)
+}
@@ -0,0 +1,300 @@
+package rpc
+
+import (
+	"dexbe/internal/domains/orderbook"
+	"dexbe/internal/domains/trade"
+	"dexbe/internal/infra/api"
+	"dexbe/internal/infra/metrics"
+	"dexbe/proto/dexpb"
+	"encoding/json"
+	"errors"
+	"log"
+	"math/big"
+	"sync"
+
+	"github.com/gorilla/websocket"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+var errSlowConsumer = errors.New("slow consumer")
+
+// liveSinks holds every open stream so Shutdown can end them
+var (
+	liveSinks   = map[*streamSink]bool{}
+	liveSinksMu sync.Mutex
+)
+
+// streamSink subscribes a server stream to the websocket fan-outs. The broadcasters hand it the
+// same encoded frames a websocket gets, never waiting on it; the stream's handler decodes them.
+//
+// A stream may fall behind by api.WsSendQueue frames before it is ended, as a websocket would be.
+type streamSink struct {
+	frames chan []byte
+	done   chan struct{}
+	once   sync.Once
+	err    error
+}
+
+func newStreamSink() *streamSink {
+	return &streamSink{frames: make(chan []byte, api.WsSendQueue), done: make(chan struct{})}
+}
+
+// closeStreams ends every open stream with Unavailable
+func closeStreams() {
+	liveSinksMu.Lock()
+	defer liveSinksMu.Unlock()
+	for sink := range liveSinks {
+		sink.fail(nil)
+	}
+}
+
+func (sink *streamSink) WriteMessage(messageType int, data []byte) error {
+	select {
+	case <-sink.done:
+		return api.ErrWsClosed
+	default:
+	}
+	select {
+	case sink.frames <- data:
+		return nil
+	default:
+		metrics.DroppedMessages.WithLabelValues("grpc").Inc()
+		sink.fail(errSlowConsumer)
+		return errSlowConsumer
+	}
+}
+
+// QueueFree is how many more frames fit before the stream is ended as a slow consumer
+func (sink *streamSink) QueueFree() int {
+	return cap(sink.frames) - len(sink.frames)
+}
+
+func (sink *streamSink) Close() error {
+	sink.fail(nil)
+	return nil
+}
+
+func (sink *streamSink) fail(err error) {
+	sink.once.Do(func() {
+		sink.err = err
+		close(sink.done)
+	})
+}
+
+// run hands each frame to send until the client goes away, send fails or the sink is ended
+func (sink *streamSink) run(stream grpc.ServerStream, send func(frame []byte) error) error {
+	liveSinksMu.Lock()
+	liveSinks[sink] = true
+	liveSinksMu.Unlock()
+	defer func() {
+		liveSinksMu.Lock()
+		delete(liveSinks, sink)
+		liveSinksMu.Unlock()
+	}()
+	for {
+		select {
+		case frame := <-sink.frames:
+			if err := send(frame); err != nil {
+				return err
+			}
+		case <-sink.done:
+			if sink.err != nil {
+				return status.Errorf(codes.ResourceExhausted, "%v: more than %d messages behind", sink.err, cap(sink.frames))
+			}
+			return status.Error(codes.Unavailable, "stream closed by server")
+		case <-stream.Context().Done():
+			return nil
+		}
+	}
+}
+
+// bookFrame is a Snapshot or Depth message of the depth feed
+type bookFrame struct {
+	Event     string                 `json:"event"`
+	Pair      string                 `json:"pair"`
+	Seq       uint64                 `json:"seq"`
+	PrevSeq   uint64                 `json:"prevSeq"`
+	LastPrice float64                `json:"lastPrice"`
+	Bids      []orderbook.DepthLevel `json:"bids"`
+	Asks      []orderbook.DepthLevel `json:"asks"`
+	Data      struct {
+		Bids []orderbook.DepthLevel `json:"bids"`
+		Asks []orderbook.DepthLevel `json:"asks"`
+	} `json:"data"`
+}
+
+// StreamBook subscribes to the pair's depth feed for the requested view, then queues a snapshot.
+// Depth messages can reach the sink on either side of it: those ahead of it are held back, and of
+// all of them only the ones newer than the snapshot are sent, so the client gets the snapshot
+// followed by exactly the deltas after it.
+func (srv *Server) StreamBook(req *dexpb.StreamBookRequest, stream grpc.ServerStreamingServer[dexpb.BookUpdate]) error {
+	base, quote := orderbook.GetPairKey(req.SymbolIn, req.SymbolOut)
+	book, exists := srv.OrderBookStore.Books[base+"/"+quote]
+	if !exists {
+		return status.Errorf(codes.NotFound, "order book for %s/%s not found", base, quote)
+	}
+	view, err := orderbook.ParseDepthView(req.Group, int(req.Depth))
+	if err != nil {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	sink := newStreamSink()
+	book.AddSubscriber(sink, view)
+	defer book.RemoveSubscriber(sink)
+
+	book.Mu.RLock()
+	snapshot := book.DepthSnapshot(view)
+	book.Mu.RUnlock()
+	snapshot["event"] = "Snapshot"
+	encoded, _ := json.Marshal(snapshot)
+	sink.WriteMessage(websocket.TextMessage, encoded)
+
+	var snapshotSeq *uint64
+	var early []*dexpb.BookUpdate // deltas that overtook the snapshot
+	return sink.run(stream, func(data []byte) error {
+		var frame bookFrame
+		if err := json.Unmarshal(data, &frame); err != nil {
+			log.Printf("**gRPC**: unreadable depth message: %v", err)
+			return nil
+		}
+		update := &dexpb.BookUpdate{Pair: frame.Pair, Seq: frame.Seq, PrevSeq: frame.PrevSeq, LastPrice: frame.LastPrice}
+		if frame.Event == "Snapshot" {
+			snapshotSeq = &frame.Seq
+			update.Snapshot = true
+			update.Bids, update.Asks = levelsToProto(frame.Bids), levelsToProto(frame.Asks)
+			if err := stream.Send(update); err != nil {
+				return err
+			}
+			for _, delta := range early {
+				if delta.Seq <= *snapshotSeq {
+					continue
+				}
+				if err := stream.Send(delta); err != nil {
+					return err
+				}
+			}
+			early = nil
+			return nil
+		}
+		update.Bids, update.Asks = levelsToProto(frame.Data.Bids), levelsToProto(frame.Data.Asks)
+		if snapshotSeq == nil {
+			early = append(early, update)
+			return nil
+		}
+		if frame.Seq <= *snapshotSeq {
+			return nil
+		}
+		return stream.Send(update)
+	})
+}
+
+func (srv *Server) StreamTrades(req *dexpb.StreamTradesRequest, stream grpc.ServerStreamingServer[dexpb.Trade]) error {
+	if (req.SymbolIn == "") != (req.SymbolOut == "") {
+		return status.Error(codes.InvalidArgument, "symbolIn and symbolOut go together")
+	}
+	pair := trade.AllPairs
+	if req.SymbolIn != "" {
+		base, quote := orderbook.GetPairKey(req.SymbolIn, req.SymbolOut)
+		pair = base + "/" + quote
+		if _, exists := srv.OrderBookStore.Books[pair]; !exists {
+			return status.Errorf(codes.NotFound, "order book for %s not found", pair)
+		}
+	}
+
+	sink := newStreamSink()
+	srv.OrderBookStore.Trades.AddSubscriber(pair, sink)
+	defer srv.OrderBookStore.Trades.RemoveSubscriber(pair, sink)
+
+	return sink.run(stream, func(data []byte) error {
+		var frame struct {
+			Data trade.Trade `json:"data"`
+		}
+		if err := json.Unmarshal(data, &frame); err != nil {
+			log.Printf("**gRPC**: unreadable trade message: %v", err)
+			return nil
+		}
+		return stream.Send(tradeToProto(&frame.Data))
+	})
+}
+
+// StreamOrderEvents follows the session signer's order events the way /ws does, including resuming
+// from a previous stream's epoch and seq
+func (srv *Server) StreamOrderEvents(req *dexpb.StreamOrderEventsRequest, stream grpc.ServerStreamingServer[dexpb.OrderEvent]) error {
+	session, err := srv.session(stream.Context())
+	if err != nil {
+		return err
+	}
+	addr := session.Address
+
+	sink := newStreamSink()
+	openOrders := func() []map[string]string {
+		open := srv.OrderBookStore.GetOrdersByCreator(addr)
+		orders := make([]map[string]string, 0, len(open))
+		for _, o := range open {
+			orders = append(orders, o.ToStringMap())
+		}
+		return orders
+	}
+	if !api.SubscribeUser(addr, sink, req.Epoch, req.Since, openOrders) {
+		return status.Errorf(codes.Unavailable, "too many events for %s to resync, retry", addr.Hex())
+	}
+	defer api.RemoveSubscriber(addr, sink)
+
+	return sink.run(stream, func(data []byte) error {
+		event, err := orderEventFromFrame(data)
+		if err != nil {
+			log.Printf("**gRPC**: unreadable order event for %s: %v", addr.Hex(), err)
+			return nil
+		}
+		return stream.Send(event)
+	})
+}
+
+// orderEventFromFrame decodes a /ws event: {"event", "seq", "data"} with the order's ToStringMap
+// form as data, {"nonce"} for OrderRemove, or the epoch and seq (plus open orders) for Subscribed
+// and Resync
+func orderEventFromFrame(data []byte) (*dexpb.OrderEvent, error) {
+	var frame struct {
+		Event string          `json:"event"`
+		Seq   uint64          `json:"seq"`
+		Data  json.RawMessage `json:"data"`
+	}
+	if err := json.Unmarshal(data, &frame); err != nil {
+		return nil, err
+	}
+	event := &dexpb.OrderEvent{Event: frame.Event, Seq: frame.Seq}
+
+	switch frame.Event {
+	case "Subscribed", "Resync":
+		var intro struct {
+			Epoch  string              `json:"epoch"`
+			Seq    uint64              `json:"seq"`
+			Orders []map[string]string `json:"orders"`
+		}
+		if err := json.Unmarshal(frame.Data, &intro); err != nil {
+			return nil, err
+		}
+		event.Epoch, event.Seq = intro.Epoch, intro.Seq
+		for _, o := range intro.Orders {
+			event.OpenOrders = append(event.OpenOrders, orderFromStringMap(o))
+		}
+	case "OrderRemove":
+		var removal struct {
+			Nonce *big.Int `json:"nonce"`
+		}
+		if err := json.Unmarshal(frame.Data, &removal); err != nil || removal.Nonce == nil {
+			return nil, errors.New("OrderRemove without nonce")
+		}
+		event.Nonce = removal.Nonce.String()
+	default:
+		var fields map[string]string
+		if err := json.Unmarshal(frame.Data, &fields); err != nil {
+			return nil, err
+		}
+		event.Order = orderFromStringMap(fields)
+		event.Nonce = fields["Nonce"]
+	}
+	return event, nil
+}
@@ -0,0 +1,207 @@
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math/big"
+	"os"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const serviceName = "dexbe"
+
+// Span attribute keys shared by every order span
+const (
+	AttrOrderID = attribute.Key("order.id")
+	AttrPair    = attribute.Key("order.pair")
+	AttrSource  = attribute.Key("order.source")
+	AttrTxHash  = attribute.Key("tx.hash")
+)
+
+var (
+	tracer  = otel.Tracer(serviceName)
+	enabled bool
+
+	// orders holds the lifecycle span of every order still in play, so work that happens long after
+	// submission (matching, settlement, fills) lands in the same trace
+	orders   = map[string]trace.Span{}
+	ordersMu sync.Mutex
+)
+
+// Setup installs the exporter named by mode: "file" appends spans as JSON to path, "otlp" sends
+// them to the OTLP/gRPC collector at OTEL_EXPORTER_OTLP_ENDPOINT. Tracing stays off when mode is
+// empty. The returned function flushes and stops the exporter.
+func Setup(ctx context.Context, mode, path string) (func(context.Context) error, error) {
+	var exporter sdktrace.SpanExporter
+	switch mode {
+	case "":
+		return func(context.Context) error { return nil }, nil
+	case "file":
+		file, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+		if err != nil {
+			return nil, err
+		}
+		exporter, err = stdouttrace.New(stdouttrace.WithWriter(file))
+		if err != nil {
+			file.Close()
+			return nil, err
+		}
+		log.Printf("Tracing orders to %s", path)
+	case "otlp":
+		var err error
+		exporter, err = otlptracegrpc.New(ctx)
+		if err != nil {
+			return nil, err
+		}
+		log.Printf("Tracing orders to OTLP collector")
+	default:
+		return nil, fmt.Errorf("unknown trace exporter %q, want file or otlp", mode)
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(resource.NewSchemaless(semconv.ServiceName(serviceName))),
+	)
+	otel.SetTracerProvider(provider)
+	enabled = true
+	return provider.Shutdown, nil
+}
+
+// OrderID identifies an order across spans the way the order book keys it: creator-nonce
+func OrderID(createdBy common.Address, nonce *big.Int) string {
+	if nonce == nil {
+		return createdBy.Hex() + "-"
+	}
+	return createdBy.Hex() + "-" + nonce.String()
+}
+
+// StartOrder opens the lifecycle span of an order, or finds the one already open, and returns a
+// context carrying it and whether it was opened by this call. The span stays open until EndOrder.
+func StartOrder(id string, attrs ...attribute.KeyValue) (context.Context, bool) {
+	if !enabled {
+		return context.Background(), false
+	}
+	ordersMu.Lock()
+	defer ordersMu.Unlock()
+	span, exists := orders[id]
+	if !exists {
+		_, span = tracer.Start(context.Background(), "order",
+			trace.WithNewRoot(),
+			trace.WithAttributes(append([]attribute.KeyValue{AttrOrderID.String(id)}, attrs...)...))
+		orders[id] = span
+	}
+	return trace.ContextWithSpan(context.Background(), span), !exists
+}
+
+// OrderContext returns a context carrying the order's lifecycle span, or a bare context if the
+// order is not being traced
+func OrderContext(id string) context.Context {
+	ordersMu.Lock()
+	defer ordersMu.Unlock()
+	if span, exists := orders[id]; exists {
+		return trace.ContextWithSpan(context.Background(), span)
+	}
+	return context.Background()
+}
+
+// OrderEvent notes something that happened to the order on its lifecycle span
+func OrderEvent(id, name string, attrs ...attribute.KeyValue) {
+	ordersMu.Lock()
+	defer ordersMu.Unlock()
+	if span, exists := orders[id]; exists {
+		span.AddEvent(name, trace.WithAttributes(attrs...))
+	}
+}
+
+// EndOrder closes the order's lifecycle span with its final state; err marks a rejected order
+func EndOrder(id, state string, err error) {
+	ordersMu.Lock()
+	span, exists := orders[id]
+	delete(orders, id)
+	ordersMu.Unlock()
+	if !exists {
+		return
+	}
+	span.SetAttributes(attribute.String("order.state", state))
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// Start opens a span under ctx
+func Start(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	return tracer.Start(ctx, name, trace.WithAttributes(attrs...))
+}
+
+// Fail records err on span and marks it failed
+func Fail(span trace.Span, err error) {
+	span.RecordError(err)
+	span.SetStatus(codes.Error, err.Error())
+}
+
+// OrderSpans is one step that involves several orders, such as a match or a ring. It opens a span
+// in each order's trace so every order's history is complete on its own.
+type OrderSpans struct {
+	ids   []string
+	ctxs  []context.Context
+	spans []trace.Span
+}
+
+func StartOrderSpans(name string, ids []string, attrs ...attribute.KeyValue) *OrderSpans {
+	parents := make([]context.Context, len(ids))
+	for i, id := range ids {
+		parents[i] = OrderContext(id)
+	}
+	return startAll(name, ids, parents, attrs)
+}
+
+func startAll(name string, ids []string, parents []context.Context, attrs []attribute.KeyValue) *OrderSpans {
+	spans := &OrderSpans{ids: ids}
+	if !enabled {
+		return spans
+	}
+	for i, parent := range parents {
+		ctx, span := tracer.Start(parent, name,
+			trace.WithAttributes(append([]attribute.KeyValue{AttrOrderID.String(ids[i])}, attrs...)...))
+		spans.ctxs = append(spans.ctxs, ctx)
+		spans.spans = append(spans.spans, span)
+	}
+	return spans
+}
+
+// Child opens a span under each of these spans
+func (s *OrderSpans) Child(name string, attrs ...attribute.KeyValue) *OrderSpans {
+	return startAll(name, s.ids, s.ctxs, attrs)
+}
+
+func (s *OrderSpans) SetAttributes(attrs ...attribute.KeyValue) {
+	for _, span := range s.spans {
+		span.SetAttributes(attrs...)
+	}
+}
+
+func (s *OrderSpans) Fail(err error) {
+	for _, span := range s.spans {
+		Fail(span, err)
+	}
+}
+
+func (s *OrderSpans) End() {
+	for _, span := range s.spans {
+		span.End()
+	}
+}
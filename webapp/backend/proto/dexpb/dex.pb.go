@@ -0,0 +1,1904 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.10
+// 	protoc        (unknown)
+// source: dex.proto
+
+package dexpb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type OrderStatus int32
+
+const (
+	OrderStatus_ORDER_STATUS_UNSPECIFIED          OrderStatus = 0
+	OrderStatus_ORDER_STATUS_MATCHING             OrderStatus = 1
+	OrderStatus_ORDER_STATUS_PENDING_CONFIRMATION OrderStatus = 2
+	OrderStatus_ORDER_STATUS_COMPLETED            OrderStatus = 3
+	OrderStatus_ORDER_STATUS_CANCELLED            OrderStatus = 4
+	OrderStatus_ORDER_STATUS_PARTIAL_FILL         OrderStatus = 5
+)
+
+// Enum value maps for OrderStatus.
+var (
+	OrderStatus_name = map[int32]string{
+		0: "ORDER_STATUS_UNSPECIFIED",
+		1: "ORDER_STATUS_MATCHING",
+		2: "ORDER_STATUS_PENDING_CONFIRMATION",
+		3: "ORDER_STATUS_COMPLETED",
+		4: "ORDER_STATUS_CANCELLED",
+		5: "ORDER_STATUS_PARTIAL_FILL",
+	}
+	OrderStatus_value = map[string]int32{
+		"ORDER_STATUS_UNSPECIFIED":          0,
+		"ORDER_STATUS_MATCHING":             1,
+		"ORDER_STATUS_PENDING_CONFIRMATION": 2,
+		"ORDER_STATUS_COMPLETED":            3,
+		"ORDER_STATUS_CANCELLED":            4,
+		"ORDER_STATUS_PARTIAL_FILL":         5,
+	}
+)
+
+func (x OrderStatus) Enum() *OrderStatus {
+	p := new(OrderStatus)
+	*p = x
+	return p
+}
+
+func (x OrderStatus) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (OrderStatus) Descriptor() protoreflect.EnumDescriptor {
+	return file_dex_proto_enumTypes[0].Descriptor()
+}
+
+func (OrderStatus) Type() protoreflect.EnumType {
+	return &file_dex_proto_enumTypes[0]
+}
+
+func (x OrderStatus) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use OrderStatus.Descriptor instead.
+func (OrderStatus) EnumDescriptor() ([]byte, []int) {
+	return file_dex_proto_rawDescGZIP(), []int{0}
+}
+
+type Side int32
+
+const (
+	Side_SIDE_UNSPECIFIED Side = 0
+	Side_SIDE_BUY         Side = 1
+	Side_SIDE_SELL        Side = 2
+)
+
+// Enum value maps for Side.
+var (
+	Side_name = map[int32]string{
+		0: "SIDE_UNSPECIFIED",
+		1: "SIDE_BUY",
+		2: "SIDE_SELL",
+	}
+	Side_value = map[string]int32{
+		"SIDE_UNSPECIFIED": 0,
+		"SIDE_BUY":         1,
+		"SIDE_SELL":        2,
+	}
+)
+
+func (x Side) Enum() *Side {
+	p := new(Side)
+	*p = x
+	return p
+}
+
+func (x Side) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (Side) Descriptor() protoreflect.EnumDescriptor {
+	return file_dex_proto_enumTypes[1].Descriptor()
+}
+
+func (Side) Type() protoreflect.EnumType {
+	return &file_dex_proto_enumTypes[1]
+}
+
+func (x Side) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use Side.Descriptor instead.
+func (Side) EnumDescriptor() ([]byte, []int) {
+	return file_dex_proto_rawDescGZIP(), []int{1}
+}
+
+// SignedOrder is the EIP-712 Order exactly as signed
+type SignedOrder struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	CreatedBy     string                 `protobuf:"bytes,1,opt,name=created_by,json=createdBy,proto3" json:"created_by,omitempty"`
+	SymbolIn      string                 `protobuf:"bytes,2,opt,name=symbol_in,json=symbolIn,proto3" json:"symbol_in,omitempty"`
+	SymbolOut     string                 `protobuf:"bytes,3,opt,name=symbol_out,json=symbolOut,proto3" json:"symbol_out,omitempty"`
+	AmtIn         string                 `protobuf:"bytes,4,opt,name=amt_in,json=amtIn,proto3" json:"amt_in,omitempty"`
+	AmtOut        string                 `protobuf:"bytes,5,opt,name=amt_out,json=amtOut,proto3" json:"amt_out,omitempty"`
+	Nonce         string                 `protobuf:"bytes,6,opt,name=nonce,proto3" json:"nonce,omitempty"`
+	Signature     []byte                 `protobuf:"bytes,7,opt,name=signature,proto3" json:"signature,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SignedOrder) Reset() {
+	*x = SignedOrder{}
+	mi := &file_dex_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SignedOrder) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SignedOrder) ProtoMessage() {}
+
+func (x *SignedOrder) ProtoReflect() protoreflect.Message {
+	mi := &file_dex_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SignedOrder.ProtoReflect.Descriptor instead.
+func (*SignedOrder) Descriptor() ([]byte, []int) {
+	return file_dex_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *SignedOrder) GetCreatedBy() string {
+	if x != nil {
+		return x.CreatedBy
+	}
+	return ""
+}
+
+func (x *SignedOrder) GetSymbolIn() string {
+	if x != nil {
+		return x.SymbolIn
+	}
+	return ""
+}
+
+func (x *SignedOrder) GetSymbolOut() string {
+	if x != nil {
+		return x.SymbolOut
+	}
+	return ""
+}
+
+func (x *SignedOrder) GetAmtIn() string {
+	if x != nil {
+		return x.AmtIn
+	}
+	return ""
+}
+
+func (x *SignedOrder) GetAmtOut() string {
+	if x != nil {
+		return x.AmtOut
+	}
+	return ""
+}
+
+func (x *SignedOrder) GetNonce() string {
+	if x != nil {
+		return x.Nonce
+	}
+	return ""
+}
+
+func (x *SignedOrder) GetSignature() []byte {
+	if x != nil {
+		return x.Signature
+	}
+	return nil
+}
+
+type Order struct {
+	state             protoimpl.MessageState `protogen:"open.v1"`
+	CreatedBy         string                 `protobuf:"bytes,1,opt,name=created_by,json=createdBy,proto3" json:"created_by,omitempty"`
+	SymbolIn          string                 `protobuf:"bytes,2,opt,name=symbol_in,json=symbolIn,proto3" json:"symbol_in,omitempty"`
+	SymbolOut         string                 `protobuf:"bytes,3,opt,name=symbol_out,json=symbolOut,proto3" json:"symbol_out,omitempty"`
+	AmtIn             string                 `protobuf:"bytes,4,opt,name=amt_in,json=amtIn,proto3" json:"amt_in,omitempty"`
+	AmtOut            string                 `protobuf:"bytes,5,opt,name=amt_out,json=amtOut,proto3" json:"amt_out,omitempty"`
+	Nonce             string                 `protobuf:"bytes,6,opt,name=nonce,proto3" json:"nonce,omitempty"`
+	Signature         []byte                 `protobuf:"bytes,7,opt,name=signature,proto3" json:"signature,omitempty"`
+	LimitPrice        string                 `protobuf:"bytes,8,opt,name=limit_price,json=limitPrice,proto3" json:"limit_price,omitempty"`
+	TriggerPrice      string                 `protobuf:"bytes,9,opt,name=trigger_price,json=triggerPrice,proto3" json:"trigger_price,omitempty"`
+	FilledAmtIn       string                 `protobuf:"bytes,10,opt,name=filled_amt_in,json=filledAmtIn,proto3" json:"filled_amt_in,omitempty"`
+	Status            OrderStatus            `protobuf:"varint,11,opt,name=status,proto3,enum=dex.v1.OrderStatus" json:"status,omitempty"`
+	ConditionalOrder  *Order                 `protobuf:"bytes,12,opt,name=conditional_order,json=conditionalOrder,proto3" json:"conditional_order,omitempty"`
+	TransactionHashes []string               `protobuf:"bytes,13,rep,name=transaction_hashes,json=transactionHashes,proto3" json:"transaction_hashes,omitempty"`
+	CancelSignature   []byte                 `protobuf:"bytes,14,opt,name=cancel_signature,json=cancelSignature,proto3" json:"cancel_signature,omitempty"`
+	CreatedAt         *timestamppb.Timestamp `protobuf:"bytes,15,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
+}
+
+func (x *Order) Reset() {
+	*x = Order{}
+	mi := &file_dex_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Order) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Order) ProtoMessage() {}
+
+func (x *Order) ProtoReflect() protoreflect.Message {
+	mi := &file_dex_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Order.ProtoReflect.Descriptor instead.
+func (*Order) Descriptor() ([]byte, []int) {
+	return file_dex_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *Order) GetCreatedBy() string {
+	if x != nil {
+		return x.CreatedBy
+	}
+	return ""
+}
+
+func (x *Order) GetSymbolIn() string {
+	if x != nil {
+		return x.SymbolIn
+	}
+	return ""
+}
+
+func (x *Order) GetSymbolOut() string {
+	if x != nil {
+		return x.SymbolOut
+	}
+	return ""
+}
+
+func (x *Order) GetAmtIn() string {
+	if x != nil {
+		return x.AmtIn
+	}
+	return ""
+}
+
+func (x *Order) GetAmtOut() string {
+	if x != nil {
+		return x.AmtOut
+	}
+	return ""
+}
+
+func (x *Order) GetNonce() string {
+	if x != nil {
+		return x.Nonce
+	}
+	return ""
+}
+
+func (x *Order) GetSignature() []byte {
+	if x != nil {
+		return x.Signature
+	}
+	return nil
+}
+
+func (x *Order) GetLimitPrice() string {
+	if x != nil {
+		return x.LimitPrice
+	}
+	return ""
+}
+
+func (x *Order) GetTriggerPrice() string {
+	if x != nil {
+		return x.TriggerPrice
+	}
+	return ""
+}
+
+func (x *Order) GetFilledAmtIn() string {
+	if x != nil {
+		return x.FilledAmtIn
+	}
+	return ""
+}
+
+func (x *Order) GetStatus() OrderStatus {
+	if x != nil {
+		return x.Status
+	}
+	return OrderStatus_ORDER_STATUS_UNSPECIFIED
+}
+
+func (x *Order) GetConditionalOrder() *Order {
+	if x != nil {
+		return x.ConditionalOrder
+	}
+	return nil
+}
+
+func (x *Order) GetTransactionHashes() []string {
+	if x != nil {
+		return x.TransactionHashes
+	}
+	return nil
+}
+
+func (x *Order) GetCancelSignature() []byte {
+	if x != nil {
+		return x.CancelSignature
+	}
+	return nil
+}
+
+func (x *Order) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+type SubmitOrderRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Order         *SignedOrder           `protobuf:"bytes,1,opt,name=order,proto3" json:"order,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SubmitOrderRequest) Reset() {
+	*x = SubmitOrderRequest{}
+	mi := &file_dex_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SubmitOrderRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SubmitOrderRequest) ProtoMessage() {}
+
+func (x *SubmitOrderRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_dex_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SubmitOrderRequest.ProtoReflect.Descriptor instead.
+func (*SubmitOrderRequest) Descriptor() ([]byte, []int) {
+	return file_dex_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *SubmitOrderRequest) GetOrder() *SignedOrder {
+	if x != nil {
+		return x.Order
+	}
+	return nil
+}
+
+type SubmitOrderResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Order         *Order                 `protobuf:"bytes,1,opt,name=order,proto3" json:"order,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SubmitOrderResponse) Reset() {
+	*x = SubmitOrderResponse{}
+	mi := &file_dex_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SubmitOrderResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SubmitOrderResponse) ProtoMessage() {}
+
+func (x *SubmitOrderResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_dex_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SubmitOrderResponse.ProtoReflect.Descriptor instead.
+func (*SubmitOrderResponse) Descriptor() ([]byte, []int) {
+	return file_dex_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *SubmitOrderResponse) GetOrder() *Order {
+	if x != nil {
+		return x.Order
+	}
+	return nil
+}
+
+type CancelOrderRequest struct {
+	state      protoimpl.MessageState `protogen:"open.v1"`
+	CreatedBy  string                 `protobuf:"bytes,1,opt,name=created_by,json=createdBy,proto3" json:"created_by,omitempty"`
+	Nonce      string                 `protobuf:"bytes,2,opt,name=nonce,proto3" json:"nonce,omitempty"`
+	LimitPrice string                 `protobuf:"bytes,3,opt,name=limit_price,json=limitPrice,proto3" json:"limit_price,omitempty"`
+	SymbolIn   string                 `protobuf:"bytes,4,opt,name=symbol_in,json=symbolIn,proto3" json:"symbol_in,omitempty"`
+	SymbolOut  string                 `protobuf:"bytes,5,opt,name=symbol_out,json=symbolOut,proto3" json:"symbol_out,omitempty"`
+	// EIP-712 Cancel(createdBy, nonce) signed by the creator
+	Signature     []byte `protobuf:"bytes,6,opt,name=signature,proto3" json:"signature,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CancelOrderRequest) Reset() {
+	*x = CancelOrderRequest{}
+	mi := &file_dex_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CancelOrderRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CancelOrderRequest) ProtoMessage() {}
+
+func (x *CancelOrderRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_dex_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CancelOrderRequest.ProtoReflect.Descriptor instead.
+func (*CancelOrderRequest) Descriptor() ([]byte, []int) {
+	return file_dex_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *CancelOrderRequest) GetCreatedBy() string {
+	if x != nil {
+		return x.CreatedBy
+	}
+	return ""
+}
+
+func (x *CancelOrderRequest) GetNonce() string {
+	if x != nil {
+		return x.Nonce
+	}
+	return ""
+}
+
+func (x *CancelOrderRequest) GetLimitPrice() string {
+	if x != nil {
+		return x.LimitPrice
+	}
+	return ""
+}
+
+func (x *CancelOrderRequest) GetSymbolIn() string {
+	if x != nil {
+		return x.SymbolIn
+	}
+	return ""
+}
+
+func (x *CancelOrderRequest) GetSymbolOut() string {
+	if x != nil {
+		return x.SymbolOut
+	}
+	return ""
+}
+
+func (x *CancelOrderRequest) GetSignature() []byte {
+	if x != nil {
+		return x.Signature
+	}
+	return nil
+}
+
+type CancelOrderResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CancelOrderResponse) Reset() {
+	*x = CancelOrderResponse{}
+	mi := &file_dex_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CancelOrderResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CancelOrderResponse) ProtoMessage() {}
+
+func (x *CancelOrderResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_dex_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CancelOrderResponse.ProtoReflect.Descriptor instead.
+func (*CancelOrderResponse) Descriptor() ([]byte, []int) {
+	return file_dex_proto_rawDescGZIP(), []int{5}
+}
+
+type GetOpenOrdersRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Both or neither: limits the result to one pair
+	SymbolIn      string `protobuf:"bytes,1,opt,name=symbol_in,json=symbolIn,proto3" json:"symbol_in,omitempty"`
+	SymbolOut     string `protobuf:"bytes,2,opt,name=symbol_out,json=symbolOut,proto3" json:"symbol_out,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetOpenOrdersRequest) Reset() {
+	*x = GetOpenOrdersRequest{}
+	mi := &file_dex_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetOpenOrdersRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetOpenOrdersRequest) ProtoMessage() {}
+
+func (x *GetOpenOrdersRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_dex_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetOpenOrdersRequest.ProtoReflect.Descriptor instead.
+func (*GetOpenOrdersRequest) Descriptor() ([]byte, []int) {
+	return file_dex_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *GetOpenOrdersRequest) GetSymbolIn() string {
+	if x != nil {
+		return x.SymbolIn
+	}
+	return ""
+}
+
+func (x *GetOpenOrdersRequest) GetSymbolOut() string {
+	if x != nil {
+		return x.SymbolOut
+	}
+	return ""
+}
+
+type GetOpenOrdersResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Orders        []*Order               `protobuf:"bytes,1,rep,name=orders,proto3" json:"orders,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetOpenOrdersResponse) Reset() {
+	*x = GetOpenOrdersResponse{}
+	mi := &file_dex_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetOpenOrdersResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetOpenOrdersResponse) ProtoMessage() {}
+
+func (x *GetOpenOrdersResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_dex_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetOpenOrdersResponse.ProtoReflect.Descriptor instead.
+func (*GetOpenOrdersResponse) Descriptor() ([]byte, []int) {
+	return file_dex_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *GetOpenOrdersResponse) GetOrders() []*Order {
+	if x != nil {
+		return x.Orders
+	}
+	return nil
+}
+
+type GetOrderHistoryRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Limits the result to one order
+	Nonce         string `protobuf:"bytes,1,opt,name=nonce,proto3" json:"nonce,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetOrderHistoryRequest) Reset() {
+	*x = GetOrderHistoryRequest{}
+	mi := &file_dex_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetOrderHistoryRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetOrderHistoryRequest) ProtoMessage() {}
+
+func (x *GetOrderHistoryRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_dex_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetOrderHistoryRequest.ProtoReflect.Descriptor instead.
+func (*GetOrderHistoryRequest) Descriptor() ([]byte, []int) {
+	return file_dex_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *GetOrderHistoryRequest) GetNonce() string {
+	if x != nil {
+		return x.Nonce
+	}
+	return ""
+}
+
+type GetOrderHistoryResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Oldest first within each order
+	Orders        []*Order `protobuf:"bytes,1,rep,name=orders,proto3" json:"orders,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetOrderHistoryResponse) Reset() {
+	*x = GetOrderHistoryResponse{}
+	mi := &file_dex_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetOrderHistoryResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetOrderHistoryResponse) ProtoMessage() {}
+
+func (x *GetOrderHistoryResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_dex_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetOrderHistoryResponse.ProtoReflect.Descriptor instead.
+func (*GetOrderHistoryResponse) Descriptor() ([]byte, []int) {
+	return file_dex_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *GetOrderHistoryResponse) GetOrders() []*Order {
+	if x != nil {
+		return x.Orders
+	}
+	return nil
+}
+
+type Token struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Symbol        string                 `protobuf:"bytes,2,opt,name=symbol,proto3" json:"symbol,omitempty"`
+	Address       string                 `protobuf:"bytes,3,opt,name=address,proto3" json:"address,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Token) Reset() {
+	*x = Token{}
+	mi := &file_dex_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Token) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Token) ProtoMessage() {}
+
+func (x *Token) ProtoReflect() protoreflect.Message {
+	mi := &file_dex_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Token.ProtoReflect.Descriptor instead.
+func (*Token) Descriptor() ([]byte, []int) {
+	return file_dex_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *Token) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Token) GetSymbol() string {
+	if x != nil {
+		return x.Symbol
+	}
+	return ""
+}
+
+func (x *Token) GetAddress() string {
+	if x != nil {
+		return x.Address
+	}
+	return ""
+}
+
+type ListTokensRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListTokensRequest) Reset() {
+	*x = ListTokensRequest{}
+	mi := &file_dex_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListTokensRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListTokensRequest) ProtoMessage() {}
+
+func (x *ListTokensRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_dex_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListTokensRequest.ProtoReflect.Descriptor instead.
+func (*ListTokensRequest) Descriptor() ([]byte, []int) {
+	return file_dex_proto_rawDescGZIP(), []int{11}
+}
+
+type ListTokensResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Tokens        []*Token               `protobuf:"bytes,1,rep,name=tokens,proto3" json:"tokens,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListTokensResponse) Reset() {
+	*x = ListTokensResponse{}
+	mi := &file_dex_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListTokensResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListTokensResponse) ProtoMessage() {}
+
+func (x *ListTokensResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_dex_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListTokensResponse.ProtoReflect.Descriptor instead.
+func (*ListTokensResponse) Descriptor() ([]byte, []int) {
+	return file_dex_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *ListTokensResponse) GetTokens() []*Token {
+	if x != nil {
+		return x.Tokens
+	}
+	return nil
+}
+
+type Pair struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// "BASE/QUOTE"
+	Id            string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Base          string `protobuf:"bytes,2,opt,name=base,proto3" json:"base,omitempty"`
+	Quote         string `protobuf:"bytes,3,opt,name=quote,proto3" json:"quote,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Pair) Reset() {
+	*x = Pair{}
+	mi := &file_dex_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Pair) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Pair) ProtoMessage() {}
+
+func (x *Pair) ProtoReflect() protoreflect.Message {
+	mi := &file_dex_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Pair.ProtoReflect.Descriptor instead.
+func (*Pair) Descriptor() ([]byte, []int) {
+	return file_dex_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *Pair) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Pair) GetBase() string {
+	if x != nil {
+		return x.Base
+	}
+	return ""
+}
+
+func (x *Pair) GetQuote() string {
+	if x != nil {
+		return x.Quote
+	}
+	return ""
+}
+
+type ListPairsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListPairsRequest) Reset() {
+	*x = ListPairsRequest{}
+	mi := &file_dex_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListPairsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListPairsRequest) ProtoMessage() {}
+
+func (x *ListPairsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_dex_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListPairsRequest.ProtoReflect.Descriptor instead.
+func (*ListPairsRequest) Descriptor() ([]byte, []int) {
+	return file_dex_proto_rawDescGZIP(), []int{14}
+}
+
+type ListPairsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Pairs         []*Pair                `protobuf:"bytes,1,rep,name=pairs,proto3" json:"pairs,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListPairsResponse) Reset() {
+	*x = ListPairsResponse{}
+	mi := &file_dex_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListPairsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListPairsResponse) ProtoMessage() {}
+
+func (x *ListPairsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_dex_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListPairsResponse.ProtoReflect.Descriptor instead.
+func (*ListPairsResponse) Descriptor() ([]byte, []int) {
+	return file_dex_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *ListPairsResponse) GetPairs() []*Pair {
+	if x != nil {
+		return x.Pairs
+	}
+	return nil
+}
+
+type StreamBookRequest struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	SymbolIn  string                 `protobuf:"bytes,1,opt,name=symbol_in,json=symbolIn,proto3" json:"symbol_in,omitempty"`
+	SymbolOut string                 `protobuf:"bytes,2,opt,name=symbol_out,json=symbolOut,proto3" json:"symbol_out,omitempty"`
+	// Price increment to group levels by, such as "0.01"; empty for raw levels
+	Group string `protobuf:"bytes,3,opt,name=group,proto3" json:"group,omitempty"`
+	// Levels per side, 0 for all
+	Depth         uint32 `protobuf:"varint,4,opt,name=depth,proto3" json:"depth,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StreamBookRequest) Reset() {
+	*x = StreamBookRequest{}
+	mi := &file_dex_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StreamBookRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StreamBookRequest) ProtoMessage() {}
+
+func (x *StreamBookRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_dex_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StreamBookRequest.ProtoReflect.Descriptor instead.
+func (*StreamBookRequest) Descriptor() ([]byte, []int) {
+	return file_dex_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *StreamBookRequest) GetSymbolIn() string {
+	if x != nil {
+		return x.SymbolIn
+	}
+	return ""
+}
+
+func (x *StreamBookRequest) GetSymbolOut() string {
+	if x != nil {
+		return x.SymbolOut
+	}
+	return ""
+}
+
+func (x *StreamBookRequest) GetGroup() string {
+	if x != nil {
+		return x.Group
+	}
+	return ""
+}
+
+func (x *StreamBookRequest) GetDepth() uint32 {
+	if x != nil {
+		return x.Depth
+	}
+	return 0
+}
+
+type DepthLevel struct {
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	PriceRaw string                 `protobuf:"bytes,1,opt,name=price_raw,json=priceRaw,proto3" json:"price_raw,omitempty"`
+	Price    float64                `protobuf:"fixed64,2,opt,name=price,proto3" json:"price,omitempty"`
+	// "0" in a delta: the level is gone
+	Quantity      string `protobuf:"bytes,3,opt,name=quantity,proto3" json:"quantity,omitempty"`
+	OrderCount    uint32 `protobuf:"varint,4,opt,name=order_count,json=orderCount,proto3" json:"order_count,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DepthLevel) Reset() {
+	*x = DepthLevel{}
+	mi := &file_dex_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DepthLevel) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DepthLevel) ProtoMessage() {}
+
+func (x *DepthLevel) ProtoReflect() protoreflect.Message {
+	mi := &file_dex_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DepthLevel.ProtoReflect.Descriptor instead.
+func (*DepthLevel) Descriptor() ([]byte, []int) {
+	return file_dex_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *DepthLevel) GetPriceRaw() string {
+	if x != nil {
+		return x.PriceRaw
+	}
+	return ""
+}
+
+func (x *DepthLevel) GetPrice() float64 {
+	if x != nil {
+		return x.Price
+	}
+	return 0
+}
+
+func (x *DepthLevel) GetQuantity() string {
+	if x != nil {
+		return x.Quantity
+	}
+	return ""
+}
+
+func (x *DepthLevel) GetOrderCount() uint32 {
+	if x != nil {
+		return x.OrderCount
+	}
+	return 0
+}
+
+type BookUpdate struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Pair  string                 `protobuf:"bytes,1,opt,name=pair,proto3" json:"pair,omitempty"`
+	// True for the full book as of seq, false for the levels that changed since prev_seq
+	Snapshot      bool          `protobuf:"varint,2,opt,name=snapshot,proto3" json:"snapshot,omitempty"`
+	Seq           uint64        `protobuf:"varint,3,opt,name=seq,proto3" json:"seq,omitempty"`
+	PrevSeq       uint64        `protobuf:"varint,4,opt,name=prev_seq,json=prevSeq,proto3" json:"prev_seq,omitempty"`
+	Bids          []*DepthLevel `protobuf:"bytes,5,rep,name=bids,proto3" json:"bids,omitempty"`
+	Asks          []*DepthLevel `protobuf:"bytes,6,rep,name=asks,proto3" json:"asks,omitempty"`
+	LastPrice     float64       `protobuf:"fixed64,7,opt,name=last_price,json=lastPrice,proto3" json:"last_price,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BookUpdate) Reset() {
+	*x = BookUpdate{}
+	mi := &file_dex_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BookUpdate) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BookUpdate) ProtoMessage() {}
+
+func (x *BookUpdate) ProtoReflect() protoreflect.Message {
+	mi := &file_dex_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BookUpdate.ProtoReflect.Descriptor instead.
+func (*BookUpdate) Descriptor() ([]byte, []int) {
+	return file_dex_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *BookUpdate) GetPair() string {
+	if x != nil {
+		return x.Pair
+	}
+	return ""
+}
+
+func (x *BookUpdate) GetSnapshot() bool {
+	if x != nil {
+		return x.Snapshot
+	}
+	return false
+}
+
+func (x *BookUpdate) GetSeq() uint64 {
+	if x != nil {
+		return x.Seq
+	}
+	return 0
+}
+
+func (x *BookUpdate) GetPrevSeq() uint64 {
+	if x != nil {
+		return x.PrevSeq
+	}
+	return 0
+}
+
+func (x *BookUpdate) GetBids() []*DepthLevel {
+	if x != nil {
+		return x.Bids
+	}
+	return nil
+}
+
+func (x *BookUpdate) GetAsks() []*DepthLevel {
+	if x != nil {
+		return x.Asks
+	}
+	return nil
+}
+
+func (x *BookUpdate) GetLastPrice() float64 {
+	if x != nil {
+		return x.LastPrice
+	}
+	return 0
+}
+
+type StreamTradesRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Both or neither
+	SymbolIn      string `protobuf:"bytes,1,opt,name=symbol_in,json=symbolIn,proto3" json:"symbol_in,omitempty"`
+	SymbolOut     string `protobuf:"bytes,2,opt,name=symbol_out,json=symbolOut,proto3" json:"symbol_out,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StreamTradesRequest) Reset() {
+	*x = StreamTradesRequest{}
+	mi := &file_dex_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StreamTradesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StreamTradesRequest) ProtoMessage() {}
+
+func (x *StreamTradesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_dex_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StreamTradesRequest.ProtoReflect.Descriptor instead.
+func (*StreamTradesRequest) Descriptor() ([]byte, []int) {
+	return file_dex_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *StreamTradesRequest) GetSymbolIn() string {
+	if x != nil {
+		return x.SymbolIn
+	}
+	return ""
+}
+
+func (x *StreamTradesRequest) GetSymbolOut() string {
+	if x != nil {
+		return x.SymbolOut
+	}
+	return ""
+}
+
+type Trade struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            uint64                 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Pair          string                 `protobuf:"bytes,2,opt,name=pair,proto3" json:"pair,omitempty"`
+	Base          string                 `protobuf:"bytes,3,opt,name=base,proto3" json:"base,omitempty"`
+	Quote         string                 `protobuf:"bytes,4,opt,name=quote,proto3" json:"quote,omitempty"`
+	Price         string                 `protobuf:"bytes,5,opt,name=price,proto3" json:"price,omitempty"`
+	BaseQty       string                 `protobuf:"bytes,6,opt,name=base_qty,json=baseQty,proto3" json:"base_qty,omitempty"`
+	QuoteQty      string                 `protobuf:"bytes,7,opt,name=quote_qty,json=quoteQty,proto3" json:"quote_qty,omitempty"`
+	AggressorSide Side                   `protobuf:"varint,8,opt,name=aggressor_side,json=aggressorSide,proto3,enum=dex.v1.Side" json:"aggressor_side,omitempty"`
+	Maker         string                 `protobuf:"bytes,9,opt,name=maker,proto3" json:"maker,omitempty"`
+	MakerNonce    string                 `protobuf:"bytes,10,opt,name=maker_nonce,json=makerNonce,proto3" json:"maker_nonce,omitempty"`
+	Taker         string                 `protobuf:"bytes,11,opt,name=taker,proto3" json:"taker,omitempty"`
+	TakerNonce    string                 `protobuf:"bytes,12,opt,name=taker_nonce,json=takerNonce,proto3" json:"taker_nonce,omitempty"`
+	RingSize      uint32                 `protobuf:"varint,13,opt,name=ring_size,json=ringSize,proto3" json:"ring_size,omitempty"`
+	TxHash        string                 `protobuf:"bytes,14,opt,name=tx_hash,json=txHash,proto3" json:"tx_hash,omitempty"`
+	Timestamp     *timestamppb.Timestamp `protobuf:"bytes,15,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Trade) Reset() {
+	*x = Trade{}
+	mi := &file_dex_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Trade) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Trade) ProtoMessage() {}
+
+func (x *Trade) ProtoReflect() protoreflect.Message {
+	mi := &file_dex_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Trade.ProtoReflect.Descriptor instead.
+func (*Trade) Descriptor() ([]byte, []int) {
+	return file_dex_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *Trade) GetId() uint64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *Trade) GetPair() string {
+	if x != nil {
+		return x.Pair
+	}
+	return ""
+}
+
+func (x *Trade) GetBase() string {
+	if x != nil {
+		return x.Base
+	}
+	return ""
+}
+
+func (x *Trade) GetQuote() string {
+	if x != nil {
+		return x.Quote
+	}
+	return ""
+}
+
+func (x *Trade) GetPrice() string {
+	if x != nil {
+		return x.Price
+	}
+	return ""
+}
+
+func (x *Trade) GetBaseQty() string {
+	if x != nil {
+		return x.BaseQty
+	}
+	return ""
+}
+
+func (x *Trade) GetQuoteQty() string {
+	if x != nil {
+		return x.QuoteQty
+	}
+	return ""
+}
+
+func (x *Trade) GetAggressorSide() Side {
+	if x != nil {
+		return x.AggressorSide
+	}
+	return Side_SIDE_UNSPECIFIED
+}
+
+func (x *Trade) GetMaker() string {
+	if x != nil {
+		return x.Maker
+	}
+	return ""
+}
+
+func (x *Trade) GetMakerNonce() string {
+	if x != nil {
+		return x.MakerNonce
+	}
+	return ""
+}
+
+func (x *Trade) GetTaker() string {
+	if x != nil {
+		return x.Taker
+	}
+	return ""
+}
+
+func (x *Trade) GetTakerNonce() string {
+	if x != nil {
+		return x.TakerNonce
+	}
+	return ""
+}
+
+func (x *Trade) GetRingSize() uint32 {
+	if x != nil {
+		return x.RingSize
+	}
+	return 0
+}
+
+func (x *Trade) GetTxHash() string {
+	if x != nil {
+		return x.TxHash
+	}
+	return ""
+}
+
+func (x *Trade) GetTimestamp() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Timestamp
+	}
+	return nil
+}
+
+type StreamOrderEventsRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The epoch and seq of the last event seen, to get the missed ones first
+	Epoch         string  `protobuf:"bytes,1,opt,name=epoch,proto3" json:"epoch,omitempty"`
+	Since         *uint64 `protobuf:"varint,2,opt,name=since,proto3,oneof" json:"since,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StreamOrderEventsRequest) Reset() {
+	*x = StreamOrderEventsRequest{}
+	mi := &file_dex_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StreamOrderEventsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StreamOrderEventsRequest) ProtoMessage() {}
+
+func (x *StreamOrderEventsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_dex_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StreamOrderEventsRequest.ProtoReflect.Descriptor instead.
+func (*StreamOrderEventsRequest) Descriptor() ([]byte, []int) {
+	return file_dex_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *StreamOrderEventsRequest) GetEpoch() string {
+	if x != nil {
+		return x.Epoch
+	}
+	return ""
+}
+
+func (x *StreamOrderEventsRequest) GetSince() uint64 {
+	if x != nil && x.Since != nil {
+		return *x.Since
+	}
+	return 0
+}
+
+// OrderEvent is one /ws event. "Subscribed" and "Resync" come first and give the epoch and the seq
+// the stream continues after; "Resync" also lists every open order. "OrderAdd" and
+// "TransactionChange" carry the order's full state, "OrderRemove" the nonce of a cancelled order.
+type OrderEvent struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Event         string                 `protobuf:"bytes,1,opt,name=event,proto3" json:"event,omitempty"`
+	Seq           uint64                 `protobuf:"varint,2,opt,name=seq,proto3" json:"seq,omitempty"`
+	Epoch         string                 `protobuf:"bytes,3,opt,name=epoch,proto3" json:"epoch,omitempty"`
+	Order         *Order                 `protobuf:"bytes,4,opt,name=order,proto3" json:"order,omitempty"`
+	Nonce         string                 `protobuf:"bytes,5,opt,name=nonce,proto3" json:"nonce,omitempty"`
+	OpenOrders    []*Order               `protobuf:"bytes,6,rep,name=open_orders,json=openOrders,proto3" json:"open_orders,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *OrderEvent) Reset() {
+	*x = OrderEvent{}
+	mi := &file_dex_proto_msgTypes[22]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *OrderEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*OrderEvent) ProtoMessage() {}
+
+func (x *OrderEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_dex_proto_msgTypes[22]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use OrderEvent.ProtoReflect.Descriptor instead.
+func (*OrderEvent) Descriptor() ([]byte, []int) {
+	return file_dex_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *OrderEvent) GetEvent() string {
+	if x != nil {
+		return x.Event
+	}
+	return ""
+}
+
+func (x *OrderEvent) GetSeq() uint64 {
+	if x != nil {
+		return x.Seq
+	}
+	return 0
+}
+
+func (x *OrderEvent) GetEpoch() string {
+	if x != nil {
+		return x.Epoch
+	}
+	return ""
+}
+
+func (x *OrderEvent) GetOrder() *Order {
+	if x != nil {
+		return x.Order
+	}
+	return nil
+}
+
+func (x *OrderEvent) GetNonce() string {
+	if x != nil {
+		return x.Nonce
+	}
+	return ""
+}
+
+func (x *OrderEvent) GetOpenOrders() []*Order {
+	if x != nil {
+		return x.OpenOrders
+	}
+	return nil
+}
+
+var File_dex_proto protoreflect.FileDescriptor
+
+const file_dex_proto_rawDesc = "" +
+	"\n" +
+	"\tdex.proto\x12\x06dex.v1\x1a\x1fgoogle/protobuf/timestamp.proto\"\xcc\x01\n" +
+	"\vSignedOrder\x12\x1d\n" +
+	"\n" +
+	"created_by\x18\x01 \x01(\tR\tcreatedBy\x12\x1b\n" +
+	"\tsymbol_in\x18\x02 \x01(\tR\bsymbolIn\x12\x1d\n" +
+	"\n" +
+	"symbol_out\x18\x03 \x01(\tR\tsymbolOut\x12\x15\n" +
+	"\x06amt_in\x18\x04 \x01(\tR\x05amtIn\x12\x17\n" +
+	"\aamt_out\x18\x05 \x01(\tR\x06amtOut\x12\x14\n" +
+	"\x05nonce\x18\x06 \x01(\tR\x05nonce\x12\x1c\n" +
+	"\tsignature\x18\a \x01(\fR\tsignature\"\xae\x04\n" +
+	"\x05Order\x12\x1d\n" +
+	"\n" +
+	"created_by\x18\x01 \x01(\tR\tcreatedBy\x12\x1b\n" +
+	"\tsymbol_in\x18\x02 \x01(\tR\bsymbolIn\x12\x1d\n" +
+	"\n" +
+	"symbol_out\x18\x03 \x01(\tR\tsymbolOut\x12\x15\n" +
+	"\x06amt_in\x18\x04 \x01(\tR\x05amtIn\x12\x17\n" +
+	"\aamt_out\x18\x05 \x01(\tR\x06amtOut\x12\x14\n" +
+	"\x05nonce\x18\x06 \x01(\tR\x05nonce\x12\x1c\n" +
+	"\tsignature\x18\a \x01(\fR\tsignature\x12\x1f\n" +
+	"\vlimit_price\x18\b \x01(\tR\n" +
+	"limitPrice\x12#\n" +
+	"\rtrigger_price\x18\t \x01(\tR\ftriggerPrice\x12\"\n" +
+	"\rfilled_amt_in\x18\n" +
+	" \x01(\tR\vfilledAmtIn\x12+\n" +
+	"\x06status\x18\v \x01(\x0e2\x13.dex.v1.OrderStatusR\x06status\x12:\n" +
+	"\x11conditional_order\x18\f \x01(\v2\r.dex.v1.OrderR\x10conditionalOrder\x12-\n" +
+	"\x12transaction_hashes\x18\r \x03(\tR\x11transactionHashes\x12)\n" +
+	"\x10cancel_signature\x18\x0e \x01(\fR\x0fcancelSignature\x129\n" +
+	"\n" +
+	"created_at\x18\x0f \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\"?\n" +
+	"\x12SubmitOrderRequest\x12)\n" +
+	"\x05order\x18\x01 \x01(\v2\x13.dex.v1.SignedOrderR\x05order\":\n" +
+	"\x13SubmitOrderResponse\x12#\n" +
+	"\x05order\x18\x01 \x01(\v2\r.dex.v1.OrderR\x05order\"\xc4\x01\n" +
+	"\x12CancelOrderRequest\x12\x1d\n" +
+	"\n" +
+	"created_by\x18\x01 \x01(\tR\tcreatedBy\x12\x14\n" +
+	"\x05nonce\x18\x02 \x01(\tR\x05nonce\x12\x1f\n" +
+	"\vlimit_price\x18\x03 \x01(\tR\n" +
+	"limitPrice\x12\x1b\n" +
+	"\tsymbol_in\x18\x04 \x01(\tR\bsymbolIn\x12\x1d\n" +
+	"\n" +
+	"symbol_out\x18\x05 \x01(\tR\tsymbolOut\x12\x1c\n" +
+	"\tsignature\x18\x06 \x01(\fR\tsignature\"\x15\n" +
+	"\x13CancelOrderResponse\"R\n" +
+	"\x14GetOpenOrdersRequest\x12\x1b\n" +
+	"\tsymbol_in\x18\x01 \x01(\tR\bsymbolIn\x12\x1d\n" +
+	"\n" +
+	"symbol_out\x18\x02 \x01(\tR\tsymbolOut\">\n" +
+	"\x15GetOpenOrdersResponse\x12%\n" +
+	"\x06orders\x18\x01 \x03(\v2\r.dex.v1.OrderR\x06orders\".\n" +
+	"\x16GetOrderHistoryRequest\x12\x14\n" +
+	"\x05nonce\x18\x01 \x01(\tR\x05nonce\"@\n" +
+	"\x17GetOrderHistoryResponse\x12%\n" +
+	"\x06orders\x18\x01 \x03(\v2\r.dex.v1.OrderR\x06orders\"M\n" +
+	"\x05Token\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x16\n" +
+	"\x06symbol\x18\x02 \x01(\tR\x06symbol\x12\x18\n" +
+	"\aaddress\x18\x03 \x01(\tR\aaddress\"\x13\n" +
+	"\x11ListTokensRequest\";\n" +
+	"\x12ListTokensResponse\x12%\n" +
+	"\x06tokens\x18\x01 \x03(\v2\r.dex.v1.TokenR\x06tokens\"@\n" +
+	"\x04Pair\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
+	"\x04base\x18\x02 \x01(\tR\x04base\x12\x14\n" +
+	"\x05quote\x18\x03 \x01(\tR\x05quote\"\x12\n" +
+	"\x10ListPairsRequest\"7\n" +
+	"\x11ListPairsResponse\x12\"\n" +
+	"\x05pairs\x18\x01 \x03(\v2\f.dex.v1.PairR\x05pairs\"{\n" +
+	"\x11StreamBookRequest\x12\x1b\n" +
+	"\tsymbol_in\x18\x01 \x01(\tR\bsymbolIn\x12\x1d\n" +
+	"\n" +
+	"symbol_out\x18\x02 \x01(\tR\tsymbolOut\x12\x14\n" +
+	"\x05group\x18\x03 \x01(\tR\x05group\x12\x14\n" +
+	"\x05depth\x18\x04 \x01(\rR\x05depth\"|\n" +
+	"\n" +
+	"DepthLevel\x12\x1b\n" +
+	"\tprice_raw\x18\x01 \x01(\tR\bpriceRaw\x12\x14\n" +
+	"\x05price\x18\x02 \x01(\x01R\x05price\x12\x1a\n" +
+	"\bquantity\x18\x03 \x01(\tR\bquantity\x12\x1f\n" +
+	"\vorder_count\x18\x04 \x01(\rR\n" +
+	"orderCount\"\xd8\x01\n" +
+	"\n" +
+	"BookUpdate\x12\x12\n" +
+	"\x04pair\x18\x01 \x01(\tR\x04pair\x12\x1a\n" +
+	"\bsnapshot\x18\x02 \x01(\bR\bsnapshot\x12\x10\n" +
+	"\x03seq\x18\x03 \x01(\x04R\x03seq\x12\x19\n" +
+	"\bprev_seq\x18\x04 \x01(\x04R\aprevSeq\x12&\n" +
+	"\x04bids\x18\x05 \x03(\v2\x12.dex.v1.DepthLevelR\x04bids\x12&\n" +
+	"\x04asks\x18\x06 \x03(\v2\x12.dex.v1.DepthLevelR\x04asks\x12\x1d\n" +
+	"\n" +
+	"last_price\x18\a \x01(\x01R\tlastPrice\"Q\n" +
+	"\x13StreamTradesRequest\x12\x1b\n" +
+	"\tsymbol_in\x18\x01 \x01(\tR\bsymbolIn\x12\x1d\n" +
+	"\n" +
+	"symbol_out\x18\x02 \x01(\tR\tsymbolOut\"\xb6\x03\n" +
+	"\x05Trade\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x04R\x02id\x12\x12\n" +
+	"\x04pair\x18\x02 \x01(\tR\x04pair\x12\x12\n" +
+	"\x04base\x18\x03 \x01(\tR\x04base\x12\x14\n" +
+	"\x05quote\x18\x04 \x01(\tR\x05quote\x12\x14\n" +
+	"\x05price\x18\x05 \x01(\tR\x05price\x12\x19\n" +
+	"\bbase_qty\x18\x06 \x01(\tR\abaseQty\x12\x1b\n" +
+	"\tquote_qty\x18\a \x01(\tR\bquoteQty\x123\n" +
+	"\x0eaggressor_side\x18\b \x01(\x0e2\f.dex.v1.SideR\raggressorSide\x12\x14\n" +
+	"\x05maker\x18\t \x01(\tR\x05maker\x12\x1f\n" +
+	"\vmaker_nonce\x18\n" +
+	" \x01(\tR\n" +
+	"makerNonce\x12\x14\n" +
+	"\x05taker\x18\v \x01(\tR\x05taker\x12\x1f\n" +
+	"\vtaker_nonce\x18\f \x01(\tR\n" +
+	"takerNonce\x12\x1b\n" +
+	"\tring_size\x18\r \x01(\rR\bringSize\x12\x17\n" +
+	"\atx_hash\x18\x0e \x01(\tR\x06txHash\x128\n" +
+	"\ttimestamp\x18\x0f \x01(\v2\x1a.google.protobuf.TimestampR\ttimestamp\"U\n" +
+	"\x18StreamOrderEventsRequest\x12\x14\n" +
+	"\x05epoch\x18\x01 \x01(\tR\x05epoch\x12\x19\n" +
+	"\x05since\x18\x02 \x01(\x04H\x00R\x05since\x88\x01\x01B\b\n" +
+	"\x06_since\"\xb5\x01\n" +
+	"\n" +
+	"OrderEvent\x12\x14\n" +
+	"\x05event\x18\x01 \x01(\tR\x05event\x12\x10\n" +
+	"\x03seq\x18\x02 \x01(\x04R\x03seq\x12\x14\n" +
+	"\x05epoch\x18\x03 \x01(\tR\x05epoch\x12#\n" +
+	"\x05order\x18\x04 \x01(\v2\r.dex.v1.OrderR\x05order\x12\x14\n" +
+	"\x05nonce\x18\x05 \x01(\tR\x05nonce\x12.\n" +
+	"\vopen_orders\x18\x06 \x03(\v2\r.dex.v1.OrderR\n" +
+	"openOrders*\xc4\x01\n" +
+	"\vOrderStatus\x12\x1c\n" +
+	"\x18ORDER_STATUS_UNSPECIFIED\x10\x00\x12\x19\n" +
+	"\x15ORDER_STATUS_MATCHING\x10\x01\x12%\n" +
+	"!ORDER_STATUS_PENDING_CONFIRMATION\x10\x02\x12\x1a\n" +
+	"\x16ORDER_STATUS_COMPLETED\x10\x03\x12\x1a\n" +
+	"\x16ORDER_STATUS_CANCELLED\x10\x04\x12\x1d\n" +
+	"\x19ORDER_STATUS_PARTIAL_FILL\x10\x05*9\n" +
+	"\x04Side\x12\x14\n" +
+	"\x10SIDE_UNSPECIFIED\x10\x00\x12\f\n" +
+	"\bSIDE_BUY\x10\x01\x12\r\n" +
+	"\tSIDE_SELL\x10\x022\x88\x05\n" +
+	"\x03Dex\x12F\n" +
+	"\vSubmitOrder\x12\x1a.dex.v1.SubmitOrderRequest\x1a\x1b.dex.v1.SubmitOrderResponse\x12F\n" +
+	"\vCancelOrder\x12\x1a.dex.v1.CancelOrderRequest\x1a\x1b.dex.v1.CancelOrderResponse\x12L\n" +
+	"\rGetOpenOrders\x12\x1c.dex.v1.GetOpenOrdersRequest\x1a\x1d.dex.v1.GetOpenOrdersResponse\x12R\n" +
+	"\x0fGetOrderHistory\x12\x1e.dex.v1.GetOrderHistoryRequest\x1a\x1f.dex.v1.GetOrderHistoryResponse\x12C\n" +
+	"\n" +
+	"ListTokens\x12\x19.dex.v1.ListTokensRequest\x1a\x1a.dex.v1.ListTokensResponse\x12@\n" +
+	"\tListPairs\x12\x18.dex.v1.ListPairsRequest\x1a\x19.dex.v1.ListPairsResponse\x12=\n" +
+	"\n" +
+	"StreamBook\x12\x19.dex.v1.StreamBookRequest\x1a\x12.dex.v1.BookUpdate0\x01\x12<\n" +
+	"\fStreamTrades\x12\x1b.dex.v1.StreamTradesRequest\x1a\r.dex.v1.Trade0\x01\x12K\n" +
+	"\x11StreamOrderEvents\x12 .dex.v1.StreamOrderEventsRequest\x1a\x12.dex.v1.OrderEvent0\x01B\x13Z\x11dexbe/proto/dexpbb\x06proto3"
+
+var (
+	file_dex_proto_rawDescOnce sync.Once
+	file_dex_proto_rawDescData []byte
+)
+
+func file_dex_proto_rawDescGZIP() []byte {
+	file_dex_proto_rawDescOnce.Do(func() {
+		file_dex_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_dex_proto_rawDesc), len(file_dex_proto_rawDesc)))
+	})
+	return file_dex_proto_rawDescData
+}
+
+var file_dex_proto_enumTypes = make([]protoimpl.EnumInfo, 2)
+var file_dex_proto_msgTypes = make([]protoimpl.MessageInfo, 23)
+var file_dex_proto_goTypes = []any{
+	(OrderStatus)(0),                 // 0: dex.v1.OrderStatus
+	(Side)(0),                        // 1: dex.v1.Side
+	(*SignedOrder)(nil),              // 2: dex.v1.SignedOrder
+	(*Order)(nil),                    // 3: dex.v1.Order
+	(*SubmitOrderRequest)(nil),       // 4: dex.v1.SubmitOrderRequest
+	(*SubmitOrderResponse)(nil),      // 5: dex.v1.SubmitOrderResponse
+	(*CancelOrderRequest)(nil),       // 6: dex.v1.CancelOrderRequest
+	(*CancelOrderResponse)(nil),      // 7: dex.v1.CancelOrderResponse
+	(*GetOpenOrdersRequest)(nil),     // 8: dex.v1.GetOpenOrdersRequest
+	(*GetOpenOrdersResponse)(nil),    // 9: dex.v1.GetOpenOrdersResponse
+	(*GetOrderHistoryRequest)(nil),   // 10: dex.v1.GetOrderHistoryRequest
+	(*GetOrderHistoryResponse)(nil),  // 11: dex.v1.GetOrderHistoryResponse
+	(*Token)(nil),                    // 12: dex.v1.Token
+	(*ListTokensRequest)(nil),        // 13: dex.v1.ListTokensRequest
+	(*ListTokensResponse)(nil),       // 14: dex.v1.ListTokensResponse
+	(*Pair)(nil),                     // 15: dex.v1.Pair
+	(*ListPairsRequest)(nil),         // 16: dex.v1.ListPairsRequest
+	(*ListPairsResponse)(nil),        // 17: dex.v1.ListPairsResponse
+	(*StreamBookRequest)(nil),        // 18: dex.v1.StreamBookRequest
+	(*DepthLevel)(nil),               // 19: dex.v1.DepthLevel
+	(*BookUpdate)(nil),               // 20: dex.v1.BookUpdate
+	(*StreamTradesRequest)(nil),      // 21: dex.v1.StreamTradesRequest
+	(*Trade)(nil),                    // 22: dex.v1.Trade
+	(*StreamOrderEventsRequest)(nil), // 23: dex.v1.StreamOrderEventsRequest
+	(*OrderEvent)(nil),               // 24: dex.v1.OrderEvent
+	(*timestamppb.Timestamp)(nil),    // 25: google.protobuf.Timestamp
+}
+var file_dex_proto_depIdxs = []int32{
+	0,  // 0: dex.v1.Order.status:type_name -> dex.v1.OrderStatus
+	3,  // 1: dex.v1.Order.conditional_order:type_name -> dex.v1.Order
+	25, // 2: dex.v1.Order.created_at:type_name -> google.protobuf.Timestamp
+	2,  // 3: dex.v1.SubmitOrderRequest.order:type_name -> dex.v1.SignedOrder
+	3,  // 4: dex.v1.SubmitOrderResponse.order:type_name -> dex.v1.Order
+	3,  // 5: dex.v1.GetOpenOrdersResponse.orders:type_name -> dex.v1.Order
+	3,  // 6: dex.v1.GetOrderHistoryResponse.orders:type_name -> dex.v1.Order
+	12, // 7: dex.v1.ListTokensResponse.tokens:type_name -> dex.v1.Token
+	15, // 8: dex.v1.ListPairsResponse.pairs:type_name -> dex.v1.Pair
+	19, // 9: dex.v1.BookUpdate.bids:type_name -> dex.v1.DepthLevel
+	19, // 10: dex.v1.BookUpdate.asks:type_name -> dex.v1.DepthLevel
+	1,  // 11: dex.v1.Trade.aggressor_side:type_name -> dex.v1.Side
+	25, // 12: dex.v1.Trade.timestamp:type_name -> google.protobuf.Timestamp
+	3,  // 13: dex.v1.OrderEvent.order:type_name -> dex.v1.Order
+	3,  // 14: dex.v1.OrderEvent.open_orders:type_name -> dex.v1.Order
+	4,  // 15: dex.v1.Dex.SubmitOrder:input_type -> dex.v1.SubmitOrderRequest
+	6,  // 16: dex.v1.Dex.CancelOrder:input_type -> dex.v1.CancelOrderRequest
+	8,  // 17: dex.v1.Dex.GetOpenOrders:input_type -> dex.v1.GetOpenOrdersRequest
+	10, // 18: dex.v1.Dex.GetOrderHistory:input_type -> dex.v1.GetOrderHistoryRequest
+	13, // 19: dex.v1.Dex.ListTokens:input_type -> dex.v1.ListTokensRequest
+	16, // 20: dex.v1.Dex.ListPairs:input_type -> dex.v1.ListPairsRequest
+	18, // 21: dex.v1.Dex.StreamBook:input_type -> dex.v1.StreamBookRequest
+	21, // 22: dex.v1.Dex.StreamTrades:input_type -> dex.v1.StreamTradesRequest
+	23, // 23: dex.v1.Dex.StreamOrderEvents:input_type -> dex.v1.StreamOrderEventsRequest
+	5,  // 24: dex.v1.Dex.SubmitOrder:output_type -> dex.v1.SubmitOrderResponse
+	7,  // 25: dex.v1.Dex.CancelOrder:output_type -> dex.v1.CancelOrderResponse
+	9,  // 26: dex.v1.Dex.GetOpenOrders:output_type -> dex.v1.GetOpenOrdersResponse
+	11, // 27: dex.v1.Dex.GetOrderHistory:output_type -> dex.v1.GetOrderHistoryResponse
+	14, // 28: dex.v1.Dex.ListTokens:output_type -> dex.v1.ListTokensResponse
+	17, // 29: dex.v1.Dex.ListPairs:output_type -> dex.v1.ListPairsResponse
+	20, // 30: dex.v1.Dex.StreamBook:output_type -> dex.v1.BookUpdate
+	22, // 31: dex.v1.Dex.StreamTrades:output_type -> dex.v1.Trade
+	24, // 32: dex.v1.Dex.StreamOrderEvents:output_type -> dex.v1.OrderEvent
+	24, // [24:33] is the sub-list for method output_type
+	15, // [15:24] is the sub-list for method input_type
+	15, // [15:15] is the sub-list for extension type_name
+	15, // [15:15] is the sub-list for extension extendee
+	0,  // [0:15] is the sub-list for field type_name
+}
+
+func init() { file_dex_proto_init() }
+func file_dex_proto_init() {
+	if File_dex_proto != nil {
+		return
+	}
+	file_dex_proto_msgTypes[21].OneofWrappers = []any{}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_dex_proto_rawDesc), len(file_dex_proto_rawDesc)),
+			NumEnums:      2,
+			NumMessages:   23,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_dex_proto_goTypes,
+		DependencyIndexes: file_dex_proto_depIdxs,
+		EnumInfos:         file_dex_proto_enumTypes,
+		MessageInfos:      file_dex_proto_msgTypes,
+	}.Build()
+	File_dex_proto = out.File
+	file_dex_proto_goTypes = nil
+	file_dex_proto_depIdxs = nil
+}
@@ -2,7 +2,7 @@ import { useEffect, useState, useRef, useCallback } from "react";
 import { ethers } from "ethers";
 import { readProvider } from "./lib/eth";
 import { connectWallet, switchOrAddNetwork } from "./lib/wallet";
-import { getNextNonce, signOrder, submitLimitOrderToBackend, openOrderWs, getOrdersByAddress, cancelOrder, getOrderBookNormalized, normalizeOrderBook, EXCHANGE_ADDRESS, getPastHistory } from "./lib/api";
+import { getNextNonce, signOrder, submitLimitOrderToBackend, openOrderWs, getOrdersByAddress, cancelOrder, signCancel, getOrderBookNormalized, normalizeOrderBook, depthFromSnapshot, applyDepth, depthToRaw, type DepthState, EXCHANGE_ADDRESS, getPastHistory, getSessionToken } from "./lib/api";
 // import MapTokenModal from "./components/MapTokenModal";
 import IssueTokenModal from "./components/IssueTokenModal";
 import { motion, AnimatePresence } from "framer-motion";
@@ -138,23 +138,39 @@ useEffect(() => {
     }
   };
   
+  let depth: DepthState | null = null;
+
   ws.onmessage = (event) => {
     try {
       const msg = JSON.parse(event.data);
+      if (msg.event === "Snapshot") {
+        depth = depthFromSnapshot(msg);
+      } else if (msg.event === "Depth") {
+        if (!depth) return; // waiting for a snapshot
+        if (!applyDepth(depth, msg)) {
+          console.warn(`Orderbook gap after seq ${depth.seq}, requesting snapshot`);
+          depth = null;
+          ws.send(JSON.stringify({ type: "snapshot" }));
+          return;
+        }
+      } else {
+        return;
+      }
+      const book = depthToRaw(depth);
       let lastPriceToSet = "-"; // default if invalid
-      if (msg.lastPrice && Number(msg.lastPrice) > 0) {
+      if (book.lastPrice && Number(book.lastPrice) > 0) {
         const baseSymbol = availableTokens.find(t => t.address === baseToken)?.symbol;
         // Check if we need to invert based on whether symbols match AND isSwapped state
-        if (msg.symbolIn === baseSymbol && !isSwapped) {
-          lastPriceToSet = msg.lastPrice;
-        } else if (msg.symbolIn !== baseSymbol && isSwapped) {
-          lastPriceToSet = msg.lastPrice;
+        if (book.symbolIn === baseSymbol && !isSwapped) {
+          lastPriceToSet = String(book.lastPrice);
+        } else if (book.symbolIn !== baseSymbol && isSwapped) {
+          lastPriceToSet = String(book.lastPrice);
         } else {
-          lastPriceToSet = (1 / Number(msg.lastPrice)).toFixed(2); // invert price for reversed pair
+          lastPriceToSet = (1 / Number(book.lastPrice)).toFixed(2); // invert price for reversed pair
         }
       }
       setLastPrice(lastPriceToSet);
-      const normalized = normalizeOrderBook(msg.data, tokenIn);
+      const normalized = normalizeOrderBook(book, tokenIn);
       setOrderBook(normalized as any);
     } catch (err) {
       console.error("WS parse error", err);
@@ -328,9 +344,14 @@ useEffect(() => {
     const wsNotify = new WebSocket(`ws://${backendUrl}/ws`);
     wsNotifyRef.current = wsNotify;
 
-    wsNotify.onopen = () => {
+    wsNotify.onopen = async () => {
       console.log("Notification WS connected for account:", primary);
-      wsNotify.send(JSON.stringify({ address : primary }));
+      try {
+        const token = await getSessionToken(primary);
+        wsNotify.send(JSON.stringify({ token }));
+      } catch (e) {
+        console.warn("Notification WS subscribe failed", e);
+      }
     };
 
     wsNotify.onmessage = (event) => {
@@ -507,9 +528,14 @@ async function onConnect() {
     const wsNotify = new WebSocket(`ws://${backendUrl}/ws`);
     wsNotifyRef.current = wsNotify;
 
-    wsNotify.onopen = () => {
+    wsNotify.onopen = async () => {
       console.log("Notification WS connected for account:", address);
-      wsNotify.send(JSON.stringify({ address: address }));
+      try {
+        const token = await getSessionToken(address);
+        wsNotify.send(JSON.stringify({ token }));
+      } catch (e) {
+        console.warn("Notification WS subscribe failed", e);
+      }
     };
 
     wsNotify.onmessage = (event) => {
@@ -861,7 +887,11 @@ async function handleOrderSubmit(details: TokenQuantity) {
     const confirmation = confirm(`Delete order with nonce ${order.nonce}?`);
     if (!confirmation) return;
     try {
-      await cancelOrder(order);
+      const provider = new ethers.BrowserProvider((window as any).ethereum);
+      const signer = await provider.getSigner();
+      const network = await provider.getNetwork();
+      const signature = await signCancel(signer, Number(network.chainId), order.createdBy, order.nonce.toString());
+      await cancelOrder(order, signature);
       // setOrders((cur) => cur.filter((local) => String(local.id) !== String(req.nonce)));
     } catch (e: any) {
       alert(e?.message || e);
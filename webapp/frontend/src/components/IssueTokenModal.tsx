@@ -44,7 +44,7 @@ export default function IssueTokenModal({
       const addr = typeof (contract as any).getAddress === "function" ? await (contract as any).getAddress() : (contract as any).target || (contract as any).address;
       console.log(`Name: ${name} Symbol: ${symbol} Address: ${addr}`)
       try {
-        await addToken(name, symbol, addr)
+        await addToken(name, symbol, addr, await signer.getAddress())
         onDeployed({ address: addr, symbol, name });
         alert(`Token deployed at ${addr}`)
       } catch {
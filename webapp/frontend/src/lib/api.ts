@@ -1,9 +1,59 @@
-import { type Signer } from "ethers";
+import { ethers, type Signer } from "ethers";
 import type { Order } from "../App";
 
 const BACKEND = "http://" + (import.meta as any).env.VITE_BACKEND_URL || "http://localhost:8080";
 const EXCHANGE_ADDRESS = (import.meta as any).env.VITE_EXCHANGE || "";
 
+// Sign-In with Ethereum sessions, cached per address for private endpoints and the /ws feed
+const sessions = new Map<string, { token: string; expiresAt: number }>();
+const pendingSignIns = new Map<string, Promise<string>>();
+
+export async function signIn(address: string) {
+  const challengeRes = await fetch(`${BACKEND}/auth/challenge`, {
+    method: "POST",
+    headers: { "Content-Type": "application/json" },
+    body: JSON.stringify({ address }),
+  });
+  if (!challengeRes.ok) throw new Error("Failed to get sign-in challenge");
+  const { message } = await challengeRes.json();
+
+  const provider = new ethers.BrowserProvider((window as any).ethereum);
+  const signer = await provider.getSigner(address);
+  const signature = await signer.signMessage(message);
+
+  const loginRes = await fetch(`${BACKEND}/auth/login`, {
+    method: "POST",
+    headers: { "Content-Type": "application/json" },
+    body: JSON.stringify({ message, signature }),
+  });
+  if (!loginRes.ok) {
+    const txt = await loginRes.text();
+    throw new Error(`Sign-in failed: ${loginRes.status} ${txt}`);
+  }
+  const session = await loginRes.json();
+  const entry = { token: session.token as string, expiresAt: Date.parse(session.expiresAt) };
+  sessions.set(address.toLowerCase(), entry);
+  return entry.token;
+}
+
+export async function getSessionToken(address: string) {
+  const cached = sessions.get(address.toLowerCase());
+  if (cached && cached.expiresAt > Date.now()) return cached.token;
+  // Share one wallet prompt between concurrent callers
+  const key = address.toLowerCase();
+  let pending = pendingSignIns.get(key);
+  if (!pending) {
+    pending = signIn(address).finally(() => pendingSignIns.delete(key));
+    pendingSignIns.set(key, pending);
+  }
+  return await pending;
+}
+
+async function authHeaders(address: string) {
+  const token = await getSessionToken(address);
+  return { "Content-Type": "application/json", Authorization: `Bearer ${token}` };
+}
+
 export async function getNextNonce(address: string) {
   const res = await fetch(`${BACKEND}/nonce`, {
     method: "POST",
@@ -128,7 +178,7 @@ function keysToCamel<T>(obj: any): T {
 export async function getOrdersByAddress(address: string) {
   const res = await fetch(`${BACKEND}/order/${address}`, {
     method: "GET",
-    headers: { "Content-Type": "application/json" },
+    headers: await authHeaders(address),
   });
 
   if (!res.ok) {
@@ -167,13 +217,42 @@ export async function getOrdersByAddress(address: string) {
 }
 
 
-export async function cancelOrder(orderReq: Order) {
+export async function signCancel(
+  signer: Signer,
+  chainId: number,
+  createdBy: string,
+  nonce: bigint | string
+) {
+  const domain = {
+    name: "SmashDEX",
+    version: "1",
+    chainId,
+    verifyingContract: EXCHANGE_ADDRESS,
+  };
+
+  const types = {
+    Cancel: [
+      { name: "createdBy", type: "address" },
+      { name: "nonce", type: "uint256" },
+    ],
+  };
+
+  const value = {
+    createdBy,
+    nonce: BigInt(nonce),
+  };
+
+  return await signer.signTypedData(domain, types, value);
+}
+
+export async function cancelOrder(orderReq: Order, signature: string) {
   const values = {
     createdBy: orderReq.createdBy,
     nonce: orderReq.nonce,
     limitPrice: orderReq.limitPrice,
     symbolIn: orderReq.symbolIn,
-    symbolOut: orderReq.symbolOut
+    symbolOut: orderReq.symbolOut,
+    signature: signature,
   }
   const res = await fetch(`${BACKEND}/order`, {
     method: "DELETE",
@@ -259,21 +338,79 @@ export function normalizeOrderBook(raw: any, symbolIn: string) {
   };
 }
 
+// Local copy of a book kept in sync from the L2 feed: a Snapshot followed by Depth deltas.
+// Levels are keyed by the exact priceRaw; a quantity of "0" removes the level.
+export type DepthState = {
+  seq: number;
+  symbolIn: string;
+  symbolOut: string;
+  lastPrice: number;
+  bids: Map<string, any>;
+  asks: Map<string, any>;
+};
+
+export function depthFromSnapshot(msg: any): DepthState {
+  return {
+    seq: Number(msg.seq),
+    symbolIn: msg.symbolIn,
+    symbolOut: msg.symbolOut,
+    lastPrice: Number(msg.lastPrice) || 0,
+    bids: new Map((msg.bids || []).map((l: any) => [l.priceRaw, l])),
+    asks: new Map((msg.asks || []).map((l: any) => [l.priceRaw, l])),
+  };
+}
+
+// Applies a Depth message. Returns false when a message was missed and a new snapshot is needed.
+export function applyDepth(state: DepthState, msg: any): boolean {
+  if (Number(msg.seq) <= state.seq) return true; // already covered by the snapshot
+  if (Number(msg.prevSeq) !== state.seq) return false;
+  for (const [side, levels] of [[state.bids, msg.data?.bids], [state.asks, msg.data?.asks]] as const) {
+    for (const l of levels || []) {
+      if (l.quantity === "0") side.delete(l.priceRaw);
+      else side.set(l.priceRaw, l);
+    }
+  }
+  state.seq = Number(msg.seq);
+  state.lastPrice = Number(msg.lastPrice) || state.lastPrice;
+  return true;
+}
+
+// Returns the book in the raw snapshot layout expected by normalizeOrderBook
+export function depthToRaw(state: DepthState) {
+  const byPrice = (desc: boolean) => (a: any, b: any) => {
+    const diff = BigInt(a.priceRaw) - BigInt(b.priceRaw);
+    const sign = diff > 0n ? 1 : diff < 0n ? -1 : 0;
+    return desc ? -sign : sign;
+  };
+  return {
+    symbolIn: state.symbolIn,
+    symbolOut: state.symbolOut,
+    lastPrice: state.lastPrice,
+    bids: [...state.bids.values()].sort(byPrice(true)),
+    asks: [...state.asks.values()].sort(byPrice(false)),
+  };
+}
+
 export async function getOrderBookNormalized(symbolIn: string, symbolOut: string) {
   const raw = await getOrderBook(symbolIn, symbolOut);
   return normalizeOrderBook(raw, symbolIn);
 }
 
-export function openOrderWs(address: string, onMessage: (msg: unknown) => void) {
+// Position in the per-address event stream of /ws, kept across reconnects so missed events are
+// replayed. Events at or before seq are duplicates; a "Resync" replaces the open orders wholesale.
+export type OrderWsCursor = { epoch?: string; seq?: number };
+
+export function openOrderWs(address: string, onMessage: (msg: unknown) => void, cursor: OrderWsCursor = {}) {
   const url = new URL(BACKEND);
   const wsproto = url.protocol === "https:" ? "wss:" : "ws:";
   const host = url.host;
   const path = url.pathname.replace(/\/$/, "");
   const ws = new WebSocket(`${wsproto}//${host}${path}/ws`);
 
-  ws.onopen = () => {
+  ws.onopen = async () => {
     try {
-      ws.send(JSON.stringify({ address }));
+      const token = await getSessionToken(address);
+      ws.send(JSON.stringify({ token, epoch: cursor.epoch, since: cursor.seq }));
     } catch (e) {
       console.warn("ws send subscribe failed", e);
     }
@@ -281,6 +418,13 @@ export function openOrderWs(address: string, onMessage: (msg: unknown) => void)
   ws.onmessage = (ev) => {
     try {
       const msg = JSON.parse(ev.data as string);
+      if (msg.event === "Subscribed" || msg.event === "Resync") {
+        cursor.epoch = msg.data.epoch;
+        cursor.seq = msg.data.seq;
+      } else if (typeof msg.seq === "number") {
+        if (cursor.seq !== undefined && msg.seq <= cursor.seq) return;
+        cursor.seq = msg.seq;
+      }
       onMessage(msg);
     } catch (e) {
       console.error("bad ws message", e);
@@ -294,7 +438,7 @@ export function openOrderWs(address: string, onMessage: (msg: unknown) => void)
 export async function getPastHistory(address: string) {
   const res = await fetch(`${BACKEND}/order/history/${address}`, {
     method: "GET",
-    headers: { "Content-Type": "application/json" },
+    headers: await authHeaders(address),
   });
   if (!res.ok) {
     const txt = await res.text();
@@ -305,7 +449,8 @@ export async function getPastHistory(address: string) {
 
 export { BACKEND, EXCHANGE_ADDRESS };
 
-export async function addToken(name: string, symbol: string, address: string) {
+// Requires the caller to be a backend admin (registry/exchange owner or configured admin)
+export async function addToken(name: string, symbol: string, address: string, admin: string) {
   const req = {
     name: name,
     symbol: symbol,
@@ -314,7 +459,7 @@ export async function addToken(name: string, symbol: string, address: string) {
   
   const res = await fetch(`${BACKEND}/token/add`, {
     method: "POST",
-    headers: { "Content-Type": "application/json" },
+    headers: await authHeaders(admin),
     body: JSON.stringify(req),
   });
   if (!res.ok) {